@@ -0,0 +1,481 @@
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"odoo-efor-tracker/odoo"
+)
+
+// Alt komutlar; ilk argüman bunlardan biriyse rapor yerine alt komut çalışır
+var subcommands = map[string]func(args []string) error{
+	"entries": runEntriesCommand,
+	"edit":    runEditCommand,
+	"delete":  runDeleteCommand,
+	"import":  runImportCommand,
+	"commits": runCommitsCommand,
+	"start":   runStartCommand,
+	"stop":    runStopCommand,
+	"timer":   runTimerCommand,
+}
+
+// Komut satırı girdisi ve çıktısı; testlerde değiştirilir
+var (
+	stdin  io.Reader = os.Stdin
+	stdout io.Writer = os.Stdout
+)
+
+// Alt komut adları
+func subcommandNames() string {
+	names := make([]string, 0, len(subcommands))
+	for name := range subcommands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return strings.Join(names, ", ")
+}
+
+// Komut satırı kullanıcısı. Komut satırı Odoo servis hesabıyla çalışır ve
+// tüm kayıtlara erişebilir; employee boşsa Odoo kullanıcısına bağlı çalışan
+// kullanılır.
+func cliUser(employee string) *BotUser {
+	return &BotUser{Employee: employee, Role: RoleAdmin}
+}
+
+// Kullanıcıdan evet/hayır onayı al
+func confirm(prompt string) bool {
+	fmt.Fprintf(stdout, "%s [e/H]: ", prompt)
+	answer, _ := bufio.NewReader(stdin).ReadString('\n')
+	switch strings.ToLower(strings.TrimSpace(answer)) {
+	case "e", "evet", "y", "yes":
+		return true
+	}
+	return false
+}
+
+// entries: çalışanın son kayıtlarını ID'leriyle listele
+func runEntriesCommand(args []string) error {
+	fs := flag.NewFlagSet("entries", flag.ContinueOnError)
+	employee := fs.String("employee", "", "Çalışan adı ya da ID'si (boş bırakılırsa Odoo kullanıcısına bağlı çalışan)")
+	days := fs.Int("days", recentEntryDays, "Kaç gün geriye bakılacağı")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	client, err := authenticateOdoo()
+	if err != nil {
+		return fmt.Errorf("Odoo kimlik doğrulama hatası: %v", err)
+	}
+	defer client.Close()
+
+	employeeID, err := userEmployeeID(client, cliUser(*employee))
+	if err != nil {
+		return err
+	}
+	lines, err := recentEntries(client, employeeID, *days, time.Now())
+	if err != nil {
+		return fmt.Errorf("kayıtlar okunamadı: %v", err)
+	}
+	if len(lines) == 0 {
+		fmt.Fprintf(stdout, "Son %d günde kayıt yok.\n", *days)
+		return nil
+	}
+	for _, line := range lines {
+		fmt.Fprintln(stdout, formatEntry(line))
+	}
+	return nil
+}
+
+// edit: kaydın saatini, açıklamasını ya da görevini değiştir
+func runEditCommand(args []string) error {
+	fs := flag.NewFlagSet("edit", flag.ContinueOnError)
+	id := fs.Int64("id", 0, "Düzenlenecek kaydın ID'si")
+	hours := fs.String("hours", "", "Yeni saat")
+	description := fs.String("description", "", "Yeni açıklama")
+	task := fs.String("task", "", "Yeni görev (ad, kod ya da #ID; görevi kaldırmak için -)")
+	yes := fs.Bool("yes", false, "Onay sormadan uygula")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *id <= 0 {
+		return fmt.Errorf("-id gerekli")
+	}
+
+	// Yalnızca verilen bayraklar değişikliğe dahil edilir
+	var changes EntryChanges
+	var err error
+	fs.Visit(func(f *flag.Flag) {
+		var c EntryChanges
+		if err != nil {
+			return
+		}
+		switch f.Name {
+		case "hours":
+			if c, err = parseEntryChange("saat", *hours); err == nil {
+				changes.Hours = c.Hours
+			}
+		case "description":
+			if c, err = parseEntryChange("açıklama", *description); err == nil {
+				changes.Description = c.Description
+			}
+		case "task":
+			changes.Task = task
+		}
+	})
+	if err != nil {
+		return err
+	}
+	if changes.Empty() {
+		return fmt.Errorf("değiştirilecek alan yok (-hours, -description, -task)")
+	}
+
+	client, err := authenticateOdoo()
+	if err != nil {
+		return fmt.Errorf("Odoo kimlik doğrulama hatası: %v", err)
+	}
+	defer client.Close()
+
+	line, err := loadEditableEntry(client, cliUser(""), *id)
+	if err != nil {
+		return err
+	}
+	values, notes, err := entryChangeValues(client, line, changes)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintln(stdout, formatEntry(*line))
+	for _, note := range notes {
+		fmt.Fprintln(stdout, "  "+note)
+	}
+	if !*yes && !confirm("Kayıt güncellensin mi?") {
+		fmt.Fprintln(stdout, "İptal edildi.")
+		return nil
+	}
+	if err := client.Write(odoo.ModelTimesheet, []int64{*id}, values); err != nil {
+		return fmt.Errorf("kayıt güncellenemedi: %v", err)
+	}
+	fmt.Fprintf(stdout, "#%d güncellendi.\n", *id)
+	return nil
+}
+
+// delete: kaydı sil
+func runDeleteCommand(args []string) error {
+	fs := flag.NewFlagSet("delete", flag.ContinueOnError)
+	id := fs.Int64("id", 0, "Silinecek kaydın ID'si")
+	yes := fs.Bool("yes", false, "Onay sormadan sil")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *id <= 0 {
+		return fmt.Errorf("-id gerekli")
+	}
+
+	client, err := authenticateOdoo()
+	if err != nil {
+		return fmt.Errorf("Odoo kimlik doğrulama hatası: %v", err)
+	}
+	defer client.Close()
+
+	line, err := loadEditableEntry(client, cliUser(""), *id)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintln(stdout, formatEntry(*line))
+	if !*yes && !confirm("Kayıt silinsin mi?") {
+		fmt.Fprintln(stdout, "İptal edildi.")
+		return nil
+	}
+	if err := client.Unlink(odoo.ModelTimesheet, []int64{*id}); err != nil {
+		return fmt.Errorf("kayıt silinemedi: %v", err)
+	}
+	fmt.Fprintf(stdout, "#%d silindi.\n", *id)
+	return nil
+}
+
+// importOptions, kayıtları toplu ekleyen komutların ortak bayrakları
+type importOptions struct {
+	employee    *string
+	dryRun      *bool
+	skipInvalid *bool
+	batchSize   *int
+	yes         *bool
+}
+
+func addImportFlags(fs *flag.FlagSet) importOptions {
+	return importOptions{
+		employee:    fs.String("employee", "", "Çalışanı belirtilmeyen satırlar için çalışan adı ya da ID'si (boş bırakılırsa Odoo kullanıcısına bağlı çalışan)"),
+		dryRun:      fs.Bool("dry-run", false, "Yalnızca eklenecek kayıtları göster"),
+		skipInvalid: fs.Bool("skip-invalid", false, "Hatalı satırları atlayıp geçerli satırları ekle"),
+		batchSize:   fs.Int("batch", importBatchSize, "Tek seferde oluşturulacak kayıt sayısı"),
+		yes:         fs.Bool("yes", false, "Onay sormadan ekle"),
+	}
+}
+
+// import: CSV ya da JSON dosyasındaki kayıtları toplu olarak ekle
+func runImportCommand(args []string) error {
+	fs := flag.NewFlagSet("import", flag.ContinueOnError)
+	file := fs.String("file", "", "İçe aktarılacak .csv ya da .json dosyası")
+	format := fs.String("format", "", "Dosyayı dışa aktaran araç: toggl, clockify, harvest (boşsa içe aktarma biçimi)")
+	opts := addImportFlags(fs)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *file == "" && fs.NArg() > 0 {
+		*file = fs.Arg(0)
+	}
+	if *file == "" {
+		return fmt.Errorf("-file gerekli")
+	}
+
+	rules, err := validationRulesFromEnv()
+	if err != nil {
+		return err
+	}
+	var rows []ImportRow
+	if *format == "" {
+		rows, err = readImportFile(*file)
+	} else {
+		rows, err = readTrackerRows(*format, *file, rules.HoursStep)
+	}
+	if err != nil {
+		return err
+	}
+	if len(rows) == 0 {
+		return fmt.Errorf("%s içinde kayıt yok", *file)
+	}
+	return opts.run(rows, rules)
+}
+
+// Satırları doğrula, planı göster ve onay alındıktan sonra kayıtları oluştur
+func (o importOptions) run(rows []ImportRow, rules ValidationRules) error {
+	client, err := authenticateOdoo()
+	if err != nil {
+		return fmt.Errorf("Odoo kimlik doğrulama hatası: %v", err)
+	}
+	defer client.Close()
+
+	// Varsayılan çalışan yalnızca çalışanı belirtilmeyen satır varsa gerekir
+	var defaultEmployee *odoo.Employee
+	for _, row := range rows {
+		if row.Employee == "" {
+			id, err := userEmployeeID(client, cliUser(*o.employee))
+			if err != nil {
+				return err
+			}
+			var found []odoo.Employee
+			if err := client.Read(odoo.ModelEmployee, []int64{id}, nil, &found); err != nil {
+				return fmt.Errorf("çalışan okunamadı: %v", err)
+			}
+			if len(found) == 0 {
+				return fmt.Errorf("çalışan bulunamadı: #%d", id)
+			}
+			defaultEmployee = &found[0]
+			break
+		}
+	}
+
+	items := prepareImport(client, rows, defaultEmployee, rules, time.Now())
+	writeImportReport(stdout, items)
+
+	invalid, pending := 0, 0
+	for _, item := range items {
+		if item.Err != nil {
+			invalid++
+		} else if item.Pending() {
+			pending++
+		}
+	}
+	if invalid > 0 && !*o.skipInvalid {
+		return fmt.Errorf("%d satır hatalı, hiçbir kayıt eklenmedi (hatalı satırları atlamak için -skip-invalid)", invalid)
+	}
+	if *o.dryRun || pending == 0 {
+		return nil
+	}
+	if !*o.yes && !confirm(fmt.Sprintf("%d kayıt eklensin mi?", pending)) {
+		fmt.Fprintln(stdout, "İptal edildi.")
+		return nil
+	}
+
+	createImportItems(client, items, *o.batchSize)
+	fmt.Fprintln(stdout)
+	writeImportReport(stdout, items)
+	failed := -invalid
+	for _, item := range items {
+		if item.Err != nil {
+			failed++
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d kayıt eklenemedi", failed)
+	}
+	return nil
+}
+
+// commits: git commitlerinden taslak kayıtlar üret ve gözden geçirildikten
+// sonra ekle
+func runCommitsCommand(args []string) error {
+	fs := flag.NewFlagSet("commits", flag.ContinueOnError)
+	dateSpec := fs.String("date", "", "Dönem ("+periodHelp+"; boşsa ayın başından bugüne)")
+	from := fs.String("from", "", "Başlangıç tarihi (YYYY-MM-DD)")
+	to := fs.String("to", "", "Bitiş tarihi (YYYY-MM-DD, boş bırakılırsa bugün)")
+	author := fs.String("author", "", "Commit yazarı (boşsa repos.yaml'daki author ya da deponun user.email ayarı)")
+	out := fs.String("out", "", "Taslakları düzenlemek için içe aktarma biçiminde CSV dosyasına yaz")
+	opts := addImportFlags(fs)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	period, err := resolvePeriod(*dateSpec, *from, *to, time.Now())
+	if err != nil {
+		return err
+	}
+	cfg, err := loadReposConfig()
+	if err != nil {
+		return err
+	}
+	if len(cfg.Repos) == 0 {
+		return fmt.Errorf("eşlenmiş depo yok; depoları REPOS_FILE (varsayılan %s) dosyasında tanımlayın", DefaultReposFile)
+	}
+	rules, err := validationRulesFromEnv()
+	if err != nil {
+		return err
+	}
+
+	drafts, err := scanCommitDrafts(cfg, *author, period, rules.HoursStep)
+	if err != nil {
+		return err
+	}
+	if len(drafts) == 0 {
+		fmt.Fprintf(stdout, "%s döneminde commit bulunamadı.\n", period)
+		return nil
+	}
+	rows := make([]ImportRow, 0, len(drafts))
+	for i, d := range drafts {
+		rows = append(rows, d.row(i+1))
+	}
+
+	if *out != "" {
+		f, err := os.Create(*out)
+		if err != nil {
+			return fmt.Errorf("taslak dosyası oluşturulamadı: %v", err)
+		}
+		if err := writeImportCSV(f, rows); err != nil {
+			f.Close()
+			return fmt.Errorf("taslak dosyası yazılamadı: %v", err)
+		}
+		if err := f.Close(); err != nil {
+			return fmt.Errorf("taslak dosyası yazılamadı: %v", err)
+		}
+		fmt.Fprintf(stdout, "%d taslak kayıt %s dosyasına yazıldı. Düzenledikten sonra eklemek için: import -file %s\n",
+			len(rows), *out, *out)
+		return nil
+	}
+	return opts.run(rows, rules)
+}
+
+// start: zamanlayıcıyı başlat
+func runStartCommand(args []string) error {
+	fs := flag.NewFlagSet("start", flag.ContinueOnError)
+	project := fs.String("project", "", "Proje adı, kodu ya da #ID")
+	task := fs.String("task", "", "Görev adı, kodu ya da #ID")
+	description := fs.String("description", "", "Açıklama (durdururken de verilebilir)")
+	employee := fs.String("employee", "", "Çalışan adı ya da ID'si (boş bırakılırsa Odoo kullanıcısına bağlı çalışan)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *project == "" {
+		return fmt.Errorf("-project gerekli")
+	}
+
+	if _, _, err := timerSettings(); err != nil {
+		return err
+	}
+	client, err := authenticateOdoo()
+	if err != nil {
+		return fmt.Errorf("Odoo kimlik doğrulama hatası: %v", err)
+	}
+	defer client.Close()
+
+	timer, err := newTimer(client, *project, *task, *description)
+	if err != nil {
+		return err
+	}
+	if err := startTimer(cliTimerOwner(*employee), *timer); err != nil {
+		return err
+	}
+	fmt.Fprintf(stdout, "Zamanlayıcı başladı: %s\n", timer.label())
+	return nil
+}
+
+// stop: zamanlayıcıyı durdur ve kaydı oluştur
+func runStopCommand(args []string) error {
+	fs := flag.NewFlagSet("stop", flag.ContinueOnError)
+	description := fs.String("description", "", "Açıklama (boşsa başlatırken girilen açıklama ya da görev adı)")
+	employee := fs.String("employee", "", "Zamanlayıcının başlatıldığı çalışan")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	owner := cliTimerOwner(*employee)
+	running, err := runningTimer(owner)
+	if err != nil {
+		return err
+	}
+	if running == nil {
+		return fmt.Errorf("çalışan zamanlayıcı yok")
+	}
+
+	client, err := authenticateOdoo()
+	if err != nil {
+		return fmt.Errorf("Odoo kimlik doğrulama hatası: %v", err)
+	}
+	defer client.Close()
+
+	entry, capped, err := stopTimer(client, cliUser(*employee), owner, *description)
+	if err != nil {
+		return fmt.Errorf("%v\nZamanlayıcı çalışmaya devam ediyor; kayıt eklemeden durdurmak için: timer -discard", err)
+	}
+	fmt.Fprintln(stdout, "Zaman kaydı eklendi:")
+	fmt.Fprintln(stdout, entry.Summary())
+	if capped {
+		fmt.Fprintf(stdout, "Uyarı: süre kayıt başına en fazla saate indirildi (%.2f saat); gerekirse edit ile düzeltin\n", entry.Hours)
+	}
+	return nil
+}
+
+// timer: çalışan zamanlayıcıyı göster ya da iptal et
+func runTimerCommand(args []string) error {
+	fs := flag.NewFlagSet("timer", flag.ContinueOnError)
+	employee := fs.String("employee", "", "Zamanlayıcının başlatıldığı çalışan")
+	discard := fs.Bool("discard", false, "Zamanlayıcıyı kayıt eklemeden iptal et")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	owner := cliTimerOwner(*employee)
+	if *discard {
+		timer, err := takeTimer(owner)
+		if err != nil {
+			return err
+		}
+		if timer == nil {
+			return fmt.Errorf("çalışan zamanlayıcı yok")
+		}
+		fmt.Fprintf(stdout, "Zamanlayıcı iptal edildi: %s\n", timer.label())
+		return nil
+	}
+	timer, err := runningTimer(owner)
+	if err != nil {
+		return err
+	}
+	if timer == nil {
+		fmt.Fprintln(stdout, "Çalışan zamanlayıcı yok.")
+		return nil
+	}
+	fmt.Fprintln(stdout, timer.Status(timerNow()))
+	return nil
+}
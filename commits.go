@@ -0,0 +1,262 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Depo eşleme dosyasının varsayılan yolu
+const DefaultReposFile = "repos.yaml"
+
+// Süre tahmini varsayılanları
+const (
+	DefaultMaxCommitGap = 2 * time.Hour
+	DefaultFirstCommit  = 30 * time.Minute
+)
+
+// RepoMapping, yerel git deposunun Odoo proje ve görevine eşlenmesi
+type RepoMapping struct {
+	Path        string `yaml:"path"`
+	OdooProject int64  `yaml:"odoo_project"`
+	OdooTask    int64  `yaml:"odoo_task"` // Sıfırsa görevsiz kayıt
+}
+
+// ReposConfig, commit geçmişinden taslak kayıt üretme ayarları
+type ReposConfig struct {
+	// Commitleri aranan yazar; boşsa her deponun user.email ayarı
+	Author string `yaml:"author"`
+
+	// Ardışık iki commit arasındaki süre bundan uzunsa ikinci commit yeni bir
+	// çalışma oturumu başlatır
+	MaxGap time.Duration `yaml:"max_gap"`
+
+	// Oturumun ilk commitinden önce harcandığı varsayılan süre
+	FirstCommit time.Duration `yaml:"first_commit"`
+
+	Repos []RepoMapping `yaml:"repos"`
+}
+
+// Depo eşlemelerini REPOS_FILE (varsayılan repos.yaml) dosyasından oku. Dosya
+// yoksa boş yapılandırma döner.
+func loadReposConfig() (*ReposConfig, error) {
+	path := os.Getenv("REPOS_FILE")
+	if path == "" {
+		path = DefaultReposFile
+	}
+
+	cfg := &ReposConfig{}
+	data, err := os.ReadFile(path)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("depo dosyası okunamadı: %v", err)
+	}
+	if err == nil {
+		if err := yaml.Unmarshal(data, cfg); err != nil {
+			return nil, fmt.Errorf("depo dosyası çözümlenemedi (%s): %v", path, err)
+		}
+	}
+	for i, repo := range cfg.Repos {
+		if repo.Path == "" || repo.OdooProject <= 0 {
+			return nil, fmt.Errorf("depo dosyası (%s): %d. depoda path ya da odoo_project eksik", path, i+1)
+		}
+		if rest, ok := strings.CutPrefix(repo.Path, "~/"); ok {
+			if home, err := os.UserHomeDir(); err == nil {
+				cfg.Repos[i].Path = filepath.Join(home, rest)
+			}
+		}
+	}
+	if cfg.MaxGap <= 0 {
+		cfg.MaxGap = DefaultMaxCommitGap
+	}
+	if cfg.FirstCommit <= 0 {
+		cfg.FirstCommit = DefaultFirstCommit
+	}
+	return cfg, nil
+}
+
+// Commit, taslak kayıtlara dönüştürülen git commiti
+type Commit struct {
+	Repo    *RepoMapping
+	Hash    string
+	Time    time.Time // Yazarın saat dilimiyle yazım zamanı
+	Subject string
+}
+
+// Git komutunu depoda çalıştır
+func runGit(dir string, args ...string) (string, error) {
+	var stderr bytes.Buffer
+	cmd := exec.Command("git", append([]string{"-C", dir}, args...)...)
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("git %s (%s): %v %s", args[0], dir, err, strings.TrimSpace(stderr.String()))
+	}
+	return string(out), nil
+}
+
+// Depodaki yazara ait, dönem içinde yazılmış birleştirme dışı commitleri oku.
+// Tüm dallar taranır; gün, yazarın kendi saat dilimine göre belirlenir.
+func scanRepo(repo *RepoMapping, author string, period Period) ([]Commit, error) {
+	if author == "" {
+		email, err := runGit(repo.Path, "config", "user.email")
+		if err != nil {
+			return nil, fmt.Errorf("yazar belirlenemedi, -author verin: %v", err)
+		}
+		author = strings.TrimSpace(email)
+	}
+	// git, --since/--until'i işleyen (committer) tarihine uygular; yazım
+	// tarihi aşağıda ayrıca kontrol edilir. Saat dilimi farkları için bir
+	// gün geniş tutulur. --author düz metin olarak aranır ("ali+is@" gibi
+	// adresler düzenli ifade sayılmasın) ama alt dizgi eşleşmesidir
+	// ("ali@" "mali@" ile de eşleşir); yazar aşağıda birebir karşılaştırılır.
+	out, err := runGit(repo.Path, "log", "--all", "--no-merges", "--fixed-strings",
+		"--author="+author,
+		"--since="+period.Start.AddDate(0, 0, -1).Format(dateLayout),
+		"--until="+period.End.AddDate(0, 0, 2).Format(dateLayout),
+		"--format=%H%x1f%aI%x1f%ae%x1f%an%x1f%s")
+	if err != nil {
+		return nil, err
+	}
+
+	first, last := period.Start.Format(dateLayout), period.End.Format(dateLayout)
+	var commits []Commit
+	for _, line := range strings.Split(strings.TrimSpace(out), "\n") {
+		fields := strings.SplitN(line, "\x1f", 5)
+		if len(fields) != 5 {
+			continue
+		}
+		// E-posta verildiyse e-postayla, değilse adla birebir eşleşmeli
+		got := fields[3]
+		if strings.Contains(author, "@") {
+			got = fields[2]
+		}
+		if !strings.EqualFold(got, author) {
+			continue
+		}
+		t, err := time.Parse(time.RFC3339, fields[1])
+		if err != nil {
+			return nil, fmt.Errorf("%s: geçersiz commit tarihi %q", repo.Path, fields[1])
+		}
+		if day := t.Format(dateLayout); day < first || day > last {
+			continue
+		}
+		commits = append(commits, Commit{Repo: repo, Hash: fields[0], Time: t, Subject: fields[4]})
+	}
+	return commits, nil
+}
+
+// Draft, aynı gün ve aynı Odoo proje/görevine ait commitlerden üretilen
+// taslak kayıt
+type Draft struct {
+	Date      string
+	ProjectID int64
+	TaskID    int64
+	Hours     float64
+	Subjects  []string
+	Commits   int
+}
+
+// Commitlerden günlük taslak kayıtlar üret. Bir günün commitleri zamana göre
+// sıralanır; her commite bir önceki committen bu yana geçen süre yazılır.
+// Aradaki süre maxGap'ten uzunsa ya da günün ilk commitiyse commit yeni bir
+// oturum başlatır ve firstCommit kadar süre alır. Taslaklar günlere, sonra
+// depoların eşlendiği proje ve görevlere göre gruplanır; saatler step'in en
+// yakın katına yuvarlanır (en az bir adım).
+func commitDrafts(commits []Commit, maxGap, firstCommit time.Duration, step float64) []Draft {
+	byDay := map[string][]Commit{}
+	for _, c := range commits {
+		day := c.Time.Format(dateLayout)
+		byDay[day] = append(byDay[day], c)
+	}
+	days := make([]string, 0, len(byDay))
+	for day := range byDay {
+		days = append(days, day)
+	}
+	sort.Strings(days)
+
+	type key struct{ project, task int64 }
+	var drafts []Draft
+	for _, day := range days {
+		list := byDay[day]
+		sort.SliceStable(list, func(i, j int) bool { return list[i].Time.Before(list[j].Time) })
+
+		index := map[key]int{}
+		var dayDrafts []Draft
+		for i, c := range list {
+			spent := firstCommit
+			if i > 0 {
+				if gap := c.Time.Sub(list[i-1].Time); gap <= maxGap {
+					spent = gap
+				}
+			}
+			k := key{c.Repo.OdooProject, c.Repo.OdooTask}
+			n, ok := index[k]
+			if !ok {
+				n = len(dayDrafts)
+				index[k] = n
+				dayDrafts = append(dayDrafts, Draft{Date: day, ProjectID: k.project, TaskID: k.task})
+			}
+			d := &dayDrafts[n]
+			d.Hours += spent.Hours()
+			d.Commits++
+			if subject := strings.TrimSpace(c.Subject); subject != "" && !containsFold(d.Subjects, subject) {
+				d.Subjects = append(d.Subjects, subject)
+			}
+		}
+		drafts = append(drafts, dayDrafts...)
+	}
+
+	if step > 0 {
+		for i := range drafts {
+			drafts[i].Hours = math.Max(step, math.Round(drafts[i].Hours/step)*step)
+		}
+	}
+	return drafts
+}
+
+// Taslağı içe aktarma satırına çevir; açıklama commit başlıklarından oluşur
+func (d Draft) row(line int) ImportRow {
+	row := ImportRow{
+		Line:        line,
+		Date:        d.Date,
+		Project:     fmt.Sprintf("#%d", d.ProjectID),
+		Description: strings.Join(d.Subjects, "; "),
+		Hours:       d.Hours,
+	}
+	if d.TaskID > 0 {
+		row.Task = fmt.Sprintf("#%d", d.TaskID)
+	}
+	return row
+}
+
+// Eşlenen depoları tara ve dönem için taslak kayıtları üret
+func scanCommitDrafts(cfg *ReposConfig, author string, period Period, step float64) ([]Draft, error) {
+	if author == "" {
+		author = cfg.Author
+	}
+	var commits []Commit
+	seen := map[string]bool{}
+	for i := range cfg.Repos {
+		found, err := scanRepo(&cfg.Repos[i], author, period)
+		if err != nil {
+			return nil, err
+		}
+		// Aynı depo birden fazla kez tanımlanmışsa commitler bir kez sayılır
+		for _, c := range found {
+			if !seen[c.Hash] {
+				seen[c.Hash] = true
+				commits = append(commits, c)
+			}
+		}
+	}
+	return commitDrafts(commits, cfg.MaxGap, cfg.FirstCommit, step), nil
+}
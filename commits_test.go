@@ -0,0 +1,182 @@
+package main
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"odoo-efor-tracker/odoo"
+)
+
+func TestCommitDrafts(t *testing.T) {
+	mobil := &RepoMapping{Path: "mobil", OdooProject: 1, OdooTask: 1}
+	portal := &RepoMapping{Path: "portal", OdooProject: 3}
+	at := func(s string) time.Time {
+		v, err := time.Parse(time.RFC3339, s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return v
+	}
+	commits := []Commit{
+		{Repo: portal, Time: at("2025-02-05T10:30:00+03:00"), Subject: "Portal menüsü"},
+		{Repo: mobil, Time: at("2025-02-05T09:00:00+03:00"), Subject: "Giriş ekranı"},
+		{Repo: mobil, Time: at("2025-02-05T09:40:00+03:00"), Subject: "Giriş ekranı"},
+		{Repo: mobil, Time: at("2025-02-05T10:10:00+03:00"), Subject: "Testler"},
+		{Repo: mobil, Time: at("2025-02-05T15:00:00+03:00"), Subject: "Kod incelemesi düzeltmeleri"},
+		{Repo: portal, Time: at("2025-02-06T23:30:00+03:00"), Subject: "Gece düzeltmesi"},
+	}
+	got := commitDrafts(commits, 2*time.Hour, 30*time.Minute, 0.25)
+	want := []Draft{
+		// 30 dk (ilk commit) + 40 dk + 30 dk + 30 dk (yeni oturum) = 2 saat 10 dk
+		{Date: "2025-02-05", ProjectID: 1, TaskID: 1, Hours: 2.25, Commits: 4,
+			Subjects: []string{"Giriş ekranı", "Testler", "Kod incelemesi düzeltmeleri"}},
+		// Önceki committen 20 dk sonra
+		{Date: "2025-02-05", ProjectID: 3, Hours: 0.25, Commits: 1, Subjects: []string{"Portal menüsü"}},
+		{Date: "2025-02-06", ProjectID: 3, Hours: 0.5, Commits: 1, Subjects: []string{"Gece düzeltmesi"}},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("taslaklar = %+v", got)
+	}
+	for i := range want {
+		g, w := got[i], want[i]
+		if g.Date != w.Date || g.ProjectID != w.ProjectID || g.TaskID != w.TaskID || g.Hours != w.Hours ||
+			g.Commits != w.Commits || strings.Join(g.Subjects, "|") != strings.Join(w.Subjects, "|") {
+			t.Errorf("taslak %d = %+v, beklenen %+v", i, g, w)
+		}
+	}
+
+	if row := got[0].row(1); row.Project != "#1" || row.Task != "#1" || row.Description != "Giriş ekranı; Testler; Kod incelemesi düzeltmeleri" {
+		t.Errorf("satır = %+v", row)
+	}
+	if row := got[1].row(2); row.Task != "" {
+		t.Errorf("görevsiz taslağın satırı = %+v", row)
+	}
+}
+
+// Verilen zamanlarda commitleri olan geçici bir git deposu oluştur
+func newGitRepo(t *testing.T, commits ...[3]string) string {
+	t.Helper()
+	dir := t.TempDir()
+	git := func(env []string, args ...string) {
+		cmd := exec.Command("git", append([]string{"-C", dir}, args...)...)
+		cmd.Env = append(os.Environ(), env...)
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+	}
+	git(nil, "init", "-q")
+	git(nil, "config", "user.email", "osman@example.com")
+	git(nil, "config", "user.name", "Osman")
+	for _, c := range commits { // {yazar e-postası, zaman, başlık}
+		date := "GIT_AUTHOR_DATE=" + c[1]
+		git([]string{date, "GIT_COMMITTER_DATE=" + c[1], "GIT_AUTHOR_EMAIL=" + c[0]},
+			"commit", "-q", "--allow-empty", "-m", c[2])
+	}
+	return dir
+}
+
+func TestCLICommits(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git bulunamadı")
+	}
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("GIT_CONFIG_NOSYSTEM", "1")
+
+	mobil := newGitRepo(t,
+		[3]string{"osman@example.com", "2025-02-04T17:00:00+03:00", "Dönem dışı"},
+		[3]string{"osman@example.com", "2025-02-05T09:00:00+03:00", "Giriş ekranı"},
+		[3]string{"baskasi@example.com", "2025-02-05T09:20:00+03:00", "Başkasının commiti"},
+		[3]string{"osman@example.com", "2025-02-05T10:00:00+03:00", "Giriş testleri"},
+	)
+	portal := newGitRepo(t,
+		[3]string{"osman@example.com", "2025-02-05T10:45:00+03:00", "Menü düzeltmesi"},
+	)
+	dir := t.TempDir()
+	repos := filepath.Join(dir, "repos.yaml")
+	if err := os.WriteFile(repos, []byte(`max_gap: 1h
+first_commit: 15m
+repos:
+  - path: `+mobil+`
+    odoo_project: 1
+    odoo_task: 1
+  - path: `+portal+`
+    odoo_project: 3
+`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("REPOS_FILE", repos)
+
+	srv := newFakeOdoo(t)
+	before := len(srv.Records(odoo.ModelTimesheet))
+
+	// Taslaklar düzenlenmek üzere dosyaya yazılabilir
+	drafts := filepath.Join(dir, "taslak.csv")
+	out := captureCLI(t, "")
+	if err := runCommitsCommand([]string{"-from", "2025-02-05", "-to", "2025-02-05", "-out", drafts}); err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(drafts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "date,project,task,description,hours,employee\n" +
+		"2025-02-05,#1,#1,Giriş ekranı; Giriş testleri,1.25,\n" +
+		"2025-02-05,#3,,Menü düzeltmesi,0.75,\n"
+	if string(data) != want {
+		t.Errorf("taslak dosyası:\n%s\nbeklenen:\n%s", data, want)
+	}
+	if !strings.Contains(out.String(), "2 taslak kayıt") {
+		t.Errorf("çıktı:\n%s", out)
+	}
+	if n := len(srv.Records(odoo.ModelTimesheet)); n != before {
+		t.Fatalf("taslak yazılırken %d kayıt eklendi", n-before)
+	}
+
+	// Onaylanan taslaklar içe aktarma ile aynı yoldan eklenir
+	out = captureCLI(t, "e\n")
+	if err := runCommitsCommand([]string{"-from", "2025-02-05", "-to", "2025-02-05"}); err != nil {
+		t.Fatalf("%v\n%s", err, out)
+	}
+	for _, s := range []string{
+		"+ satır 1: 2025-02-05 Osman Çağrı GENÇ: TEKNOSA / CX-7006 - 1.25 saat - Giriş ekranı; Giriş testleri",
+		"+ satır 2: 2025-02-05 Osman Çağrı GENÇ: CX Portal - 0.75 saat - Menü düzeltmesi",
+		"2 eklendi",
+	} {
+		if !strings.Contains(out.String(), s) {
+			t.Errorf("çıktıda %q yok:\n%s", s, out)
+		}
+	}
+	if n := len(srv.Records(odoo.ModelTimesheet)); n != before+2 {
+		t.Errorf("%d kayıt eklendi, beklenen 2", n-before)
+	}
+}
+
+func TestScanRepoAuthorWithPlus(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git bulunamadı")
+	}
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("GIT_CONFIG_NOSYSTEM", "1")
+
+	repo := &RepoMapping{Path: newGitRepo(t,
+		[3]string{"ali+is@example.com", "2025-02-05T09:00:00+03:00", "Giriş ekranı"},
+		[3]string{"aliis@example.com", "2025-02-05T10:00:00+03:00", "Başkasının commiti"},
+		[3]string{"mali+is@example.com", "2025-02-05T11:00:00+03:00", "Adresi içeren başka yazar"},
+	), OdooProject: 1}
+	period, err := parsePeriod("2025-02-05", time.Now())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	commits, err := scanRepo(repo, "ali+is@example.com", period)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(commits) != 1 || commits[0].Subject != "Giriş ekranı" {
+		t.Errorf("commitler = %+v", commits)
+	}
+}
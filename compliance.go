@@ -0,0 +1,314 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"odoo-efor-tracker/odoo"
+)
+
+// Bir çalışanın beklenen iş gününde hiç kayıt girmediği ya da eksik
+// girdiği gün
+type MissingDay struct {
+	Employee   string  `json:"employee"`
+	EmployeeID int64   `json:"employee_id"`
+	Date       string  `json:"date"`
+	Hours      float64 `json:"hours"`
+	Expected   float64 `json:"expected"`
+}
+
+// O gün hiç kayıt girilmemiş mi
+func (m MissingDay) Empty() bool {
+	return m.Hours == 0
+}
+
+// WorkCalendar, çalışanların dönem içindeki günlük beklenen saatlerini
+// belirler. Haftalık takvim (Odoo resource.calendar ya da work_hours.yaml),
+// tarih bazlı istisnalar, genel tatiller (resource.calendar.leaves, ülke
+// takvimi ve şirket kapanışları; arifeler yarım gün) ve onaylı izinler
+// (hr.leave; yarım gün izinler dahil) birlikte uygulanır.
+type WorkCalendar struct {
+	fallback  Schedule                     // Takvimi bilinmeyen çalışanlar için
+	schedules map[int64]Schedule           // çalışan → haftalık takvim
+	calendars map[int64]employeeCalendar   // çalışan → Odoo takvimi
+	hours     map[int64]map[string]float64 // çalışan → tarih → saat istisnası
+	holidays  map[string]string            // tarih → tatil adı
+	halfDays  map[string]string            // tarih → yarım gün tatil adı
+	leaves    map[int64]map[string]float64 // çalışan → tarih → izinli gün oranı
+}
+
+// Yalnızca varsayılan takvimi (pazartesi-cuma MinWorkHours) içeren takvim
+func newWorkCalendar() *WorkCalendar {
+	return &WorkCalendar{
+		fallback:  defaultSchedule(),
+		schedules: make(map[int64]Schedule),
+		calendars: make(map[int64]employeeCalendar),
+		hours:     make(map[int64]map[string]float64),
+		holidays:  make(map[string]string),
+		halfDays:  make(map[string]string),
+		leaves:    make(map[int64]map[string]float64),
+	}
+}
+
+// Çalışanın haftalık takvimini ayarla
+func (c *WorkCalendar) SetSchedule(employeeID int64, s Schedule) {
+	c.schedules[employeeID] = s
+}
+
+// Odoo takvimine bağlı çalışanın takvimi; dosyadaki tanım üzerine yazılır
+type employeeCalendar struct {
+	calendar odooCalendar
+	override *HoursConfig
+}
+
+// Çalışanın takvimini Odoo takviminden al. İki haftalık ve tarih aralıklı
+// takvimlerde saatler haftadan haftaya değişebilir.
+func (c *WorkCalendar) SetCalendar(employeeID int64, calendar odooCalendar, override *HoursConfig) {
+	c.calendars[employeeID] = employeeCalendar{calendar: calendar, override: override}
+}
+
+// Çalışanın, günün içinde bulunduğu haftadaki takvimi
+func (c *WorkCalendar) schedule(employeeID int64, day time.Time) Schedule {
+	if ec, ok := c.calendars[employeeID]; ok {
+		s := ec.calendar.Week(day)
+		if ec.override != nil {
+			s = ec.override.apply(s)
+		}
+		return s
+	}
+	if s, ok := c.schedules[employeeID]; ok {
+		return s
+	}
+	return c.fallback
+}
+
+// Çalışanın belirli bir gündeki beklenen saatini ayarla (ör. yarım gün)
+func (c *WorkCalendar) SetHours(employeeID int64, date string, hours float64) {
+	if c.hours[employeeID] == nil {
+		c.hours[employeeID] = make(map[string]float64)
+	}
+	c.hours[employeeID][date] = hours
+}
+
+// Genel tatil ekle
+func (c *WorkCalendar) AddHoliday(date, name string) {
+	c.holidays[date] = name
+}
+
+// Yarım gün tatil (arife) ekle; o gün beklenen saat yarıya iner
+func (c *WorkCalendar) AddHalfDay(date, name string) {
+	c.halfDays[date] = name
+}
+
+// Çalışanın izinli olduğu günü ekle; fraction 1 tam gün, 0.5 yarım gündür
+func (c *WorkCalendar) AddLeave(employeeID int64, date string, fraction float64) {
+	if c.leaves[employeeID] == nil {
+		c.leaves[employeeID] = make(map[string]float64)
+	}
+	c.leaves[employeeID][date] = math.Min(1, c.leaves[employeeID][date]+fraction)
+}
+
+// Çalışanın o gün çalışması beklenen saat; iş günü değilse 0
+func (c *WorkCalendar) Expected(employeeID int64, day time.Time) float64 {
+	date := day.Format(dateLayout)
+	if _, ok := c.holidays[date]; ok {
+		return 0
+	}
+
+	hours, ok := c.hours[employeeID][date]
+	if !ok {
+		hours = c.schedule(employeeID, day)[day.Weekday()]
+	}
+	if _, ok := c.halfDays[date]; ok {
+		hours /= 2
+	}
+	return hours * (1 - c.leaves[employeeID][date])
+}
+
+// Gün, çalışan için iş günü mü
+func (c *WorkCalendar) Workday(employeeID int64, day time.Time) bool {
+	return c.Expected(employeeID, day) > 0
+}
+
+// Ülke tatilleri ve şirket kapanışlarıyla birlikte dönemin genel
+// tatillerini ve verilen çalışanların onaylı izinlerini Odoo'dan okuyarak
+// takvimi oluştur. Odoo tarih-saatleri UTC olduğundan sorgu bir gün geniş
+// tutulur ve değerler dönemin saat dilimine çevrilir.
+func loadWorkCalendar(client odoo.API, period Period, employeeIDs []int64) (*WorkCalendar, error) {
+	holidays, err := loadHolidayCalendar()
+	if err != nil {
+		return nil, err
+	}
+	cal := newWorkCalendar()
+	holidays.apply(cal)
+
+	loc := period.Start.Location()
+	from := period.Start.AddDate(0, 0, -1).Format(dateLayout) + " 00:00:00"
+	to := period.End.AddDate(0, 0, 1).Format(dateLayout) + " 23:59:59"
+
+	var odooHolidays []odoo.Holiday
+	err = client.SearchRead(odoo.ModelHoliday,
+		odoo.NewDomain().
+			Where("resource_id", "=", false).
+			Where("date_from", "<=", to).
+			Where("date_to", ">=", from),
+		nil, &odooHolidays)
+	if err != nil {
+		return nil, fmt.Errorf("tatiller alınamadı: %v", err)
+	}
+	for _, h := range odooHolidays {
+		days, err := leaveDays(h.DateFrom, h.DateTo, loc)
+		if err != nil {
+			return nil, fmt.Errorf("%s tatili: %v", h.Name, err)
+		}
+		for _, day := range days {
+			cal.AddHoliday(day, h.Name)
+		}
+	}
+
+	if len(employeeIDs) == 0 {
+		return cal, nil
+	}
+
+	var leaves []odoo.Leave
+	err = client.SearchRead(odoo.ModelLeave,
+		odoo.NewDomain().
+			Where("employee_id", "in", employeeIDs).
+			Where("state", "=", "validate").
+			Where("date_from", "<=", to).
+			Where("date_to", ">=", from),
+		nil, &leaves)
+	if err != nil {
+		return nil, fmt.Errorf("izinler alınamadı: %v", err)
+	}
+	for _, l := range leaves {
+		days, err := leaveDays(l.DateFrom, l.DateTo, loc)
+		if err != nil {
+			return nil, fmt.Errorf("%s izni: %v", l.Employee.Name, err)
+		}
+		fraction := 1.0
+		if l.HalfDay {
+			fraction = 0.5
+		}
+		for _, day := range days {
+			cal.AddLeave(l.Employee.ID, day, fraction)
+		}
+	}
+	return cal, nil
+}
+
+// UTC tarih-saat aralığının yerel saatte kapsadığı günler
+func leaveDays(dateFrom, dateTo string, loc *time.Location) ([]string, error) {
+	start, err := time.ParseInLocation(odoo.DateTimeLayout, dateFrom, time.UTC)
+	if err != nil {
+		return nil, err
+	}
+	end, err := time.ParseInLocation(odoo.DateTimeLayout, dateTo, time.UTC)
+	if err != nil {
+		return nil, err
+	}
+	start, end = start.In(loc), end.In(loc)
+
+	var days []string
+	day := time.Date(start.Year(), start.Month(), start.Day(), 0, 0, 0, 0, loc)
+	for !day.After(end) {
+		days = append(days, day.Format(dateLayout))
+		day = day.AddDate(0, 0, 1)
+	}
+	return days, nil
+}
+
+// Her çalışan için dönemdeki (bugüne kadarki) iş günlerinde hiç kayıt
+// girilmeyen ya da beklenen saatin altında kalan günleri bul. Sonuç
+// çalışan adına, sonra tarihe göre sıralıdır.
+func findMissingDays(r *Report, employees []odoo.Employee, cal *WorkCalendar, now time.Time) []MissingDay {
+	hours := make(map[int64]map[string]float64)
+	for _, entry := range r.Entries {
+		if hours[entry.Employee.ID] == nil {
+			hours[entry.Employee.ID] = make(map[string]float64)
+		}
+		hours[entry.Employee.ID][entry.Date] += entry.UnitAmount
+	}
+
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, r.Period.Start.Location())
+	var missing []MissingDay
+	for _, emp := range employees {
+		for _, day := range r.Period.Days() {
+			expected := cal.Expected(emp.ID, day)
+			if day.After(today) || expected == 0 {
+				continue
+			}
+			date := day.Format(dateLayout)
+			if h := hours[emp.ID][date]; h < expected {
+				missing = append(missing, MissingDay{
+					Employee:   emp.Name,
+					EmployeeID: emp.ID,
+					Date:       date,
+					Hours:      h,
+					Expected:   expected,
+				})
+			}
+		}
+	}
+	sort.SliceStable(missing, func(i, j int) bool {
+		return missing[i].Employee < missing[j].Employee
+	})
+	return missing
+}
+
+// Rapor kapsamındaki çalışanlar. Filtre yoksa dönemde kaydı olan
+// çalışanlar Odoo'dan okunur.
+func scopeEmployees(client odoo.API, r *Report) ([]odoo.Employee, error) {
+	if r.Members != nil {
+		return r.Members, nil
+	}
+
+	var ids []int64
+	seen := make(map[int64]bool)
+	for _, entry := range r.Entries {
+		if !seen[entry.Employee.ID] {
+			seen[entry.Employee.ID] = true
+			ids = append(ids, entry.Employee.ID)
+		}
+	}
+	if len(ids) == 0 {
+		return nil, nil
+	}
+
+	var employees []odoo.Employee
+	err := client.SearchRead(odoo.ModelEmployee, odoo.NewDomain().Where("id", "in", ids),
+		&odoo.SearchOptions{Order: "name"}, &employees)
+	if err != nil {
+		return nil, fmt.Errorf("çalışanlar alınamadı: %v", err)
+	}
+	return employees, nil
+}
+
+// Çalışma takvimini Odoo'dan ve work_hours.yaml'dan oluştur; günlük durum
+// etiketlerini çalışanların beklenen saatlerine göre yeniden hesapla ve
+// raporun eksik günlerini doldur
+func applyWorkCalendar(client odoo.API, r *Report, now time.Time) error {
+	employees, err := scopeEmployees(client, r)
+	if err != nil {
+		return err
+	}
+	ids := make([]int64, 0, len(employees))
+	for _, emp := range employees {
+		ids = append(ids, emp.ID)
+	}
+
+	cal, err := loadWorkCalendar(client, r.Period, ids)
+	if err != nil {
+		return err
+	}
+	if err := loadEmployeeSchedules(client, employees, cal); err != nil {
+		return err
+	}
+
+	r.calendar = cal
+	r.applyCalendar(cal)
+	r.Missing = findMissingDays(r, employees, cal, now)
+	return nil
+}
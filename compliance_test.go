@@ -0,0 +1,141 @@
+package main
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"odoo-efor-tracker/odoo"
+)
+
+func TestLeaveDays(t *testing.T) {
+	istanbul := time.FixedZone("TRT", 3*60*60)
+
+	// Odoo'da Türkiye saatiyle tam gün tatil UTC olarak önceki gün 21:00'de başlar
+	days, err := leaveDays("2025-04-22 21:00:00", "2025-04-23 20:59:59", istanbul)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(days, []string{"2025-04-23"}) {
+		t.Errorf("leaveDays = %v", days)
+	}
+
+	days, _ = leaveDays("2025-02-05 05:00:00", "2025-02-07 14:00:00", istanbul)
+	if !reflect.DeepEqual(days, []string{"2025-02-05", "2025-02-06", "2025-02-07"}) {
+		t.Errorf("çok günlük izin = %v", days)
+	}
+
+	if _, err := leaveDays("2025-02-05", "2025-02-06 14:00:00", istanbul); err == nil {
+		t.Error("geçersiz tarih-saat için hata bekleniyordu")
+	}
+}
+
+func TestWorkCalendar(t *testing.T) {
+	cal := newWorkCalendar()
+	cal.AddHoliday("2025-04-23", "Ulusal Egemenlik ve Çocuk Bayramı")
+	cal.AddLeave(7, "2025-04-24", 1)
+
+	for _, tt := range []struct {
+		employee int64
+		date     string
+		want     bool
+	}{
+		{7, "2025-04-22", true},
+		{7, "2025-04-23", false}, // tatil
+		{7, "2025-04-24", false}, // izinli
+		{8, "2025-04-24", true},
+		{8, "2025-04-26", false}, // cumartesi
+		{8, "2025-04-27", false}, // pazar
+	} {
+		day, _ := time.Parse(dateLayout, tt.date)
+		if got := cal.Workday(tt.employee, day); got != tt.want {
+			t.Errorf("Workday(%d, %s) = %v, beklenen %v", tt.employee, tt.date, got, tt.want)
+		}
+	}
+}
+
+func TestGenerateReportMissingDays(t *testing.T) {
+	newFakeOdoo(t)
+	chdirTemp(t)
+
+	// 2025-02-03 pazartesi - 2025-02-09 pazar; 07 şirket tatili,
+	// Ayşegül 05-06 onaylı izinde, Fatih'in 05 izni reddedilmiş,
+	// Osman 05'te yarım gün izinli, Fatih 06'da yarım gün (work_hours.yaml)
+	period, err := parsePeriod("2025-W06", time.Now())
+	if err != nil {
+		t.Fatal(err)
+	}
+	report, _, err := generateReport(period, "", "", FormatText, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	type day struct {
+		employee, date  string
+		hours, expected float64
+	}
+	var got []day
+	for _, m := range report.Missing {
+		got = append(got, day{m.Employee, m.Date, m.Hours, m.Expected})
+	}
+	want := []day{
+		{"Ayşegül Şahin", "2025-02-03", 5.5, 8},
+		{"Fatih Delice", "2025-02-03", 0, 8},
+		{"Fatih Delice", "2025-02-04", 7, 8},
+		{"Fatih Delice", "2025-02-05", 0, 8},
+		{"Fatih Delice", "2025-02-06", 0, 4},
+		// Kişiye özel takvim izni (resource_id dolu) genel tatil sayılmaz
+		{"Osman Çağrı GENÇ", "2025-02-05", 0, 4},
+		{"Osman Çağrı GENÇ", "2025-02-06", 0, 8},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("eksik günler:\n%v\nbeklenen:\n%v", got, want)
+	}
+
+	summary := renderSummary(report)
+	if !strings.Contains(summary, "Fatih Delice: 4 gün") {
+		t.Errorf("özette eksik gün sayısı yok:\n%s", summary)
+	}
+}
+
+func TestLoadReportUsesNow(t *testing.T) {
+	newFakeOdoo(t)
+	client, err := authenticateOdoo()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	period, err := parsePeriod("2025-W06", time.Now())
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 2025-02-04 akşamı itibarıyla sonraki günler eksik sayılmaz
+	now := time.Date(2025, 2, 4, 18, 0, 0, 0, period.Start.Location())
+	report, err := loadReport(client, period, "", "", now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, m := range report.Missing {
+		if m.Date > "2025-02-04" {
+			t.Errorf("gelecekteki gün eksik sayıldı: %+v", m)
+		}
+	}
+	if len(report.Missing) != 3 {
+		t.Errorf("eksik günler = %+v", report.Missing)
+	}
+}
+
+func TestFindMissingDaysSkipsFuture(t *testing.T) {
+	period := Period{
+		Start: time.Date(2025, 2, 3, 0, 0, 0, 0, time.UTC),
+		End:   time.Date(2025, 2, 7, 0, 0, 0, 0, time.UTC),
+	}
+	r := buildReport(period, nil, nil)
+	now := time.Date(2025, 2, 4, 18, 0, 0, 0, time.UTC)
+
+	missing := findMissingDays(r, []odoo.Employee{{ID: 1, Name: "Ali"}}, newWorkCalendar(), now)
+	if len(missing) != 2 || missing[1].Date != "2025-02-04" {
+		t.Errorf("bugünden sonraki günler sayılmamalı: %v", missing)
+	}
+}
@@ -0,0 +1,240 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"odoo-efor-tracker/odoo"
+)
+
+// Son kayıtlar listelenirken varsayılan olarak kaç gün geriye bakılacağı
+const recentEntryDays = 14
+
+// Listede gösterilecek en fazla kayıt sayısı
+const recentEntryLimit = 30
+
+// EntryChanges, bir zaman kaydında değiştirilecek alanlar; nil alanlar
+// olduğu gibi kalır
+type EntryChanges struct {
+	Hours       *float64
+	Description *string
+	Task        *string // Görev adı, kodu ya da #ID; boş dize görevi kaldırır
+}
+
+// Değişiklik yok mu
+func (c EntryChanges) Empty() bool {
+	return c.Hours == nil && c.Description == nil && c.Task == nil
+}
+
+// Kullanıcının kayıtlarının bağlı olduğu çalışan. Bağlı çalışanı olmayan
+// kullanıcılar (tek sohbetli kurulumdaki yönetici, komut satırı) için Odoo
+// oturumunun kullanıcısına bağlı çalışan kullanılır.
+func userEmployeeID(client *odoo.Client, user *BotUser) (int64, error) {
+	if user.EmployeeID > 0 {
+		return user.EmployeeID, nil
+	}
+	if user.Employee != "" {
+		emp, err := resolveEmployee(client, user.Employee)
+		if err != nil {
+			return 0, err
+		}
+		return emp.ID, nil
+	}
+	var employees []odoo.Employee
+	err := client.SearchRead(odoo.ModelEmployee,
+		odoo.NewDomain().Where("user_id", "=", client.UID()),
+		&odoo.SearchOptions{Limit: 1}, &employees)
+	if err != nil {
+		return 0, err
+	}
+	if len(employees) == 0 {
+		return 0, errors.New("Odoo kullanıcısına bağlı çalışan bulunamadı")
+	}
+	return employees[0].ID, nil
+}
+
+// Çalışanın son kayıtlarını yeniden eskiye sıralı döndür
+func recentEntries(client odoo.API, employeeID int64, days int, now time.Time) ([]odoo.TimesheetLine, error) {
+	since := now.AddDate(0, 0, -days).Format(dateLayout)
+	var lines []odoo.TimesheetLine
+	err := client.SearchRead(odoo.ModelTimesheet,
+		odoo.NewDomain().
+			Where("employee_id", "=", employeeID).
+			Where("date", ">=", since),
+		&odoo.SearchOptions{Limit: recentEntryLimit, Order: "date desc, id desc"}, &lines)
+	return lines, err
+}
+
+// Kaydın tek satırlık gösterimi
+func formatEntry(line odoo.TimesheetLine) string {
+	task := ""
+	if line.Task.Valid() {
+		task = " / " + line.Task.Name
+	}
+	return fmt.Sprintf("#%d %s %s%s - %.2f saat - %s",
+		line.ID, line.Date, line.Project.Name, task, line.UnitAmount, line.Description)
+}
+
+// Kaydın kilitli olup olmadığını belirleyen alanlar
+var lockFields = []string{"validated", "timesheet_invoice_id", "date", "employee_id"}
+
+// Kilit tarihini TIMESHEET_LOCK_DATE'ten (YYYY-MM-DD) oku; tanımlı
+// değilse sıfır değer döner
+func lockDateFromEnv() (time.Time, error) {
+	v := strings.TrimSpace(os.Getenv("TIMESHEET_LOCK_DATE"))
+	if v == "" {
+		return time.Time{}, nil
+	}
+	lock, err := time.Parse(dateLayout, v)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("geçersiz TIMESHEET_LOCK_DATE: %q (YYYY-MM-DD)", v)
+	}
+	return lock, nil
+}
+
+// Kaydın değiştirilemez olma nedeni; değiştirilebiliyorsa boş döner.
+// Onaylanmış (validated) ve faturalanmış kayıtlar ile kilit tarihinden
+// (lock; sıfırsa kilit yok) önceki kayıtlar kilitlidir.
+func entryLockReason(fields map[string]interface{}, lock time.Time) string {
+	if validated, _ := fields["validated"].(bool); validated {
+		return "kayıt onaylanmış"
+	}
+	if invoice, ok := fields["timesheet_invoice_id"]; ok && invoice != nil && invoice != false {
+		return "kayıt faturalanmış"
+	}
+	if !lock.IsZero() {
+		value, _ := fields["date"].(string)
+		if date, err := time.Parse(dateLayout, value); err == nil && date.Before(lock) {
+			return fmt.Sprintf("%s tarihinden önceki kayıtlar kilitli", lock.Format(dateLayout))
+		}
+	}
+	return ""
+}
+
+// Düzenlenecek ya da silinecek kaydı oku. Kaydın kullanıcıya ait olduğunu
+// (yöneticiler hariç) ve kilitli olmadığını doğrular.
+func loadEditableEntry(client *odoo.Client, user *BotUser, id int64) (*odoo.TimesheetLine, error) {
+	lock, err := lockDateFromEnv()
+	if err != nil {
+		return nil, err
+	}
+
+	var lines []odoo.TimesheetLine
+	if err := client.Read(odoo.ModelTimesheet, []int64{id}, nil, &lines); err != nil {
+		return nil, err
+	}
+	if len(lines) == 0 {
+		return nil, fmt.Errorf("kayıt bulunamadı: #%d", id)
+	}
+	line := &lines[0]
+
+	if !user.Can(RoleAdmin) {
+		employeeID, err := userEmployeeID(client, user)
+		if err != nil {
+			return nil, err
+		}
+		if line.Employee.ID != employeeID {
+			return nil, fmt.Errorf("#%d başka bir çalışana ait, yalnızca kendi kayıtlarınızı değiştirebilirsiniz", id)
+		}
+	}
+
+	// Kilit alanları Odoo sürümüne ve kurulu modüllere göre değişir;
+	// yalnızca modelde var olanlar okunur
+	fields, err := presentFields(client, odoo.ModelTimesheet, lockFields...)
+	if err != nil {
+		return nil, err
+	}
+	var locks []map[string]interface{}
+	if err := client.Read(odoo.ModelTimesheet, []int64{id}, fields, &locks); err != nil {
+		return nil, err
+	}
+	if len(locks) > 0 {
+		if reason := entryLockReason(locks[0], lock); reason != "" {
+			return nil, fmt.Errorf("#%d değiştirilemez: %s", id, reason)
+		}
+	}
+	return line, nil
+}
+
+// Değişiklikleri Odoo'ya yazılacak alanlara çevir; görev, kaydın projesinde
+// aranır. Kaydın değişmiş hali yeni kayıtlarla aynı kurallarla doğrulanır.
+// Kullanıcıya gösterilecek açıklamayı da döndürür.
+func entryChangeValues(client odoo.API, line *odoo.TimesheetLine, changes EntryChanges) (odoo.Values, []string, error) {
+	values := odoo.Values{}
+	var notes []string
+	updated := NewTimeEntry{
+		Date:        line.Date,
+		ProjectID:   line.Project.ID,
+		TaskID:      line.Task.ID,
+		Description: line.Description,
+		Hours:       line.UnitAmount,
+	}
+	if changes.Hours != nil {
+		values["unit_amount"] = *changes.Hours
+		updated.Hours = *changes.Hours
+		notes = append(notes, fmt.Sprintf("Saat: %.2f → %.2f", line.UnitAmount, *changes.Hours))
+	}
+	if changes.Description != nil {
+		values["name"] = *changes.Description
+		updated.Description = *changes.Description
+		notes = append(notes, fmt.Sprintf("Açıklama: %s → %s", line.Description, *changes.Description))
+	}
+	if changes.Task != nil {
+		old := line.Task.Name
+		if old == "" {
+			old = "-"
+		}
+		if *changes.Task == "" || *changes.Task == "-" {
+			values["task_id"] = false
+			updated.TaskID = 0
+			notes = append(notes, fmt.Sprintf("Görev: %s → -", old))
+		} else {
+			task, err := resolveTask(client, line.Project.ID, *changes.Task)
+			if err != nil {
+				return nil, nil, err
+			}
+			values["task_id"] = task.ID
+			updated.TaskID = task.ID
+			notes = append(notes, fmt.Sprintf("Görev: %s → %s", old, task.Name))
+		}
+	}
+	if len(values) == 0 {
+		return nil, nil, errors.New("değiştirilecek alan yok")
+	}
+
+	rules, err := validationRulesFromEnv()
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := validateEntry(client, rules, line.Employee.ID, updated, line.ID, time.Now()); err != nil {
+		return nil, nil, err
+	}
+	return values, notes, nil
+}
+
+// Düzenleme komutu argümanlarını çöz: "<alan> <değer>". Alanlar saat,
+// açıklama ve görev (hours, description, task) olabilir. Değerler
+// entryChangeValues içinde doğrulanır.
+func parseEntryChange(field, value string) (EntryChanges, error) {
+	var changes EntryChanges
+	value = strings.TrimSpace(value)
+	switch strings.ToLower(field) {
+	case "saat", "hours":
+		hours, err := strconv.ParseFloat(strings.Replace(value, ",", ".", 1), 64)
+		if err != nil {
+			return changes, fmt.Errorf("geçersiz saat: %q", value)
+		}
+		changes.Hours = &hours
+	case "açıklama", "aciklama", "description", "desc":
+		changes.Description = &value
+	case "görev", "gorev", "task":
+		changes.Task = &value
+	default:
+		return changes, fmt.Errorf("bilinmeyen alan %q (saat, açıklama, görev)", field)
+	}
+	return changes, nil
+}
@@ -0,0 +1,136 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"odoo-efor-tracker/odoo"
+	"odoo-efor-tracker/odoo/odootest"
+)
+
+// Komut satırı çıktısını yakala ve girdiyi ayarla
+func captureCLI(t *testing.T, input string) *bytes.Buffer {
+	t.Helper()
+	var out bytes.Buffer
+	stdin, stdout = strings.NewReader(input), &out
+	t.Cleanup(func() { stdin, stdout = os.Stdin, os.Stdout })
+	return &out
+}
+
+func findRecord(srv *odootest.Server, id int64) map[string]interface{} {
+	for _, rec := range srv.Records(odoo.ModelTimesheet) {
+		if rec["id"] == id {
+			return rec
+		}
+	}
+	return nil
+}
+
+func TestEntryLockReason(t *testing.T) {
+	t.Setenv("TIMESHEET_LOCK_DATE", "2025-02-01")
+	lock, err := lockDateFromEnv()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, tt := range []struct {
+		fields map[string]interface{}
+		want   string
+	}{
+		{map[string]interface{}{"date": "2025-02-03", "validated": false, "timesheet_invoice_id": false}, ""},
+		{map[string]interface{}{"date": "2025-02-03", "validated": true}, "onaylanmış"},
+		{map[string]interface{}{"date": "2025-02-03", "timesheet_invoice_id": []interface{}{int64(5), "INV/2025/0005"}}, "faturalanmış"},
+		{map[string]interface{}{"date": "2025-01-31"}, "kilitli"},
+	} {
+		if got := entryLockReason(tt.fields, lock); (tt.want == "" && got != "") || !strings.Contains(got, tt.want) {
+			t.Errorf("entryLockReason(%v) = %q, beklenen %q", tt.fields, got, tt.want)
+		}
+	}
+	if got := entryLockReason(map[string]interface{}{"date": "2025-01-31"}, time.Time{}); got != "" {
+		t.Errorf("kilit tarihi yokken = %q", got)
+	}
+
+	t.Setenv("TIMESHEET_LOCK_DATE", "01.02.2025")
+	if _, err := lockDateFromEnv(); err == nil {
+		t.Error("geçersiz TIMESHEET_LOCK_DATE için hata bekleniyordu")
+	}
+}
+
+func TestCLIEditEntry(t *testing.T) {
+	srv := newFakeOdoo(t)
+	out := captureCLI(t, "e\n")
+
+	if err := runEditCommand([]string{"-id", "1", "-hours", "5", "-task", "cx-7010"}); err != nil {
+		t.Fatal(err)
+	}
+	rec := findRecord(srv, 1)
+	task, _ := rec["task_id"].([]interface{})
+	if rec["unit_amount"] != 5.0 || len(task) < 2 || task[1] != "CX-7010" || rec["name"] != "Geliştirme" {
+		t.Errorf("güncellenen kayıt = %v", rec)
+	}
+	if !strings.Contains(out.String(), "Saat: 6.00 → 5.00") || !strings.Contains(out.String(), "#1 güncellendi") {
+		t.Errorf("çıktı:\n%s", out)
+	}
+
+	if err := runEditCommand([]string{"-id", "1", "-hours", "-2", "-yes"}); err == nil {
+		t.Error("negatif saat kabul edildi")
+	}
+	if err := runEditCommand([]string{"-id", "1"}); err == nil {
+		t.Error("değişiklik olmadan düzenleme yapıldı")
+	}
+}
+
+func TestCLIDeleteEntry(t *testing.T) {
+	srv := newFakeOdoo(t)
+
+	// Onay verilmezse silinmez
+	out := captureCLI(t, "h\n")
+	if err := runDeleteCommand([]string{"-id", "2"}); err != nil {
+		t.Fatal(err)
+	}
+	if findRecord(srv, 2) == nil || !strings.Contains(out.String(), "İptal edildi") {
+		t.Fatalf("onaysız silindi:\n%s", out)
+	}
+
+	if err := runDeleteCommand([]string{"-id", "2", "-yes"}); err != nil {
+		t.Fatal(err)
+	}
+	if findRecord(srv, 2) != nil {
+		t.Error("kayıt silinmedi")
+	}
+
+	// Onaylanmış ve kilit tarihinden önceki kayıtlar değiştirilemez
+	locked := srv.Insert(odoo.ModelTimesheet, map[string]interface{}{
+		"date": "2025-02-05", "employee_id": 3, "project_id": 1, "name": "Onaylı", "unit_amount": 2.0, "validated": true,
+	})
+	if err := runDeleteCommand([]string{"-id", strconv.FormatInt(locked, 10), "-yes"}); err == nil || !strings.Contains(err.Error(), "onaylanmış") {
+		t.Errorf("onaylanmış kayıt silindi: %v", err)
+	}
+	t.Setenv("TIMESHEET_LOCK_DATE", "2025-02-04")
+	if err := runEditCommand([]string{"-id", "1", "-description", "Eski", "-yes"}); err == nil || !strings.Contains(err.Error(), "kilitli") {
+		t.Errorf("kilit tarihinden önceki kayıt düzenlendi: %v", err)
+	}
+	if findRecord(srv, locked) == nil || findRecord(srv, 1)["name"] != "Geliştirme" {
+		t.Error("kilitli kayıt değişti")
+	}
+}
+
+func TestCLIListEntries(t *testing.T) {
+	srv := newFakeOdoo(t)
+	out := captureCLI(t, "")
+	today := time.Now().Format(dateLayout)
+	id := srv.Insert(odoo.ModelTimesheet, map[string]interface{}{
+		"date": today, "employee_id": 3, "project_id": 1, "task_id": 1, "name": "Bugün", "unit_amount": 1.5,
+	})
+
+	if err := runEntriesCommand([]string{"-employee", "Fatih Delice"}); err != nil {
+		t.Fatal(err)
+	}
+	want := "#" + strconv.FormatInt(id, 10) + " " + today + " TEKNOSA / CX-7006 - 1.50 saat - Bugün"
+	if strings.TrimSpace(out.String()) != want {
+		t.Errorf("çıktı = %q, beklenen %q", out, want)
+	}
+}
@@ -0,0 +1,221 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/xuri/excelize/v2"
+)
+
+// Desteklenen rapor çıktı biçimleri
+const (
+	FormatText = "text"
+	FormatJSON = "json"
+	FormatCSV  = "csv"
+	FormatXLSX = "xlsx"
+)
+
+var exportFormats = []string{FormatText, FormatJSON, FormatCSV, FormatXLSX}
+
+// E-posta ve Telegram eklerinde kullanılan içerik türleri
+var exportContentTypes = map[string]string{
+	".txt":  "text/plain; charset=UTF-8",
+	".json": "application/json",
+	".csv":  "text/csv; charset=UTF-8",
+	".xlsx": "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet",
+}
+
+// XLSX çalışma kitabındaki sayfa adları
+const (
+	sheetEntries   = "Kayıtlar"
+	sheetEmployees = "Çalışanlar"
+	sheetProjects  = "Projeler"
+	sheetDaily     = "Günlük"
+	sheetMissing   = "Eksik Günler"
+)
+
+// Biçim adını doğrula; boş değer düz metin demektir
+func parseFormat(format string) (string, error) {
+	format = strings.ToLower(strings.TrimSpace(format))
+	if format == "" || format == "txt" {
+		return FormatText, nil
+	}
+	for _, f := range exportFormats {
+		if f == format {
+			return f, nil
+		}
+	}
+	return "", fmt.Errorf("desteklenmeyen biçim %q. Desteklenen biçimler: %s", format, strings.Join(exportFormats, ", "))
+}
+
+// Biçime göre dosya uzantısı
+func formatExtension(format string) string {
+	if format == FormatText {
+		return "txt"
+	}
+	return format
+}
+
+// Raporu istenen biçimde yaz
+func writeReport(w io.Writer, r *Report, format string) error {
+	switch format {
+	case FormatText:
+		renderText(w, r)
+		return nil
+	case FormatJSON:
+		return renderJSON(w, r)
+	case FormatCSV:
+		return renderCSV(w, r)
+	case FormatXLSX:
+		return renderXLSX(w, r)
+	}
+	return fmt.Errorf("desteklenmeyen biçim: %s", format)
+}
+
+// Raporun tamamını JSON olarak yaz
+func renderJSON(w io.Writer, r *Report) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(r)
+}
+
+var entryColumns = []string{"id", "date", "employee_id", "employee", "project_id", "project", "task_id", "task", "description", "hours"}
+
+// Metin hücresini CSV için hazırla. =, +, -, @, sekme ya da satır başı ile
+// başlayan değerler Excel'de formül olarak çalıştırılmasın diye başına '
+// eklenir.
+func csvText(value string) string {
+	if value != "" && strings.ContainsRune("=+-@\t\r", rune(value[0])) {
+		return "'" + value
+	}
+	return value
+}
+
+// Kayıtları CSV olarak yaz; her satır bir zaman kaydıdır
+func renderCSV(w io.Writer, r *Report) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(entryColumns); err != nil {
+		return err
+	}
+	for _, entry := range r.Entries {
+		err := cw.Write([]string{
+			strconv.FormatInt(entry.ID, 10),
+			entry.Date,
+			strconv.FormatInt(entry.Employee.ID, 10),
+			csvText(entry.Employee.Name),
+			strconv.FormatInt(entry.Project.ID, 10),
+			csvText(entry.Project.Name),
+			strconv.FormatInt(entry.Task.ID, 10),
+			csvText(entry.Task.Name),
+			csvText(entry.Description),
+			strconv.FormatFloat(entry.UnitAmount, 'f', 2, 64),
+		})
+		if err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// Raporu çok sayfalı XLSX çalışma kitabı olarak yaz: kayıtlar, çalışan
+// toplamları, proje toplamları, çalışan × gün saat tablosu ve eksik günler
+func renderXLSX(w io.Writer, r *Report) error {
+	f := excelize.NewFile()
+	defer f.Close()
+
+	if err := f.SetSheetName("Sheet1", sheetEntries); err != nil {
+		return err
+	}
+	rows := [][]interface{}{{"ID", "Tarih", "Çalışan ID", "Çalışan", "Proje ID", "Proje", "Görev ID", "Görev", "Açıklama", "Saat"}}
+	for _, entry := range r.Entries {
+		rows = append(rows, []interface{}{
+			entry.ID, entry.Date,
+			entry.Employee.ID, entry.Employee.Name,
+			entry.Project.ID, entry.Project.Name,
+			entry.Task.ID, entry.Task.Name,
+			entry.Description, entry.UnitAmount,
+		})
+	}
+	if err := writeSheet(f, sheetEntries, rows); err != nil {
+		return err
+	}
+
+	if err := writeTotalsSheet(f, sheetEmployees, "Çalışan", r.Roster, r.TotalHours); err != nil {
+		return err
+	}
+	if err := writeTotalsSheet(f, sheetProjects, "Proje", r.Projects, r.TotalHours); err != nil {
+		return err
+	}
+
+	// Günlük tablo: satırlar çalışanlar, sütunlar dönemin günleri
+	days := r.Period.Days()
+	header := []interface{}{"Çalışan"}
+	for _, day := range days {
+		header = append(header, day.Format(dateLayout))
+	}
+	header = append(header, "Toplam")
+	rows = [][]interface{}{header}
+
+	daily := make(map[string]map[string]float64)
+	for _, day := range r.Days {
+		daily[day.Date] = make(map[string]float64)
+		for _, emp := range day.Employees {
+			daily[day.Date][emp.Employee] = emp.Hours
+		}
+	}
+	for _, emp := range r.Roster {
+		row := []interface{}{emp.Name}
+		for _, day := range days {
+			row = append(row, daily[day.Format(dateLayout)][emp.Name])
+		}
+		row = append(row, emp.Hours)
+		rows = append(rows, row)
+	}
+	if err := writeSheet(f, sheetDaily, rows); err != nil {
+		return err
+	}
+
+	rows = [][]interface{}{{"Çalışan", "Tarih", "Girilen Saat", "Beklenen Saat"}}
+	for _, day := range r.Missing {
+		rows = append(rows, []interface{}{day.Employee, day.Date, day.Hours, day.Expected})
+	}
+	if err := writeSheet(f, sheetMissing, rows); err != nil {
+		return err
+	}
+
+	return f.Write(w)
+}
+
+// Ad/saat toplamlarını, en altta genel toplamla birlikte sayfaya yaz
+func writeTotalsSheet(f *excelize.File, sheet, label string, totals []HoursTotal, total float64) error {
+	rows := [][]interface{}{{label, "Saat"}}
+	for _, t := range totals {
+		rows = append(rows, []interface{}{t.Name, t.Hours})
+	}
+	rows = append(rows, []interface{}{"Toplam", total})
+	return writeSheet(f, sheet, rows)
+}
+
+// Satırları sayfaya A1'den başlayarak yaz; sayfa yoksa oluştur
+func writeSheet(f *excelize.File, sheet string, rows [][]interface{}) error {
+	if idx, _ := f.GetSheetIndex(sheet); idx < 0 {
+		if _, err := f.NewSheet(sheet); err != nil {
+			return err
+		}
+	}
+	for i, row := range rows {
+		cell, err := excelize.CoordinatesToCellName(1, i+1)
+		if err != nil {
+			return err
+		}
+		if err := f.SetSheetRow(sheet, cell, &row); err != nil {
+			return fmt.Errorf("%s sayfası yazılamadı: %v", sheet, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,170 @@
+package main
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"odoo-efor-tracker/odoo"
+
+	"github.com/xuri/excelize/v2"
+)
+
+func sampleReport() *Report {
+	period := Period{
+		Start: time.Date(2025, 2, 3, 0, 0, 0, 0, time.UTC),
+		End:   time.Date(2025, 2, 5, 0, 0, 0, 0, time.UTC),
+	}
+	entries := []odoo.TimesheetLine{
+		line(1, "2025-02-03", "Ali", "CX", 8),
+		line(2, "2025-02-04", "Ali", "CX", 6.5),
+	}
+	entries[1].Task = odoo.Many2One{ID: 3, Name: "CX-7006"}
+	entries[1].Description = "Geliştirme, \"test\""
+	r := buildReport(period, entries, []odoo.Employee{{ID: 9, Name: "Zeynep"}})
+	r.applyCalendar(newWorkCalendar())
+	return r
+}
+
+func TestParseFormat(t *testing.T) {
+	for in, want := range map[string]string{"": FormatText, "txt": FormatText, "JSON": FormatJSON, " xlsx ": FormatXLSX} {
+		got, err := parseFormat(in)
+		if err != nil || got != want {
+			t.Errorf("parseFormat(%q) = %q, %v; beklenen %q", in, got, err, want)
+		}
+	}
+	if _, err := parseFormat("pdf"); err == nil {
+		t.Error("parseFormat(pdf) hata vermedi")
+	}
+}
+
+func TestRenderJSON(t *testing.T) {
+	var buf bytes.Buffer
+	if err := renderJSON(&buf, sampleReport()); err != nil {
+		t.Fatal(err)
+	}
+
+	var got struct {
+		Period     map[string]string `json:"period"`
+		TotalHours float64           `json:"total_hours"`
+		Entries    []struct {
+			Date     string        `json:"date"`
+			Employee odoo.Many2One `json:"employee"`
+			Task     odoo.Many2One `json:"task"`
+			Hours    float64       `json:"hours"`
+		} `json:"entries"`
+		Roster []HoursTotal `json:"roster"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+		t.Fatalf("JSON çözülemedi: %v\n%s", err, buf.String())
+	}
+	if got.Period["start"] != "2025-02-03" || got.Period["end"] != "2025-02-05" {
+		t.Errorf("period = %v", got.Period)
+	}
+	if got.TotalHours != 14.5 || len(got.Entries) != 2 {
+		t.Errorf("total_hours = %v, entries = %d", got.TotalHours, len(got.Entries))
+	}
+	if e := got.Entries[1]; e.Task.Name != "CX-7006" || e.Hours != 6.5 || e.Employee.Name != "Ali" {
+		t.Errorf("entries[1] = %+v", e)
+	}
+	if len(got.Roster) != 2 || got.Roster[1] != (HoursTotal{"Zeynep", 0}) {
+		t.Errorf("roster = %v", got.Roster)
+	}
+}
+
+func TestRenderCSV(t *testing.T) {
+	var buf bytes.Buffer
+	if err := renderCSV(&buf, sampleReport()); err != nil {
+		t.Fatal(err)
+	}
+	rows, err := csv.NewReader(&buf).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rows) != 3 {
+		t.Fatalf("%d satır, beklenen 3", len(rows))
+	}
+	if strings.Join(rows[0], ",") != strings.Join(entryColumns, ",") {
+		t.Errorf("başlık = %v", rows[0])
+	}
+	want := []string{"2", "2025-02-04", "2", "Ali", "1", "CX", "3", "CX-7006", "Geliştirme, \"test\"", "6.50"}
+	if strings.Join(rows[2], "|") != strings.Join(want, "|") {
+		t.Errorf("satır = %v, beklenen %v", rows[2], want)
+	}
+
+	// Formül gibi başlayan metinler Excel'de çalıştırılmaz
+	r := sampleReport()
+	r.Entries[0].Description = `=HYPERLINK("http://example.com","tıkla")`
+	r.Entries[0].Project.Name = "@CX"
+	r.Entries[1].Description = "-2 saat düzeltme"
+	buf.Reset()
+	if err := renderCSV(&buf, r); err != nil {
+		t.Fatal(err)
+	}
+	if rows, err = csv.NewReader(&buf).ReadAll(); err != nil {
+		t.Fatal(err)
+	}
+	if got := rows[1][8]; got != `'=HYPERLINK("http://example.com","tıkla")` {
+		t.Errorf("açıklama = %q", got)
+	}
+	if rows[1][5] != "'@CX" || rows[2][8] != "'-2 saat düzeltme" || rows[2][9] != "6.50" {
+		t.Errorf("satırlar = %v", rows[1:])
+	}
+}
+
+func TestRenderXLSX(t *testing.T) {
+	var buf bytes.Buffer
+	if err := renderXLSX(&buf, sampleReport()); err != nil {
+		t.Fatal(err)
+	}
+	f, err := excelize.OpenReader(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	if got := strings.Join(f.GetSheetList(), ","); got != "Kayıtlar,Çalışanlar,Projeler,Günlük,Eksik Günler" {
+		t.Errorf("sayfalar = %s", got)
+	}
+
+	for _, c := range []struct{ sheet, cell, want string }{
+		{sheetEntries, "H3", "CX-7006"},
+		{sheetEntries, "J3", "6.5"},
+		{sheetEmployees, "A3", "Zeynep"},
+		{sheetEmployees, "B4", "14.5"},
+		{sheetProjects, "A2", "CX"},
+		{sheetDaily, "D1", "2025-02-05"},
+		{sheetDaily, "C2", "6.5"},
+		{sheetDaily, "D2", "0"},
+		{sheetDaily, "E2", "14.5"},
+		{sheetDaily, "A3", "Zeynep"},
+	} {
+		got, err := f.GetCellValue(c.sheet, c.cell)
+		if err != nil || got != c.want {
+			t.Errorf("%s!%s = %q, %v; beklenen %q", c.sheet, c.cell, got, err, c.want)
+		}
+	}
+}
+
+func TestGenerateReportFormats(t *testing.T) {
+	newFakeOdoo(t)
+	chdirTemp(t)
+
+	period, _ := parsePeriod("2025-02-03", time.Now())
+	for _, format := range []string{FormatJSON, FormatCSV, FormatXLSX} {
+		_, outputFileName, err := generateReport(period, "", "", format, "")
+		if err != nil {
+			t.Fatalf("%s: %v", format, err)
+		}
+		if !strings.HasSuffix(outputFileName, "."+format) {
+			t.Errorf("%s: dosya adı %s", format, outputFileName)
+		}
+		if info, err := os.Stat(outputFileName); err != nil || info.Size() == 0 {
+			t.Errorf("%s: rapor dosyası yazılmadı: %v", format, err)
+		}
+	}
+}
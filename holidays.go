@@ -0,0 +1,294 @@
+package main
+
+import (
+	"bufio"
+	"embed"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+	"unicode"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Uygulamayla birlikte gelen ülke tatil takvimleri (holidays/<ad>.yaml)
+//
+//go:embed holidays/*.yaml
+var bundledHolidays embed.FS
+
+const (
+	// Varsayılan ülke tatil takvimi
+	DefaultHolidayCalendar = "tr"
+	// Şirkete özel kapanış günleri dosyasının varsayılan yolu
+	DefaultHolidaysFile = "holidays.yaml"
+)
+
+// PublicHoliday, bir tatil günü ya da End ile birlikte bir tatil aralığı.
+// Half, yalnızca öğleden sonranın tatil olduğu günlerdir (arife).
+type PublicHoliday struct {
+	Date string `yaml:"date"`
+	End  string `yaml:"end"`
+	Name string `yaml:"name"`
+	Half bool   `yaml:"half"`
+}
+
+// Tatil dosyası (YAML)
+type holidayFile struct {
+	Holidays []PublicHoliday `yaml:"holidays"`
+}
+
+// HolidayCalendar, ülke tatilleri ile şirket kapanışlarının gün bazında
+// birleşimi. Aynı gün hem tam hem yarım gün tatil varsa tam gün geçerlidir.
+type HolidayCalendar struct {
+	full map[string]string // tarih → tatil adı
+	half map[string]string // tarih → arife adı
+}
+
+func newHolidayCalendar() *HolidayCalendar {
+	return &HolidayCalendar{
+		full: make(map[string]string),
+		half: make(map[string]string),
+	}
+}
+
+// Tatili (aralıksa her gününü) takvime ekle
+func (c *HolidayCalendar) Add(h PublicHoliday) error {
+	start, err := time.Parse(dateLayout, h.Date)
+	if err != nil {
+		return fmt.Errorf("%s: geçersiz tarih %q (YYYY-MM-DD)", h.Name, h.Date)
+	}
+	end := start
+	if h.End != "" {
+		if end, err = time.Parse(dateLayout, h.End); err != nil || end.Before(start) {
+			return fmt.Errorf("%s: geçersiz bitiş tarihi %q", h.Name, h.End)
+		}
+	}
+
+	for day := start; !day.After(end); day = day.AddDate(0, 0, 1) {
+		date := day.Format(dateLayout)
+		if h.Half {
+			if _, ok := c.full[date]; !ok {
+				c.half[date] = h.Name
+			}
+			continue
+		}
+		c.full[date] = h.Name
+		delete(c.half, date)
+	}
+	return nil
+}
+
+// Günün tatil adı ve yarım gün olup olmadığı; tatil değilse ok false
+func (c *HolidayCalendar) Lookup(day time.Time) (name string, half, ok bool) {
+	date := day.Format(dateLayout)
+	if name, ok := c.full[date]; ok {
+		return name, false, true
+	}
+	if name, ok := c.half[date]; ok {
+		return name, true, true
+	}
+	return "", false, false
+}
+
+// Gün hafta sonu ya da tam gün tatil değilse iş günüdür. Arifeler iş
+// günü sayılır.
+func (c *HolidayCalendar) IsWorkday(day time.Time) bool {
+	if day.Weekday() == time.Saturday || day.Weekday() == time.Sunday {
+		return false
+	}
+	_, half, ok := c.Lookup(day)
+	return !ok || half
+}
+
+// Tatilleri çalışma takvimine aktar
+func (c *HolidayCalendar) apply(cal *WorkCalendar) {
+	for date, name := range c.full {
+		cal.AddHoliday(date, name)
+	}
+	for date, name := range c.half {
+		cal.AddHalfDay(date, name)
+	}
+}
+
+// Ülke takvimini (HOLIDAY_CALENDAR; varsayılan "tr", kapatmak için "none")
+// ve şirket kapanışlarını (HOLIDAYS_FILE; varsayılan holidays.yaml) yükle.
+// HOLIDAY_CALENDAR, gömülü takvim adı yerine bir .yaml ya da .ics dosyası
+// da olabilir. Şirket dosyası yoksa yalnızca ülke takvimi kullanılır.
+func loadHolidayCalendar() (*HolidayCalendar, error) {
+	cal := newHolidayCalendar()
+
+	name := os.Getenv("HOLIDAY_CALENDAR")
+	if name == "" {
+		name = DefaultHolidayCalendar
+	}
+	if name != "none" {
+		holidays, err := readHolidayCalendar(name)
+		if err != nil {
+			return nil, err
+		}
+		for _, h := range holidays {
+			if err := cal.Add(h); err != nil {
+				return nil, fmt.Errorf("tatil takvimi (%s): %v", name, err)
+			}
+		}
+	}
+
+	path := os.Getenv("HOLIDAYS_FILE")
+	if path == "" {
+		path = DefaultHolidaysFile
+	}
+	closures, err := readHolidayFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return cal, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	for _, h := range closures {
+		if err := cal.Add(h); err != nil {
+			return nil, fmt.Errorf("kapanış günleri (%s): %v", path, err)
+		}
+	}
+	return cal, nil
+}
+
+// Gömülü takvimi adıyla, değilse dosyadan oku
+func readHolidayCalendar(name string) ([]PublicHoliday, error) {
+	if filepath.Ext(name) != "" {
+		return readHolidayFile(name)
+	}
+	data, err := bundledHolidays.ReadFile("holidays/" + name + ".yaml")
+	if err != nil {
+		return nil, fmt.Errorf("bilinmeyen tatil takvimi: %s", name)
+	}
+	return parseHolidayYAML(data)
+}
+
+// Tatil dosyasını uzantısına göre (.ics ya da YAML) oku
+func readHolidayFile(path string) ([]PublicHoliday, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("tatil dosyası okunamadı: %v", err)
+	}
+	defer f.Close()
+
+	var holidays []PublicHoliday
+	if strings.EqualFold(filepath.Ext(path), ".ics") {
+		holidays, err = parseICal(f)
+	} else {
+		var data []byte
+		if data, err = io.ReadAll(f); err == nil {
+			holidays, err = parseHolidayYAML(data)
+		}
+	}
+	if err != nil {
+		return nil, fmt.Errorf("tatil dosyası çözümlenemedi (%s): %v", path, err)
+	}
+	return holidays, nil
+}
+
+func parseHolidayYAML(data []byte) ([]PublicHoliday, error) {
+	var file holidayFile
+	if err := yaml.Unmarshal(data, &file); err != nil {
+		return nil, err
+	}
+	return file.Holidays, nil
+}
+
+// iCal (RFC 5545) dosyasındaki VEVENT kayıtlarını tatillere çevir. Tüm gün
+// etkinliklerinde DTEND hariçtir. Saatli etkinlikler ve adında "arife"
+// geçenler yarım gün sayılır.
+func parseICal(r io.Reader) ([]PublicHoliday, error) {
+	var (
+		holidays []PublicHoliday
+		event    map[string]string
+		lines    []string
+	)
+
+	// Katlanmış satırları (boşluk ya da sekmeyle başlayan) birleştir
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if len(lines) > 0 && (strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t")) {
+			lines[len(lines)-1] += line[1:]
+			continue
+		}
+		lines = append(lines, line)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	for _, line := range lines {
+		key, value, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+		name, _, _ := strings.Cut(key, ";")
+		switch strings.ToUpper(name) {
+		case "BEGIN":
+			if strings.EqualFold(value, "VEVENT") {
+				event = make(map[string]string)
+			}
+		case "END":
+			if strings.EqualFold(value, "VEVENT") && event != nil {
+				h, err := icalHoliday(event)
+				if err != nil {
+					return nil, err
+				}
+				holidays = append(holidays, h)
+				event = nil
+			}
+		case "DTSTART", "DTEND", "SUMMARY":
+			if event != nil {
+				event[strings.ToUpper(name)] = value
+			}
+		}
+	}
+	return holidays, nil
+}
+
+func icalHoliday(event map[string]string) (PublicHoliday, error) {
+	summary := strings.NewReplacer(`\,`, ",", `\;`, ";", `\n`, " ", `\\`, `\`).Replace(event["SUMMARY"])
+	h := PublicHoliday{Name: summary}
+
+	start, timed, err := icalDate(event["DTSTART"])
+	if err != nil {
+		return h, fmt.Errorf("%s: geçersiz DTSTART %q", summary, event["DTSTART"])
+	}
+	h.Date = start.Format(dateLayout)
+	h.Half = timed || strings.Contains(strings.ToLowerSpecial(unicode.TurkishCase, summary), "arife")
+
+	if event["DTEND"] != "" && !timed {
+		end, _, err := icalDate(event["DTEND"])
+		if err != nil {
+			return h, fmt.Errorf("%s: geçersiz DTEND %q", summary, event["DTEND"])
+		}
+		if last := end.AddDate(0, 0, -1); last.After(start) {
+			h.End = last.Format(dateLayout)
+		}
+	}
+	return h, nil
+}
+
+// iCal DATE (20250101) ya da DATE-TIME (20250101T130000[Z]) değeri; UTC
+// değerler yerel saate çevrilir
+func icalDate(value string) (t time.Time, timed bool, err error) {
+	if strings.HasSuffix(value, "Z") {
+		t, err = time.Parse("20060102T150405Z", value)
+		return t.In(time.Local), true, err
+	}
+	if len(value) > 8 {
+		t, err = time.Parse("20060102T150405", value)
+		return t, true, err
+	}
+	t, err = time.Parse("20060102", value)
+	return t, false, err
+}
@@ -0,0 +1,120 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestBundledHolidays(t *testing.T) {
+	t.Setenv("HOLIDAY_CALENDAR", "")
+	t.Setenv("HOLIDAYS_FILE", filepath.Join(t.TempDir(), "yok.yaml"))
+
+	holidays, err := loadHolidayCalendar()
+	if err != nil {
+		t.Fatal(err)
+	}
+	cal := newWorkCalendar()
+	holidays.apply(cal)
+
+	for _, tt := range []struct {
+		date     string
+		workday  bool
+		expected float64
+	}{
+		{"2025-10-27", true, 8},
+		{"2025-10-28", true, 4},  // Cumhuriyet Bayramı arifesi
+		{"2025-10-29", false, 0}, // Cumhuriyet Bayramı
+		{"2025-03-31", false, 0}, // Ramazan Bayramı
+		{"2025-06-05", true, 4},  // Kurban Bayramı arifesi
+		{"2025-06-09", false, 0},
+		{"2025-06-10", true, 8},
+	} {
+		day, _ := time.Parse(dateLayout, tt.date)
+		if got := holidays.IsWorkday(day); got != tt.workday {
+			t.Errorf("IsWorkday(%s) = %v, beklenen %v", tt.date, got, tt.workday)
+		}
+		if got := cal.Expected(1, day); got != tt.expected {
+			t.Errorf("Expected(%s) = %.2f, beklenen %.2f", tt.date, got, tt.expected)
+		}
+	}
+}
+
+func TestParseICal(t *testing.T) {
+	f, err := os.Open("testdata/holidays.ics")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	holidays, err := parseICal(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []PublicHoliday{
+		// Tüm gün etkinliklerinde DTEND hariç
+		{Date: "2025-08-11", End: "2025-08-15", Name: "Yaz kapanışı, tüm ofisler"},
+		// Saatli etkinlik yarım gün; katlanmış satır birleştirilir
+		{Date: "2025-12-31", Name: "Yılsonu kutlaması", Half: true},
+		{Date: "2025-06-04", Name: "Kurban Bayramı Arifesi (şirket)", Half: true},
+	}
+	if !reflect.DeepEqual(holidays, want) {
+		t.Errorf("parseICal =\n%+v\nbeklenen\n%+v", holidays, want)
+	}
+}
+
+func TestLoadHolidayCalendarClosures(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "holidays.yaml")
+	err := os.WriteFile(path, []byte(`
+holidays:
+  - date: 2025-10-28
+    name: Bayram köprüsü
+  - date: 2025-12-26
+    end: 2025-12-31
+    name: Yılsonu kapanışı
+`), 0o644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("HOLIDAY_CALENDAR", "tr")
+	t.Setenv("HOLIDAYS_FILE", path)
+
+	holidays, err := loadHolidayCalendar()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for date, want := range map[string]string{
+		"2025-10-28": "Bayram köprüsü", // Arife tam gün kapanışla ezilir
+		"2025-10-29": "Cumhuriyet Bayramı",
+		"2025-12-29": "Yılsonu kapanışı",
+	} {
+		day, _ := time.Parse(dateLayout, date)
+		if name, half, ok := holidays.Lookup(day); !ok || half || name != want {
+			t.Errorf("Lookup(%s) = %q, %v, %v", date, name, half, ok)
+		}
+	}
+
+	// Ülke takvimi kapatılabilir ya da iCal dosyasından okunabilir
+	t.Setenv("HOLIDAY_CALENDAR", "none")
+	if holidays, err = loadHolidayCalendar(); err != nil {
+		t.Fatal(err)
+	}
+	if day, _ := time.Parse(dateLayout, "2025-10-29"); !holidays.IsWorkday(day) {
+		t.Error("HOLIDAY_CALENDAR=none iken ülke tatilleri uygulanmamalı")
+	}
+
+	t.Setenv("HOLIDAY_CALENDAR", "testdata/holidays.ics")
+	if holidays, err = loadHolidayCalendar(); err != nil {
+		t.Fatal(err)
+	}
+	if day, _ := time.Parse(dateLayout, "2025-08-13"); holidays.IsWorkday(day) {
+		t.Error("iCal takvimindeki kapanış uygulanmadı")
+	}
+
+	t.Setenv("HOLIDAY_CALENDAR", "xx")
+	if _, err := loadHolidayCalendar(); err == nil {
+		t.Error("bilinmeyen takvim için hata bekleniyordu")
+	}
+}
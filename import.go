@@ -0,0 +1,389 @@
+package main
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"odoo-efor-tracker/odoo"
+)
+
+// Toplu içe aktarmada tek create çağrısıyla gönderilen en fazla kayıt
+const importBatchSize = 50
+
+// ImportRow, içe aktarılan dosyadaki tek kayıt
+type ImportRow struct {
+	Line        int     `json:"-"` // CSV'de dosya satırı, JSON'da sıra numarası
+	Date        string  `json:"date"`
+	Project     string  `json:"project"`
+	Task        string  `json:"task"`
+	Description string  `json:"description"`
+	Hours       float64 `json:"hours"`
+	Employee    string  `json:"employee"` // Boşsa varsayılan çalışan
+	Err         error   `json:"-"`        // Dosya okunurken bulunan hata; satır içe aktarılmaz
+}
+
+// CSV başlıklarının alan adları; Türkçe başlıklar da kabul edilir
+var importColumns = map[string]string{
+	"date": "date", "tarih": "date",
+	"project": "project", "proje": "project",
+	"task": "task", "görev": "task", "gorev": "task",
+	"description": "description", "açıklama": "description", "aciklama": "description",
+	"hours": "hours", "saat": "hours",
+	"employee": "employee", "çalışan": "employee", "calisan": "employee",
+}
+
+// Dosyayı uzantısına göre CSV ya da JSON olarak oku
+func readImportFile(path string) ([]ImportRow, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("dosya okunamadı: %v", err)
+	}
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".csv":
+		return parseImportCSV(data)
+	case ".json":
+		return parseImportJSON(data)
+	}
+	return nil, fmt.Errorf("desteklenmeyen dosya türü %q (.csv ya da .json)", filepath.Ext(path))
+}
+
+// csvRecord, başlık satırlı CSV'nin tek satırı; alanlar küçük harfe
+// çevrilmiş başlık adlarıyla tutulur
+type csvRecord struct {
+	Line   int
+	Fields map[string]string
+}
+
+// Başlık satırlı CSV'yi oku. Excel'in Türkçe ayarlarla ürettiği ";" ayraçlı
+// dosyalar da desteklenir.
+func readCSV(data []byte) ([]string, []csvRecord, error) {
+	data = bytes.TrimPrefix(data, []byte("\ufeff"))
+	r := csv.NewReader(bytes.NewReader(data))
+	header, _, _ := strings.Cut(string(data), "\n")
+	if strings.Count(header, ";") > strings.Count(header, ",") {
+		r.Comma = ';'
+	}
+	r.TrimLeadingSpace = true
+	r.FieldsPerRecord = -1
+
+	head, err := r.Read()
+	if err != nil {
+		return nil, nil, fmt.Errorf("CSV başlığı okunamadı: %v", err)
+	}
+	for i := range head {
+		head[i] = strings.ToLower(strings.TrimSpace(head[i]))
+	}
+
+	var records []csvRecord
+	for {
+		fields, err := r.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, nil, fmt.Errorf("CSV okunamadı: %v", err)
+		}
+		line, _ := r.FieldPos(0)
+		rec := csvRecord{Line: line, Fields: make(map[string]string, len(head))}
+		for i, value := range fields {
+			if i < len(head) {
+				rec.Fields[head[i]] = strings.TrimSpace(value)
+			}
+		}
+		records = append(records, rec)
+	}
+	return head, records, nil
+}
+
+// Saat değerini ondalık virgülü de kabul ederek çöz
+func parseHours(value string) (float64, error) {
+	return strconv.ParseFloat(strings.Replace(value, ",", ".", 1), 64)
+}
+
+// İçe aktarma biçimindeki CSV'yi oku
+func parseImportCSV(data []byte) ([]ImportRow, error) {
+	head, records, err := readCSV(data)
+	if err != nil {
+		return nil, err
+	}
+	columns := map[string]string{} // alan -> başlık
+	for _, name := range head {
+		if field := importColumns[name]; field != "" {
+			columns[field] = name
+		}
+	}
+	for _, required := range []string{"date", "project", "description", "hours"} {
+		if columns[required] == "" {
+			return nil, fmt.Errorf("CSV'de %q sütunu yok", required)
+		}
+	}
+
+	rows := make([]ImportRow, 0, len(records))
+	for _, rec := range records {
+		get := func(field string) string {
+			if name := columns[field]; name != "" {
+				return rec.Fields[name]
+			}
+			return ""
+		}
+		row := ImportRow{
+			Line:        rec.Line,
+			Date:        get("date"),
+			Project:     get("project"),
+			Task:        get("task"),
+			Description: get("description"),
+			Employee:    get("employee"),
+		}
+		// Okunamayan hücreler yalnızca kendi satırını geçersiz kılar
+		var errs ValidationError
+		if _, err := time.Parse(dateLayout, row.Date); err != nil {
+			errs = append(errs, FieldError{"tarih", fmt.Sprintf("%q YYYY-MM-DD biçiminde olmalı", row.Date)})
+		}
+		if hours, err := parseHours(get("hours")); err != nil {
+			errs = append(errs, FieldError{"saat", fmt.Sprintf("%q sayı olmalı", get("hours"))})
+		} else {
+			row.Hours = hours
+		}
+		row.Err = errs.orNil()
+		rows = append(rows, row)
+	}
+	return rows, nil
+}
+
+// Satırları içe aktarma biçiminde CSV olarak yaz
+func writeImportCSV(w io.Writer, rows []ImportRow) error {
+	cw := csv.NewWriter(w)
+	cw.Write([]string{"date", "project", "task", "description", "hours", "employee"})
+	for _, row := range rows {
+		cw.Write([]string{row.Date, row.Project, row.Task, row.Description,
+			strconv.FormatFloat(row.Hours, 'f', -1, 64), row.Employee})
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// Kayıt nesnelerinden oluşan JSON dizisini oku
+func parseImportJSON(data []byte) ([]ImportRow, error) {
+	var rows []ImportRow
+	if err := json.Unmarshal(data, &rows); err != nil {
+		return nil, fmt.Errorf("JSON okunamadı: %v", err)
+	}
+	for i := range rows {
+		rows[i].Line = i + 1
+	}
+	return rows, nil
+}
+
+// ImportItem, çözümlenip doğrulanmış içe aktarma satırı
+type ImportItem struct {
+	Row        ImportRow
+	Entry      *ResolvedEntry
+	EmployeeID int64
+	Employee   string // Çalışanın Odoo'daki adı
+	Duplicate  bool   // Aynı kayıt Odoo'da zaten var; atlanır
+	Err        error  // Çözümleme, doğrulama ya da oluşturma hatası
+	ID         int64  // Oluşturulan kaydın ID'si
+}
+
+// Satır oluşturulacak mı
+func (it *ImportItem) Pending() bool {
+	return it.Err == nil && !it.Duplicate && it.ID == 0
+}
+
+// Satırları çözümle ve doğrula. Proje ve görev adları bot ile aynı kurallarla
+// çözümlenir; günlük üst sınır, Odoo'daki kayıtlara dosyadaki önceki satırlar
+// da eklenerek kontrol edilir. Odoo'da birebir aynısı bulunan satırlar
+// yinelenen olarak işaretlenir, böylece aynı dosya tekrar içe aktarılabilir.
+// defaultEmployee, çalışanı belirtilmeyen satırlarda kullanılır.
+func prepareImport(client odoo.API, rows []ImportRow, defaultEmployee *odoo.Employee, rules ValidationRules, now time.Time) []*ImportItem {
+	employees := map[string]*odoo.Employee{}
+	days := map[string][]odoo.TimesheetLine{}
+	pending := map[string]float64{}
+
+	// Günlük üst sınır aşağıda dosyadaki satırlarla birlikte kontrol edilir
+	fieldRules := rules
+	fieldRules.DailyCap = 0
+
+	items := make([]*ImportItem, 0, len(rows))
+	for _, row := range rows {
+		item := &ImportItem{Row: row}
+		items = append(items, item)
+
+		if row.Err != nil {
+			item.Err = row.Err
+			continue
+		}
+		if row.Project == "" {
+			item.Err = fmt.Errorf("proje belirtilmedi")
+			continue
+		}
+
+		emp := defaultEmployee
+		if row.Employee != "" {
+			if emp = employees[row.Employee]; emp == nil {
+				var err error
+				if emp, err = resolveEmployee(client, row.Employee); err != nil {
+					item.Err = err
+					continue
+				}
+				employees[row.Employee] = emp
+			}
+		}
+		if emp == nil {
+			item.Err = fmt.Errorf("çalışan belirtilmedi")
+			continue
+		}
+		item.EmployeeID, item.Employee = emp.ID, emp.Name
+
+		entry, err := resolveTimeEntry(client, row.Date, row.Project, row.Task, row.Description, row.Hours)
+		if err != nil {
+			item.Err = err
+			continue
+		}
+		item.Entry = entry
+		if err := validateEntry(client, fieldRules, emp.ID, entry.NewTimeEntry, 0, now); err != nil {
+			item.Err = err
+			continue
+		}
+
+		key := fmt.Sprintf("%d/%s", emp.ID, row.Date)
+		lines, ok := days[key]
+		if !ok {
+			if err := client.SearchRead(odoo.ModelTimesheet,
+				odoo.NewDomain().Where("employee_id", "=", emp.ID).Where("date", "=", row.Date),
+				nil, &lines); err != nil {
+				item.Err = err
+				continue
+			}
+			days[key] = lines
+		}
+		existing := 0.0
+		for _, line := range lines {
+			existing += line.UnitAmount
+			if line.Project.ID == entry.ProjectID && line.Task.ID == entry.TaskID &&
+				line.UnitAmount == entry.Hours && line.Description == entry.Description {
+				item.Duplicate = true
+			}
+		}
+		if item.Duplicate {
+			continue
+		}
+		if msg := rules.checkDailyCap(row.Date, existing+pending[key], entry.Hours); msg != "" {
+			item.Err = ValidationError{{"saat", msg}}
+			continue
+		}
+		pending[key] += entry.Hours
+	}
+	return items
+}
+
+// Bekleyen satırları batchSize'lık gruplar halinde oluştur. Bir grup
+// reddedilirse hatalı satırları bulmak için gruptaki satırlar tek tek denenir.
+func createImportItems(client odoo.API, items []*ImportItem, batchSize int) {
+	var batch []*ImportItem
+	flush := func() {
+		if len(batch) == 0 {
+			return
+		}
+		values := make([]odoo.Values, 0, len(batch))
+		for _, item := range batch {
+			values = append(values, timesheetValues(item.EmployeeID, item.Entry.NewTimeEntry))
+		}
+		ids, err := client.CreateMany(odoo.ModelTimesheet, values)
+		if err == nil {
+			for i, item := range batch {
+				if i < len(ids) {
+					item.ID = ids[i]
+				}
+			}
+		} else {
+			for i, item := range batch {
+				if item.ID, err = client.Create(odoo.ModelTimesheet, values[i]); err != nil {
+					item.Err = fmt.Errorf("zaman kaydı oluşturma hatası: %v", err)
+				}
+			}
+		}
+		batch = batch[:0]
+	}
+
+	if batchSize <= 0 {
+		batchSize = importBatchSize
+	}
+	for _, item := range items {
+		if !item.Pending() {
+			continue
+		}
+		batch = append(batch, item)
+		if len(batch) == batchSize {
+			flush()
+		}
+	}
+	flush()
+}
+
+// Satırın tek satırlık gösterimi
+func (it *ImportItem) describe() string {
+	if it.Entry == nil {
+		return fmt.Sprintf("%s %s / %s - %.2f saat - %s",
+			it.Row.Date, it.Row.Project, it.Row.Task, it.Row.Hours, it.Row.Description)
+	}
+	task := ""
+	if it.Entry.TaskName != "" {
+		task = " / " + it.Entry.TaskName
+	}
+	return fmt.Sprintf("%s %s: %s%s - %.2f saat - %s",
+		it.Entry.Date, it.Employee, it.Entry.ProjectName, task, it.Entry.Hours, it.Entry.Description)
+}
+
+// İçe aktarma planını ya da sonucunu satır satır yaz. Satırlar "+" (eklenecek),
+// "✓" (eklendi), "=" (zaten var) ve "✗" (hatalı) ile işaretlenir.
+func writeImportReport(w io.Writer, items []*ImportItem) {
+	var added, pending, duplicate, failed int
+	for _, it := range items {
+		switch {
+		case it.Err != nil:
+			failed++
+			msg := strings.ReplaceAll(it.Err.Error(), "\n", "\n    ")
+			fmt.Fprintf(w, "✗ satır %d: %s\n    %s\n", it.Row.Line, it.describe(), msg)
+		case it.Duplicate:
+			duplicate++
+			fmt.Fprintf(w, "= satır %d: %s (zaten var)\n", it.Row.Line, it.describe())
+		case it.ID > 0:
+			added++
+			fmt.Fprintf(w, "✓ satır %d: #%d %s\n", it.Row.Line, it.ID, it.describe())
+		default:
+			pending++
+			note := ""
+			if !it.Entry.Exact {
+				note = " (yaklaşık eşleşme)"
+			}
+			fmt.Fprintf(w, "+ satır %d: %s%s\n", it.Row.Line, it.describe(), note)
+		}
+	}
+
+	var parts []string
+	if pending > 0 {
+		parts = append(parts, fmt.Sprintf("%d eklenecek", pending))
+	}
+	if added > 0 {
+		parts = append(parts, fmt.Sprintf("%d eklendi", added))
+	}
+	if duplicate > 0 {
+		parts = append(parts, fmt.Sprintf("%d zaten var", duplicate))
+	}
+	if failed > 0 {
+		parts = append(parts, fmt.Sprintf("%d hatalı", failed))
+	}
+	if len(parts) == 0 {
+		parts = append(parts, "kayıt yok")
+	}
+	fmt.Fprintf(w, "\nToplam %d satır: %s\n", len(items), strings.Join(parts, ", "))
+}
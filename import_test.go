@@ -0,0 +1,142 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"odoo-efor-tracker/odoo"
+)
+
+func TestParseImportCSV(t *testing.T) {
+	data := "\ufefftarih;proje;görev;açıklama;saat;çalışan\n" +
+		"2025-02-05;TEKNOSA;CX-7006;Analiz;1,5;\n" +
+		"2025-02-05; CX Portal ;;\"Toplantı; planlama\";2;Fatih\n"
+	rows, err := parseImportCSV([]byte(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rows) != 2 {
+		t.Fatalf("%d satır okundu, beklenen 2", len(rows))
+	}
+	if r := rows[0]; r.Line != 2 || r.Project != "TEKNOSA" || r.Task != "CX-7006" || r.Hours != 1.5 || r.Employee != "" {
+		t.Errorf("satır 1 = %+v", r)
+	}
+	if r := rows[1]; r.Line != 3 || r.Project != "CX Portal" || r.Description != "Toplantı; planlama" || r.Employee != "Fatih" {
+		t.Errorf("satır 2 = %+v", r)
+	}
+
+	if _, err := parseImportCSV([]byte("date,project,hours\n")); err == nil || !strings.Contains(err.Error(), "description") {
+		t.Errorf("eksik sütun hatası = %v", err)
+	}
+
+	// Geçersiz hücreler okumayı durdurmaz, yalnızca kendi satırını işaretler
+	rows, err = parseImportCSV([]byte("date,project,description,hours\n" +
+		"2025-02-05,TEKNOSA,x,iki\n05.02.2025,TEKNOSA,y,1\n2025-02-05,TEKNOSA,z,2\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rows) != 3 {
+		t.Fatalf("%d satır okundu, beklenen 3", len(rows))
+	}
+	if err := rows[0].Err; err == nil || !strings.Contains(err.Error(), "saat") {
+		t.Errorf("geçersiz saat hatası = %v", err)
+	}
+	if err := rows[1].Err; err == nil || !strings.Contains(err.Error(), "tarih") {
+		t.Errorf("geçersiz tarih hatası = %v", err)
+	}
+	if rows[2].Err != nil || rows[2].Hours != 2 {
+		t.Errorf("geçerli satır = %+v", rows[2])
+	}
+}
+
+func TestParseImportJSON(t *testing.T) {
+	rows, err := parseImportJSON([]byte(`[
+		{"date": "2025-02-05", "project": "TEKNOSA", "task": "CX-7010", "description": "Test", "hours": 2.5},
+		{"date": "2025-02-06", "project": "#3", "description": "Destek", "hours": 1, "employee": "3"}
+	]`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rows) != 2 || rows[0].Line != 1 || rows[0].Hours != 2.5 || rows[1].Line != 2 || rows[1].Employee != "3" {
+		t.Errorf("satırlar = %+v", rows)
+	}
+}
+
+func TestCLIImport(t *testing.T) {
+	srv := newFakeOdoo(t)
+	before := len(srv.Records(odoo.ModelTimesheet))
+
+	path := filepath.Join(t.TempDir(), "kayitlar.csv")
+	data := "date,project,task,description,hours,employee\n" +
+		"2025-02-05,TEKNOSA,CX-7006,Analiz,2,\n" + // Odoo kullanıcısının çalışanı (Osman)
+		"2025-02-04,CX Portal,,Destek,4,Fatih\n" + // Fatih'in o gün 7 saati var
+		"2025-02-04,CX Portal,,Destek devam,2,Fatih\n" + // Dosyadaki önceki satırla 13 saat olur
+		"2025-02-03,TEKNOSA,CX-7006,Geliştirme,6,\n" + // Odoo'da zaten var
+		"2025-02-05,Olmayan Proje,,Analiz,1,\n"
+	if err := os.WriteFile(path, []byte(data), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	// Deneme çalıştırması hiçbir şey eklemez
+	out := captureCLI(t, "")
+	if err := runImportCommand([]string{"-file", path, "-dry-run", "-skip-invalid"}); err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		"+ satır 2: 2025-02-05 Osman Çağrı GENÇ: TEKNOSA / CX-7006 - 2.00 saat - Analiz",
+		"+ satır 3:",
+		"✗ satır 4:",
+		"günlük üst sınır 12.00 saat (girilebilecek en fazla 1.00 saat)",
+		"= satır 5:",
+		"✗ satır 6:",
+		"proje bulunamadı: Olmayan Proje",
+		"Toplam 5 satır: 2 eklenecek, 1 zaten var, 2 hatalı",
+	} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("çıktıda %q yok:\n%s", want, out)
+		}
+	}
+	if n := len(srv.Records(odoo.ModelTimesheet)); n != before {
+		t.Fatalf("deneme çalıştırmasında kayıt eklendi: %d", n-before)
+	}
+
+	// Hatalı satır varsa -skip-invalid verilmeden hiçbir kayıt eklenmez
+	captureCLI(t, "e\n")
+	if err := runImportCommand([]string{path}); err == nil || !strings.Contains(err.Error(), "2 satır hatalı") {
+		t.Fatalf("hatalı satırlarla içe aktarma hatası = %v", err)
+	}
+	if n := len(srv.Records(odoo.ModelTimesheet)); n != before {
+		t.Fatalf("hatalı dosyadan kayıt eklendi: %d", n-before)
+	}
+
+	out = captureCLI(t, "")
+	if err := runImportCommand([]string{"-file", path, "-skip-invalid", "-yes", "-batch", "1"}); err != nil {
+		t.Fatal(err)
+	}
+	if n := len(srv.Records(odoo.ModelTimesheet)); n != before+2 {
+		t.Fatalf("%d kayıt eklendi, beklenen 2", n-before)
+	}
+	if !strings.Contains(out.String(), "✓ satır 2: #") || !strings.Contains(out.String(), "2 eklendi") {
+		t.Errorf("rapor:\n%s", out)
+	}
+	var creates int
+	for _, call := range srv.Calls() {
+		if call.Model == odoo.ModelTimesheet && call.Method == "create" {
+			creates++
+		}
+	}
+	if creates != 2 {
+		t.Errorf("%d create çağrısı yapıldı, -batch 1 ile beklenen 2", creates)
+	}
+
+	// Aynı dosya tekrar içe aktarılınca eklenen satırlar yinelenen sayılır
+	out = captureCLI(t, "")
+	if err := runImportCommand([]string{"-file", path, "-skip-invalid", "-yes"}); err != nil {
+		t.Fatal(err)
+	}
+	if n := len(srv.Records(odoo.ModelTimesheet)); n != before+2 || !strings.Contains(out.String(), "3 zaten var") {
+		t.Errorf("ikinci içe aktarma %d kayıt ekledi:\n%s", n-before-2, out)
+	}
+}
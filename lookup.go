@@ -0,0 +1,228 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"odoo-efor-tracker/odoo"
+)
+
+// Eşleşme dereceleri; küçük değer daha kesin eşleşmedir
+const (
+	matchID       = iota // "#12" ya da "id:12"
+	matchExact           // Ad birebir aynı (büyük/küçük harf duyarsız)
+	matchCode            // Ad kodla başlıyor: "CX-7006" -> "CX-7006 Giriş ekranı"
+	matchPrefix          // Ad aranan metinle başlıyor
+	matchContains        // Ad aranan metni içeriyor
+)
+
+// Adayların en fazla kaçının okunacağı
+const candidateLimit = 50
+
+// Candidate, proje ya da görev aramasında bulunan kayıt
+type Candidate struct {
+	ID   int64
+	Name string
+	Rank int
+}
+
+// Kesin eşleşme mi; değilse kullanıcıya çözümlenen ad onaylatılır
+func (c Candidate) Exact() bool {
+	return c.Rank <= matchCode
+}
+
+// AmbiguousError, aranan metnin birden fazla kayıtla aynı derecede
+// eşleştiğini bildirir
+type AmbiguousError struct {
+	Kind       string // "proje" ya da "görev"
+	Query      string
+	Candidates []Candidate
+}
+
+func (e *AmbiguousError) Error() string {
+	names := make([]string, 0, len(e.Candidates))
+	for _, c := range e.Candidates {
+		names = append(names, fmt.Sprintf("%s (#%d)", c.Name, c.ID))
+	}
+	return fmt.Sprintf("%q birden fazla %s ile eşleşiyor: %s. Tam adı ya da #ID yazın",
+		e.Query, e.Kind, strings.Join(names, ", "))
+}
+
+// "#12" ya da "id:12" biçimindeki ID aramasını çöz
+func parseIDQuery(query string) (int64, bool) {
+	s, ok := strings.CutPrefix(query, "#")
+	if !ok {
+		s, ok = strings.CutPrefix(strings.ToLower(query), "id:")
+	}
+	if !ok {
+		return 0, false
+	}
+	id, err := strconv.ParseInt(strings.TrimSpace(s), 10, 64)
+	return id, err == nil && id > 0
+}
+
+// Adın aranan metinle eşleşme derecesi
+func matchRank(name, query string) int {
+	n := strings.ToLower(strings.TrimSpace(name))
+	q := strings.ToLower(strings.TrimSpace(query))
+	if n == q {
+		return matchExact
+	}
+	// Rakam içeren sorgu kod sayılır ve ad içinde boşluk, ":" ya da "]" ile
+	// bitmelidir: "CX-7006" sorgusu "CX-7006 Giriş" ve "[CX-7006] Giriş" ile
+	// eşleşir, "CX-70061" ile eşleşmez
+	if code := strings.TrimPrefix(n, "["); strings.ContainsAny(q, "0123456789") &&
+		strings.HasPrefix(code, q) && len(code) > len(q) && strings.ContainsRune(" :]", rune(code[len(q)])) {
+		return matchCode
+	}
+	if strings.HasPrefix(n, q) {
+		return matchPrefix
+	}
+	return matchContains
+}
+
+// Adayları eşleşme derecesine, sonra ada göre sırala
+func rankCandidates(query string, candidates []Candidate) []Candidate {
+	for i := range candidates {
+		candidates[i].Rank = matchRank(candidates[i].Name, query)
+	}
+	sort.SliceStable(candidates, func(i, j int) bool {
+		if candidates[i].Rank != candidates[j].Rank {
+			return candidates[i].Rank < candidates[j].Rank
+		}
+		return candidates[i].Name < candidates[j].Name
+	})
+	return candidates
+}
+
+// Adı ya da ID'si verilen projeleri eşleşme derecesine göre sıralı döndür
+func searchProjects(client odoo.API, query string) ([]Candidate, error) {
+	domain := odoo.NewDomain()
+	id, byID := parseIDQuery(query)
+	if byID {
+		domain = domain.Where("id", "=", id)
+	} else {
+		domain = domain.Where("name", "ilike", query)
+	}
+	var projects []odoo.Project
+	err := client.SearchRead(odoo.ModelProject, domain,
+		&odoo.SearchOptions{Limit: candidateLimit, Order: "name"}, &projects)
+	if err != nil {
+		return nil, err
+	}
+	candidates := make([]Candidate, 0, len(projects))
+	for _, p := range projects {
+		candidates = append(candidates, Candidate{ID: p.ID, Name: p.Name, Rank: matchID})
+	}
+	if byID {
+		return candidates, nil
+	}
+	return rankCandidates(query, candidates), nil
+}
+
+// Projedeki görevleri adı ya da ID'siyle ara
+func searchTasks(client odoo.API, projectID int64, query string) ([]Candidate, error) {
+	domain := odoo.NewDomain().Where("project_id", "=", projectID)
+	id, byID := parseIDQuery(query)
+	if byID {
+		domain = domain.Where("id", "=", id)
+	} else {
+		domain = domain.Where("name", "ilike", query)
+	}
+	var tasks []odoo.Task
+	err := client.SearchRead(odoo.ModelTask, domain,
+		&odoo.SearchOptions{Limit: candidateLimit, Order: "name"}, &tasks)
+	if err != nil {
+		return nil, err
+	}
+	candidates := make([]Candidate, 0, len(tasks))
+	for _, t := range tasks {
+		candidates = append(candidates, Candidate{ID: t.ID, Name: t.Name, Rank: matchID})
+	}
+	if byID {
+		return candidates, nil
+	}
+	return rankCandidates(query, candidates), nil
+}
+
+// Sıralı adaylardan tek kaydı seç. En iyi derecede birden fazla aday varsa
+// AmbiguousError döner.
+func pickCandidate(kind, query string, candidates []Candidate) (Candidate, error) {
+	if len(candidates) == 0 {
+		return Candidate{}, fmt.Errorf("%s bulunamadı: %s", kind, query)
+	}
+	best := candidates[0].Rank
+	n := 1
+	for n < len(candidates) && candidates[n].Rank == best {
+		n++
+	}
+	if n > 1 {
+		return Candidate{}, &AmbiguousError{Kind: kind, Query: query, Candidates: candidates[:n]}
+	}
+	return candidates[0], nil
+}
+
+// Projeyi adı, kodu ya da ID'siyle tek kayda çözümle
+func resolveProject(client odoo.API, query string) (Candidate, error) {
+	candidates, err := searchProjects(client, query)
+	if err != nil {
+		return Candidate{}, err
+	}
+	return pickCandidate("proje", query, candidates)
+}
+
+// Görevi proje içinde adı, kodu ya da ID'siyle tek kayda çözümle
+func resolveTask(client odoo.API, projectID int64, query string) (Candidate, error) {
+	candidates, err := searchTasks(client, projectID, query)
+	if err != nil {
+		return Candidate{}, err
+	}
+	return pickCandidate("görev", query, candidates)
+}
+
+// ResolvedEntry, proje ve görev adları çözümlenmiş zaman kaydı
+type ResolvedEntry struct {
+	NewTimeEntry
+	ProjectName string
+	TaskName    string
+	Exact       bool // Proje ve görev kesin eşleşti mi
+}
+
+// Kullanıcının yazdığı proje ve görev adlarını çözümleyerek kaydı hazırla
+func resolveTimeEntry(client odoo.API, date, project, task, description string, hours float64) (*ResolvedEntry, error) {
+	p, err := resolveProject(client, project)
+	if err != nil {
+		return nil, err
+	}
+	entry := &ResolvedEntry{
+		NewTimeEntry: NewTimeEntry{
+			Date:        date,
+			ProjectID:   p.ID,
+			Description: description,
+			Hours:       hours,
+		},
+		ProjectName: p.Name,
+		Exact:       p.Exact(),
+	}
+	if task != "" {
+		t, err := resolveTask(client, p.ID, task)
+		if err != nil {
+			return nil, err
+		}
+		entry.TaskID, entry.TaskName = t.ID, t.Name
+		entry.Exact = entry.Exact && t.Exact()
+	}
+	return entry, nil
+}
+
+// Kaydın özeti
+func (e *ResolvedEntry) Summary() string {
+	task := e.TaskName
+	if task == "" {
+		task = "-"
+	}
+	return fmt.Sprintf("📅 Tarih: %s\n🏢 Proje: %s\n📝 Görev: %s\n📋 Açıklama: %s\n⏱️ Saat: %.2f",
+		e.Date, e.ProjectName, task, e.Description, e.Hours)
+}
@@ -0,0 +1,56 @@
+package main
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestMatchRank(t *testing.T) {
+	for _, tt := range []struct {
+		name, query string
+		want        int
+	}{
+		{"TEKNOSA", "teknosa", matchExact},
+		{"CX-7006 Giriş ekranı", "cx-7006", matchCode},
+		{"[CX-7006] Giriş ekranı", "CX-7006", matchCode},
+		{"CX-70061 Rapor", "CX-7006", matchPrefix},
+		{"CX Portal", "CX", matchPrefix},
+		{"Enoca İç Projeler", "iç", matchContains},
+	} {
+		if got := matchRank(tt.name, tt.query); got != tt.want {
+			t.Errorf("matchRank(%q, %q) = %d, beklenen %d", tt.name, tt.query, got, tt.want)
+		}
+	}
+}
+
+func TestResolveProjectAndTask(t *testing.T) {
+	newFakeOdoo(t)
+	client, err := authenticateOdoo()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if p, err := resolveProject(client, "#3"); err != nil || p.Name != "CX Portal" || !p.Exact() {
+		t.Errorf("ID ile arama = %+v, %v", p, err)
+	}
+	if p, err := resolveProject(client, "CX"); err != nil || p.Name != "CX Portal" || p.Exact() {
+		t.Errorf("kısmi arama = %+v, %v", p, err)
+	}
+	if _, err := resolveProject(client, "#99"); err == nil || !strings.Contains(err.Error(), "proje bulunamadı") {
+		t.Errorf("olmayan ID: %v", err)
+	}
+
+	// "CX-70" TEKNOSA'daki iki görevle aynı derecede eşleşir
+	var ambiguous *AmbiguousError
+	if _, err := resolveTask(client, 1, "CX-70"); !errors.As(err, &ambiguous) || len(ambiguous.Candidates) != 2 {
+		t.Errorf("belirsiz görev hatası bekleniyordu: %v", err)
+	}
+	if task, err := resolveTask(client, 1, "cx-7010"); err != nil || task.ID != 2 || !task.Exact() {
+		t.Errorf("görev = %+v, %v", task, err)
+	}
+	// Görev ID'si başka projeye aitse bulunmaz
+	if _, err := resolveTask(client, 2, "#1"); err == nil {
+		t.Error("başka projenin görevi eşleşti")
+	}
+}
@@ -0,0 +1,366 @@
+package main
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"log"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net/textproto"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"odoo-efor-tracker/mailer"
+	"odoo-efor-tracker/odoo"
+)
+
+// E-posta eki
+type Attachment struct {
+	Name string
+	Data []byte
+}
+
+// E-posta gönderim şekilleri
+const (
+	MailModeTeam     = "team"     // Ekip raporu ekibin alıcı listesine gönderilir
+	MailModeEmployee = "employee" // Her çalışana yalnızca kendi özeti gönderilir
+)
+
+// E-posta alıcıları
+type Recipients struct {
+	To  []string `yaml:"to"`
+	Cc  []string `yaml:"cc"`
+	Bcc []string `yaml:"bcc"`
+}
+
+// Hiç alıcı tanımlı değil mi
+func (r Recipients) Empty() bool {
+	return len(r.To) == 0 && len(r.Cc) == 0 && len(r.Bcc) == 0
+}
+
+// SMTP zarfında kullanılan tüm adresler (To, Cc ve Bcc)
+func (r Recipients) All() []string {
+	return append(append(append([]string(nil), r.To...), r.Cc...), r.Bcc...)
+}
+
+// Gönderilecek e-posta. HTML boş değilse mesaj düz metin alternatifiyle
+// birlikte multipart/alternative olarak hazırlanır. Bcc adresleri mesaj
+// başlıklarına yazılmaz.
+type Email struct {
+	Recipients
+	Subject     string
+	Text        string
+	HTML        string
+	Attachments []Attachment
+}
+
+// E-postayı SMTP ayarlarıyla (SMTP_* çevre değişkenleri) gönder
+func sendEmail(email Email) error {
+	if email.Empty() {
+		return fmt.Errorf("e-posta alıcısı tanımlı değil (MAIL_TO veya teams.yaml recipients)")
+	}
+
+	cfg, err := mailer.ConfigFromEnv()
+	if err != nil {
+		return err
+	}
+	m, err := mailer.New(cfg)
+	if err != nil {
+		return err
+	}
+
+	message, err := buildMessage(m.From(), email)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("E-posta gönderiliyor...\n")
+	fmt.Printf("SMTP Sunucu: %s:%s\n", cfg.Host, cfg.Port)
+	fmt.Printf("Gönderen: %s\n", cfg.From)
+	fmt.Printf("Alıcı: %s\n", strings.Join(email.All(), ", "))
+
+	if err := m.Send(email.All(), message); err != nil {
+		return fmt.Errorf("E-posta gönderme hatası: %v", err)
+	}
+	return nil
+}
+
+// Testlerde gerçek SMTP sunucusu yerine kullanılabilmesi için değişken
+var deliverEmail = sendEmail
+
+// MAIL_TO, MAIL_CC ve MAIL_BCC çevre değişkenlerinden (virgülle ayrılmış)
+// genel alıcıları oku
+func recipientsFromEnv() Recipients {
+	return Recipients{
+		To:  splitAddresses(os.Getenv("MAIL_TO")),
+		Cc:  splitAddresses(os.Getenv("MAIL_CC")),
+		Bcc: splitAddresses(os.Getenv("MAIL_BCC")),
+	}
+}
+
+func splitAddresses(list string) []string {
+	var addrs []string
+	for _, addr := range strings.Split(list, ",") {
+		if addr = strings.TrimSpace(addr); addr != "" {
+			addrs = append(addrs, addr)
+		}
+	}
+	return addrs
+}
+
+// Ekip raporunun alıcılarını belirle. Öncelik sırası: ekibin kendi
+// listesi, teams.yaml'daki genel liste, MAIL_TO/MAIL_CC/MAIL_BCC.
+func reportRecipients(teamName string) (Recipients, error) {
+	cfg, err := loadRosterConfig()
+	if err != nil {
+		return Recipients{}, err
+	}
+	team, err := cfg.Team(teamName)
+	if err != nil {
+		return Recipients{}, err
+	}
+	if team != nil && !team.Recipients.Empty() {
+		return team.Recipients, nil
+	}
+	if !cfg.Recipients.Empty() {
+		return cfg.Recipients, nil
+	}
+	return recipientsFromEnv(), nil
+}
+
+// Raporu gönderim şekline göre e-postayla gönder
+func mailReport(client odoo.API, r *Report, mode string) error {
+	switch mode {
+	case MailModeTeam:
+		recipients, err := reportRecipients(r.Team)
+		if err != nil {
+			return err
+		}
+		email, err := reportEmail(r)
+		if err != nil {
+			return err
+		}
+		email.Recipients = recipients
+		return deliverEmail(email)
+	case MailModeEmployee:
+		return mailEmployeeReports(client, r)
+	}
+	return fmt.Errorf("bilinmeyen e-posta gönderim şekli: %s (team veya employee)", mode)
+}
+
+// Her çalışana yalnızca kendi kayıtlarını ve eksik gün uyarılarını içeren
+// özet gönder. İş e-postası olmayan çalışanlar atlanır.
+func mailEmployeeReports(client odoo.API, r *Report) error {
+	members, err := scopeEmployees(client, r)
+	if err != nil {
+		return err
+	}
+
+	var failed []string
+	for _, emp := range members {
+		if emp.WorkEmail == "" {
+			log.Printf("Uyarı: %s için iş e-postası tanımlı değil, özet gönderilmedi", emp.Name)
+			continue
+		}
+		email, err := reportEmail(employeeReport(r, emp))
+		if err == nil {
+			email.To = []string{emp.WorkEmail}
+			email.Subject = fmt.Sprintf("Zaman Çizelgesi Özetiniz - %s", r.Period)
+			err = deliverEmail(email)
+		}
+		if err != nil {
+			log.Printf("%s için e-posta gönderilemedi: %v", emp.Name, err)
+			failed = append(failed, emp.Name)
+		}
+	}
+	if len(failed) > 0 {
+		return fmt.Errorf("%d çalışana e-posta gönderilemedi: %s", len(failed), strings.Join(failed, ", "))
+	}
+	return nil
+}
+
+// Ekip raporundan tek çalışanın raporunu çıkar ve eksik günleri uyarı olarak ekle
+func employeeReport(r *Report, emp odoo.Employee) *Report {
+	var entries []odoo.TimesheetLine
+	for _, entry := range r.Entries {
+		if entry.Employee.ID == emp.ID {
+			entries = append(entries, entry)
+		}
+	}
+	personal := buildReport(r.Period, entries, []odoo.Employee{emp})
+	personal.Employee = emp.Name
+	personal.Members = []odoo.Employee{emp}
+	if r.calendar != nil {
+		personal.calendar = r.calendar
+		personal.applyCalendar(r.calendar)
+	}
+	for _, day := range r.Missing {
+		if day.EmployeeID != emp.ID {
+			continue
+		}
+		personal.Missing = append(personal.Missing, day)
+		if day.Empty() {
+			personal.Warnings = append(personal.Warnings, fmt.Sprintf("%s: kayıt girilmemiş", day.Date))
+		} else {
+			personal.Warnings = append(personal.Warnings, fmt.Sprintf("%s: %.2f saat girilmiş, %.2f saatin altında", day.Date, day.Hours, day.Expected))
+		}
+	}
+	return personal
+}
+
+// Raporu HTML gövde, düz metin alternatifi ve CSV/XLSX ekleriyle e-postaya çevir
+func reportEmail(r *Report) (Email, error) {
+	var text, html, csvData, xlsxData bytes.Buffer
+	renderText(&text, r)
+	if err := renderCSV(&csvData, r); err != nil {
+		return Email{}, fmt.Errorf("CSV rapor oluşturulamadı: %v", err)
+	}
+	if err := renderXLSX(&xlsxData, r); err != nil {
+		return Email{}, fmt.Errorf("XLSX rapor oluşturulamadı: %v", err)
+	}
+
+	name := fmt.Sprintf("rapor_%s_%s", r.Period.Start.Format(dateLayout), r.Period.End.Format(dateLayout))
+	attachments := []Attachment{
+		{Name: name + ".csv", Data: csvData.Bytes()},
+		{Name: name + ".xlsx", Data: xlsxData.Bytes()},
+	}
+	if err := renderHTML(&html, r, attachments); err != nil {
+		return Email{}, fmt.Errorf("HTML rapor oluşturulamadı: %v", err)
+	}
+	return Email{
+		Subject:     fmt.Sprintf("Günlük Odoo Kayıtları - %s", time.Now().Format(dateLayout)),
+		Text:        text.String(),
+		HTML:        html.String(),
+		Attachments: attachments,
+	}, nil
+}
+
+// MIME mesajını oluştur. Yapı:
+//
+//	multipart/mixed (ek varsa)
+//	├── multipart/alternative (HTML varsa)
+//	│   ├── text/plain
+//	│   └── text/html
+//	└── ekler (base64)
+func buildMessage(from string, email Email) ([]byte, error) {
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "From: %s\r\n", from)
+	if len(email.To) > 0 {
+		fmt.Fprintf(&b, "To: %s\r\n", strings.Join(email.To, ", "))
+	}
+	if len(email.Cc) > 0 {
+		fmt.Fprintf(&b, "Cc: %s\r\n", strings.Join(email.Cc, ", "))
+	}
+	fmt.Fprintf(&b, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", email.Subject))
+	fmt.Fprintf(&b, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	b.WriteString("MIME-Version: 1.0\r\n")
+
+	header, body, err := messageBody(email)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(email.Attachments) == 0 {
+		for _, key := range []string{"Content-Type", "Content-Transfer-Encoding"} {
+			if v := header.Get(key); v != "" {
+				fmt.Fprintf(&b, "%s: %s\r\n", key, v)
+			}
+		}
+		b.WriteString("\r\n")
+		b.Write(body)
+		return b.Bytes(), nil
+	}
+
+	mw := multipart.NewWriter(&b)
+	fmt.Fprintf(&b, "Content-Type: multipart/mixed; boundary=%s\r\n\r\n", mw.Boundary())
+
+	part, err := mw.CreatePart(header)
+	if err != nil {
+		return nil, err
+	}
+	part.Write(body)
+
+	for _, a := range email.Attachments {
+		contentType := exportContentTypes[filepath.Ext(a.Name)]
+		if contentType == "" {
+			contentType = "application/octet-stream"
+		}
+		part, err := mw.CreatePart(textproto.MIMEHeader{
+			"Content-Type":              {contentType},
+			"Content-Transfer-Encoding": {"base64"},
+			"Content-Disposition":       {mime.FormatMediaType("attachment", map[string]string{"filename": a.Name})},
+		})
+		if err != nil {
+			return nil, err
+		}
+		writeBase64(part, a.Data)
+	}
+
+	if err := mw.Close(); err != nil {
+		return nil, err
+	}
+	return b.Bytes(), nil
+}
+
+// Mesaj gövdesini ve başlıklarını oluştur: yalnızca düz metin ya da
+// metin+HTML içeren multipart/alternative
+func messageBody(email Email) (textproto.MIMEHeader, []byte, error) {
+	var body bytes.Buffer
+	if email.HTML == "" {
+		if err := writeQuotedPrintable(&body, email.Text); err != nil {
+			return nil, nil, err
+		}
+		return textPartHeader("text/plain"), body.Bytes(), nil
+	}
+
+	mw := multipart.NewWriter(&body)
+	for _, alt := range []struct{ contentType, text string }{
+		{"text/plain", email.Text},
+		{"text/html", email.HTML},
+	} {
+		part, err := mw.CreatePart(textPartHeader(alt.contentType))
+		if err != nil {
+			return nil, nil, err
+		}
+		if err := writeQuotedPrintable(part, alt.text); err != nil {
+			return nil, nil, err
+		}
+	}
+	if err := mw.Close(); err != nil {
+		return nil, nil, err
+	}
+	header := textproto.MIMEHeader{
+		"Content-Type": {"multipart/alternative; boundary=" + mw.Boundary()},
+	}
+	return header, body.Bytes(), nil
+}
+
+func textPartHeader(contentType string) textproto.MIMEHeader {
+	return textproto.MIMEHeader{
+		"Content-Type":              {contentType + "; charset=UTF-8"},
+		"Content-Transfer-Encoding": {"quoted-printable"},
+	}
+}
+
+func writeQuotedPrintable(w io.Writer, body string) error {
+	qp := quotedprintable.NewWriter(w)
+	if _, err := qp.Write([]byte(body)); err != nil {
+		return err
+	}
+	return qp.Close()
+}
+
+// Base64 veriyi RFC 2045'e uygun olarak 76 karakterlik satırlara bölerek yaz
+func writeBase64(w io.Writer, data []byte) {
+	encoded := base64.StdEncoding.EncodeToString(data)
+	for len(encoded) > 76 {
+		fmt.Fprintf(w, "%s\r\n", encoded[:76])
+		encoded = encoded[76:]
+	}
+	fmt.Fprintf(w, "%s\r\n", encoded)
+}
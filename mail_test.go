@@ -0,0 +1,346 @@
+package main
+
+import (
+	"bytes"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/mail"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"odoo-efor-tracker/mailer"
+	"odoo-efor-tracker/mailer/smtptest"
+)
+
+// Mesajın yaprak parçalarını (içerik türü → çözülmüş gövde) topla
+func messageParts(t *testing.T, raw []byte) map[string]string {
+	t.Helper()
+	msg, err := mail.ReadMessage(bytes.NewReader(raw))
+	if err != nil {
+		t.Fatalf("mesaj okunamadı: %v", err)
+	}
+	parts := make(map[string]string)
+	var walk func(contentType string, body io.Reader)
+	walk = func(contentType string, body io.Reader) {
+		mediaType, params, err := mime.ParseMediaType(contentType)
+		if err != nil {
+			t.Fatalf("geçersiz içerik türü %q: %v", contentType, err)
+		}
+		if !strings.HasPrefix(mediaType, "multipart/") {
+			data, _ := io.ReadAll(body)
+			parts[mediaType] = string(data)
+			return
+		}
+		mr := multipart.NewReader(body, params["boundary"])
+		for {
+			p, err := mr.NextPart()
+			if err == io.EOF {
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if name := p.FileName(); name != "" {
+				data, _ := io.ReadAll(p)
+				parts[name] = string(data)
+				continue
+			}
+			walk(p.Header.Get("Content-Type"), p)
+		}
+	}
+	walk(msg.Header.Get("Content-Type"), msg.Body)
+	return parts
+}
+
+func TestBuildMessage(t *testing.T) {
+	raw, err := buildMessage("rapor@example.com", Email{
+		Recipients: Recipients{
+			To:  []string{"a@example.com", "b@example.com"},
+			Cc:  []string{"pm@example.com"},
+			Bcc: []string{"arsiv@example.com"},
+		},
+		Subject: "Günlük Rapor",
+		Text:    "özet",
+		HTML:    "<p>özet</p>",
+		Attachments: []Attachment{
+			{Name: "rapor.csv", Data: []byte("id,date\n1,2025-02-03\n")},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, want := range []string{
+		"To: a@example.com, b@example.com\r\n",
+		"Cc: pm@example.com\r\n",
+		"Subject: =?utf-8?q?G=C3=BCnl=C3=BCk_Rapor?=\r\n",
+		"Content-Type: multipart/mixed; boundary=",
+		"Content-Type: multipart/alternative; boundary=",
+		"Content-Disposition: attachment; filename=rapor.csv",
+		"Content-Type: text/csv; charset=UTF-8",
+		"aWQsZGF0ZQoxLDIwMjUtMDItMDMK",
+	} {
+		if !bytes.Contains(raw, []byte(want)) {
+			t.Errorf("mesajda %q yok:\n%s", want, raw)
+		}
+	}
+
+	if bytes.Contains(raw, []byte("arsiv@example.com")) {
+		t.Error("Bcc adresi mesaj başlıklarında görünmemeli")
+	}
+
+	parts := messageParts(t, raw)
+	if parts["text/plain"] != "özet" || parts["text/html"] != "<p>özet</p>" {
+		t.Errorf("gövde parçaları = %q", parts)
+	}
+}
+
+func TestBuildMessagePlainText(t *testing.T) {
+	raw, err := buildMessage("rapor@example.com", Email{Recipients: Recipients{To: []string{"a@example.com"}}, Subject: "Rapor", Text: "Toplam: 8.00"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	msg, err := mail.ReadMessage(bytes.NewReader(raw))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ct := msg.Header.Get("Content-Type"); ct != "text/plain; charset=UTF-8" {
+		t.Errorf("Content-Type = %q", ct)
+	}
+}
+
+func TestReportEmail(t *testing.T) {
+	r := sampleReport()
+	email, err := reportEmail(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	raw, err := buildMessage("rapor@example.com", email)
+	if err != nil {
+		t.Fatal(err)
+	}
+	parts := messageParts(t, raw)
+
+	if !strings.Contains(parts["text/plain"], "Toplam Çalışma Saati: 14.50") {
+		t.Errorf("düz metin alternatifi eksik:\n%s", parts["text/plain"])
+	}
+	html := parts["text/html"]
+	for _, want := range []string{
+		"<td>Zeynep</td><td align=\"right\">0.00</td>",
+		"<td>CX</td><td align=\"right\">14.50</td>",
+		// 6.5 saat çalışılan gün kırmızı ile vurgulanır, 8 saatlik gün vurgulanmaz
+		`<tr style="background: #fde2e2; color: #b00020;"><td>2025-02-04</td><td>Ali</td><td align="right">6.50</td>`,
+		`<tr><td>2025-02-03</td><td>Ali</td><td align="right">8.00</td>`,
+		"ekteki rapor_2025-02-03_2025-02-05.csv, rapor_2025-02-03_2025-02-05.xlsx dosyalarındadır",
+	} {
+		if !strings.Contains(html, want) {
+			t.Errorf("HTML gövdede %q yok:\n%s", want, html)
+		}
+	}
+	for _, name := range []string{"rapor_2025-02-03_2025-02-05.csv", "rapor_2025-02-03_2025-02-05.xlsx"} {
+		if parts[name] == "" {
+			t.Errorf("%s eki yok", name)
+		}
+	}
+}
+
+func TestRenderHTMLWithoutAttachments(t *testing.T) {
+	var html bytes.Buffer
+	if err := renderHTML(&html, sampleReport(), nil); err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(html.String(), "ekteki") {
+		t.Errorf("eki olmayan e-postada ek notu olmamalı:\n%s", html.String())
+	}
+}
+
+// Gönderilen e-postaları SMTP yerine topla
+func captureEmails(t *testing.T) *[]Email {
+	t.Helper()
+	var sent []Email
+	deliverEmail = func(email Email) error {
+		sent = append(sent, email)
+		return nil
+	}
+	t.Cleanup(func() { deliverEmail = sendEmail })
+	return &sent
+}
+
+func TestReportRecipients(t *testing.T) {
+	newFakeOdoo(t)
+	t.Setenv("MAIL_TO", "env@example.com, ikinci@example.com")
+
+	qa, err := reportRecipients("qa")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := Recipients{To: []string{"qa@example.com"}, Cc: []string{"pm@example.com"}, Bcc: []string{"arsiv@example.com"}}
+	if !reflect.DeepEqual(qa, want) {
+		t.Errorf("qa alıcıları = %+v, beklenen %+v", qa, want)
+	}
+
+	// Kendi listesi olmayan ekip genel listeyi kullanır
+	backend, err := reportRecipients("backend")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(backend.To, []string{"ekip@example.com"}) {
+		t.Errorf("backend alıcıları = %+v", backend)
+	}
+
+	// teams.yaml'da liste yoksa çevre değişkenleri kullanılır
+	t.Setenv("TEAMS_FILE", filepath.Join(t.TempDir(), "yok.yaml"))
+	env, err := reportRecipients("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(env.To, []string{"env@example.com", "ikinci@example.com"}) || env.Cc != nil {
+		t.Errorf("çevre değişkeni alıcıları = %+v", env)
+	}
+}
+
+func TestGenerateReportMailsTeam(t *testing.T) {
+	newFakeOdoo(t)
+	chdirTemp(t)
+	sent := captureEmails(t)
+
+	period, _ := parsePeriod("2025-02-03", time.Now())
+	if _, _, err := generateReport(period, "", "qa", FormatText, MailModeTeam); err != nil {
+		t.Fatal(err)
+	}
+	if len(*sent) != 1 {
+		t.Fatalf("%d e-posta gönderildi, beklenen 1", len(*sent))
+	}
+	email := (*sent)[0]
+	if !reflect.DeepEqual(email.To, []string{"qa@example.com"}) || !reflect.DeepEqual(email.Bcc, []string{"arsiv@example.com"}) {
+		t.Errorf("alıcılar = %+v", email.Recipients)
+	}
+	if !strings.Contains(email.Text, "Harici Danışman: 4.00 saat") {
+		t.Errorf("e-posta içeriği:\n%s", email.Text)
+	}
+}
+
+func TestGenerateReportMailsEmployees(t *testing.T) {
+	newFakeOdoo(t)
+	chdirTemp(t)
+	sent := captureEmails(t)
+
+	period, err := parsePeriod("2025-02-03..2025-02-04", time.Now())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := generateReport(period, "", "", FormatText, MailModeEmployee); err != nil {
+		t.Fatal(err)
+	}
+
+	byAddress := make(map[string]Email)
+	for _, email := range *sent {
+		if len(email.To) != 1 || len(email.Cc) != 0 || len(email.Bcc) != 0 {
+			t.Errorf("kişisel e-posta yalnızca çalışana gitmeli: %+v", email.Recipients)
+		}
+		byAddress[email.To[0]] = email
+	}
+	if len(byAddress) != 3 {
+		t.Fatalf("e-posta gönderilen adresler: %v", byAddress)
+	}
+
+	osman := byAddress["osman@example.com"].Text
+	if !strings.Contains(osman, "Toplam Çalışma Saati: 16.50") || strings.Contains(osman, "Ayşegül") || strings.Contains(osman, "Uyarılar") {
+		t.Errorf("Osman'ın özeti yalnızca kendi kayıtlarını içermeli:\n%s", osman)
+	}
+
+	fatih := byAddress["fatih@example.com"].Text
+	for _, want := range []string{
+		"2025-02-03: kayıt girilmemiş",
+		"2025-02-04: 7.00 saat girilmiş, 8.00 saatin altında",
+	} {
+		if !strings.Contains(fatih, want) {
+			t.Errorf("Fatih'in özetinde %q yok:\n%s", want, fatih)
+		}
+	}
+	if !strings.Contains(byAddress["fatih@example.com"].HTML, "2025-02-03: kayıt girilmemiş") {
+		t.Error("HTML gövdede eksik gün uyarısı yok")
+	}
+}
+
+func TestGenerateReportMailsEmployeesPartTime(t *testing.T) {
+	newFakeOdoo(t)
+	chdirTemp(t)
+	sent := captureEmails(t)
+
+	path := filepath.Join(t.TempDir(), "work_hours.yaml")
+	err := os.WriteFile(path, []byte("employees:\n  - employee: Ayşegül Şahin\n    dates:\n      \"2025-02-03\": 5.75\n"), 0o644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("WORK_HOURS_FILE", path)
+
+	period, err := parsePeriod("2025-02-03..2025-02-04", time.Now())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := generateReport(period, "", "qa", FormatText, MailModeEmployee); err != nil {
+		t.Fatal(err)
+	}
+
+	byAddress := make(map[string]Email)
+	for _, email := range *sent {
+		byAddress[email.To[0]] = email
+	}
+
+	// Yarı zamanlı çalışan için 4 saat tam gündür
+	danisman := byAddress["danisman@example.com"]
+	if !strings.Contains(danisman.Text, "Harici Danışman: 4.00 saat") || strings.Contains(danisman.Text, StatusShort) {
+		t.Errorf("yarı zamanlı çalışanın özeti:\n%s", danisman.Text)
+	}
+	if strings.Contains(danisman.HTML, StatusShort) {
+		t.Errorf("yarı zamanlı çalışanın HTML özeti:\n%s", danisman.HTML)
+	}
+
+	if aysegul := byAddress["aysegul@example.com"].Text; !strings.Contains(aysegul, "2025-02-03: 5.50 saat girilmiş, 5.75 saatin altında") {
+		t.Errorf("Ayşegül'ün özetinde beklenen saat yanlış:\n%s", aysegul)
+	}
+}
+
+func TestSendEmailOverSMTP(t *testing.T) {
+	srv, err := smtptest.NewServer(smtptest.Options{Security: mailer.SecurityStartTLS})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer srv.Close()
+
+	cfg := srv.Config()
+	t.Setenv("SMTP_HOST", cfg.Host)
+	t.Setenv("SMTP_PORT", cfg.Port)
+	t.Setenv("SMTP_USERNAME", cfg.Username)
+	t.Setenv("SMTP_PASSWORD", cfg.Password)
+	t.Setenv("SMTP_FROM", cfg.From)
+	t.Setenv("SMTP_CA_FILE", cfg.CAFile)
+	t.Setenv("SMTP_TIMEOUT", "5s")
+
+	email, err := reportEmail(sampleReport())
+	if err != nil {
+		t.Fatal(err)
+	}
+	email.Recipients = Recipients{To: []string{"ekip@example.com"}, Bcc: []string{"arsiv@example.com"}}
+	if err := sendEmail(email); err != nil {
+		t.Fatalf("sendEmail: %v", err)
+	}
+
+	msgs := srv.Messages()
+	if len(msgs) != 1 {
+		t.Fatalf("%d mesaj teslim edildi, beklenen 1", len(msgs))
+	}
+	if got := strings.Join(msgs[0].To, ","); got != "ekip@example.com,arsiv@example.com" || !msgs[0].TLS {
+		t.Errorf("zarf alıcıları = %s, TLS = %v", got, msgs[0].TLS)
+	}
+	parts := messageParts(t, msgs[0].Data)
+	if !strings.Contains(parts["text/plain"], "Toplam Çalışma Saati: 14.50") {
+		t.Errorf("teslim edilen mesaj:\n%s", msgs[0].Data)
+	}
+}
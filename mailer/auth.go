@@ -0,0 +1,84 @@
+package mailer
+
+import (
+	"errors"
+	"fmt"
+	"net/smtp"
+	"strings"
+)
+
+// Parolanın şifrelenmemiş bağlantıda gönderilmesini engelle. Yerel test
+// sunucularına SMTP_SECURITY=none ile bağlanırken izin verilir.
+func checkTLS(server *smtp.ServerInfo) error {
+	if server.TLS || server.Name == "localhost" || server.Name == "127.0.0.1" || server.Name == "::1" {
+		return nil
+	}
+	return errors.New("şifrelenmemiş bağlantıda kimlik doğrulama yapılmaz")
+}
+
+// AUTH PLAIN (RFC 4616)
+type plainAuth struct {
+	username, password string
+}
+
+func (a *plainAuth) Start(server *smtp.ServerInfo) (string, []byte, error) {
+	if err := checkTLS(server); err != nil {
+		return "", nil, err
+	}
+	return "PLAIN", []byte("\x00" + a.username + "\x00" + a.password), nil
+}
+
+func (a *plainAuth) Next(fromServer []byte, more bool) ([]byte, error) {
+	if more {
+		return nil, errors.New("beklenmeyen sunucu yanıtı")
+	}
+	return nil, nil
+}
+
+// AUTH LOGIN; Microsoft 365'in temel kimlik doğrulaması bunu kullanır
+type loginAuth struct {
+	username, password string
+}
+
+func (a *loginAuth) Start(server *smtp.ServerInfo) (string, []byte, error) {
+	if err := checkTLS(server); err != nil {
+		return "", nil, err
+	}
+	return "LOGIN", nil, nil
+}
+
+func (a *loginAuth) Next(fromServer []byte, more bool) ([]byte, error) {
+	if !more {
+		return nil, nil
+	}
+	prompt := strings.ToLower(strings.TrimSpace(string(fromServer)))
+	switch {
+	case strings.HasPrefix(prompt, "username"):
+		return []byte(a.username), nil
+	case strings.HasPrefix(prompt, "password"):
+		return []byte(a.password), nil
+	}
+	return nil, fmt.Errorf("beklenmeyen LOGIN isteği: %s", fromServer)
+}
+
+// AUTH XOAUTH2; Gmail ve Microsoft 365'te OAuth2 erişim token'ıyla
+// kimlik doğrulama
+type xoauth2Auth struct {
+	username, token string
+}
+
+func (a *xoauth2Auth) Start(server *smtp.ServerInfo) (string, []byte, error) {
+	if err := checkTLS(server); err != nil {
+		return "", nil, err
+	}
+	return "XOAUTH2", []byte("user=" + a.username + "\x01auth=Bearer " + a.token + "\x01\x01"), nil
+}
+
+// Token reddedilirse sunucu hata ayrıntısını JSON olarak gönderir ve boş
+// yanıt bekler; ardından asıl hata kodu döner
+func (a *xoauth2Auth) Next(fromServer []byte, more bool) ([]byte, error) {
+	if more {
+		return []byte{}, nil
+	}
+	return nil, nil
+}
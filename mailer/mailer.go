@@ -0,0 +1,245 @@
+// Package mailer, SMTP üzerinden e-posta gönderir. Düz bağlantı, STARTTLS
+// ve doğrudan TLS (465) ile PLAIN, LOGIN ve XOAUTH2 kimlik doğrulamasını
+// destekler; tüm oturum yapılandırılabilir bir zaman aşımıyla sınırlanır.
+package mailer
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net"
+	"net/smtp"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Bağlantı güvenliği seçenekleri
+const (
+	SecurityStartTLS = "starttls" // Düz bağlantı açılır, STARTTLS ile şifrelenir (587)
+	SecurityTLS      = "tls"      // Bağlantı baştan TLS ile açılır (465)
+	SecurityNone     = "none"     // Şifreleme yok; yalnızca yerel/test sunucuları için
+)
+
+// Kimlik doğrulama yöntemleri
+const (
+	AuthPlain   = "plain"
+	AuthLogin   = "login"
+	AuthXOAuth2 = "xoauth2"
+	AuthNone    = "none"
+)
+
+// Varsayılan bağlantı ve oturum zaman aşımı
+const DefaultTimeout = 30 * time.Second
+
+// Config, SMTP sunucu ayarları
+type Config struct {
+	Host     string
+	Port     string
+	Username string
+	Password string
+	From     string
+
+	Security string // starttls, tls veya none; boşsa 465 için tls, diğerleri için starttls
+	Auth     string // plain, login, xoauth2 veya none; boşsa token varsa xoauth2, yoksa plain
+
+	// XOAUTH2 için erişim token'ı (Microsoft 365, Gmail)
+	OAuthToken string
+
+	CAFile             string // Sunucu sertifikasını doğrulamak için ek CA (PEM)
+	InsecureSkipVerify bool   // Sertifika doğrulamasını kapat; yalnızca test için
+
+	Timeout time.Duration // Bağlantı ve tüm oturum için üst sınır
+}
+
+// SMTP ayarlarını çevre değişkenlerinden oku
+func ConfigFromEnv() (Config, error) {
+	cfg := Config{
+		Host:       os.Getenv("SMTP_HOST"),
+		Port:       os.Getenv("SMTP_PORT"),
+		Username:   os.Getenv("SMTP_USERNAME"),
+		Password:   os.Getenv("SMTP_PASSWORD"),
+		From:       os.Getenv("SMTP_FROM"),
+		Security:   os.Getenv("SMTP_SECURITY"),
+		Auth:       os.Getenv("SMTP_AUTH"),
+		OAuthToken: os.Getenv("SMTP_OAUTH_TOKEN"),
+		CAFile:     os.Getenv("SMTP_CA_FILE"),
+	}
+
+	if v := os.Getenv("SMTP_INSECURE_SKIP_VERIFY"); v != "" {
+		insecure, err := strconv.ParseBool(v)
+		if err != nil {
+			return Config{}, fmt.Errorf("geçersiz SMTP_INSECURE_SKIP_VERIFY: %v", err)
+		}
+		cfg.InsecureSkipVerify = insecure
+	}
+	if v := os.Getenv("SMTP_TIMEOUT"); v != "" {
+		timeout, err := time.ParseDuration(v)
+		if err != nil {
+			return Config{}, fmt.Errorf("geçersiz SMTP_TIMEOUT (örn. 30s): %v", err)
+		}
+		cfg.Timeout = timeout
+	}
+	return cfg, nil
+}
+
+// Mailer, doğrulanmış ayarlarla e-posta gönderir
+type Mailer struct {
+	cfg       Config
+	tlsConfig *tls.Config
+}
+
+// Ayarları doğrula ve varsayılanları uygula
+func New(cfg Config) (*Mailer, error) {
+	if cfg.Host == "" || cfg.Port == "" || cfg.From == "" {
+		return nil, fmt.Errorf("SMTP ayarları eksik. Host: %s, Port: %s, From: %s", cfg.Host, cfg.Port, cfg.From)
+	}
+
+	cfg.Security = strings.ToLower(cfg.Security)
+	if cfg.Security == "" {
+		cfg.Security = SecurityStartTLS
+		if cfg.Port == "465" {
+			cfg.Security = SecurityTLS
+		}
+	}
+	switch cfg.Security {
+	case SecurityStartTLS, SecurityTLS, SecurityNone:
+	default:
+		return nil, fmt.Errorf("geçersiz SMTP güvenlik seçeneği: %s (starttls, tls veya none)", cfg.Security)
+	}
+
+	cfg.Auth = strings.ToLower(cfg.Auth)
+	if cfg.Auth == "" {
+		switch {
+		case cfg.OAuthToken != "":
+			cfg.Auth = AuthXOAuth2
+		case cfg.Username != "":
+			cfg.Auth = AuthPlain
+		default:
+			cfg.Auth = AuthNone
+		}
+	}
+	switch cfg.Auth {
+	case AuthPlain, AuthLogin:
+		if cfg.Username == "" || cfg.Password == "" {
+			return nil, fmt.Errorf("%s kimlik doğrulaması için SMTP_USERNAME ve SMTP_PASSWORD gerekli", cfg.Auth)
+		}
+	case AuthXOAuth2:
+		if cfg.Username == "" || cfg.OAuthToken == "" {
+			return nil, fmt.Errorf("xoauth2 kimlik doğrulaması için SMTP_USERNAME ve SMTP_OAUTH_TOKEN gerekli")
+		}
+	case AuthNone:
+	default:
+		return nil, fmt.Errorf("geçersiz SMTP kimlik doğrulama yöntemi: %s (plain, login, xoauth2 veya none)", cfg.Auth)
+	}
+
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = DefaultTimeout
+	}
+
+	tlsConfig := &tls.Config{
+		ServerName:         cfg.Host,
+		InsecureSkipVerify: cfg.InsecureSkipVerify,
+	}
+	if cfg.CAFile != "" {
+		pem, err := os.ReadFile(cfg.CAFile)
+		if err != nil {
+			return nil, fmt.Errorf("CA dosyası okunamadı: %v", err)
+		}
+		pool, err := x509.SystemCertPool()
+		if err != nil {
+			pool = x509.NewCertPool()
+		}
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("CA dosyasında sertifika bulunamadı: %s", cfg.CAFile)
+		}
+		tlsConfig.RootCAs = pool
+	}
+
+	return &Mailer{cfg: cfg, tlsConfig: tlsConfig}, nil
+}
+
+// Gönderen adresi
+func (m *Mailer) From() string {
+	return m.cfg.From
+}
+
+// Hazır MIME mesajını verilen alıcılara gönder
+func (m *Mailer) Send(recipients []string, message []byte) error {
+	if len(recipients) == 0 {
+		return errors.New("alıcı yok")
+	}
+
+	addr := net.JoinHostPort(m.cfg.Host, m.cfg.Port)
+	dialer := &net.Dialer{Timeout: m.cfg.Timeout}
+
+	var conn net.Conn
+	var err error
+	if m.cfg.Security == SecurityTLS {
+		conn, err = tls.DialWithDialer(dialer, "tcp", addr, m.tlsConfig)
+	} else {
+		conn, err = dialer.Dial("tcp", addr)
+	}
+	if err != nil {
+		return fmt.Errorf("SMTP sunucusuna bağlanılamadı (%s): %v", addr, err)
+	}
+	// Yanıt vermeyen sunucuda takılmamak için oturumun tamamına süre sınırı koy
+	conn.SetDeadline(time.Now().Add(m.cfg.Timeout))
+
+	c, err := smtp.NewClient(conn, m.cfg.Host)
+	if err != nil {
+		conn.Close()
+		return fmt.Errorf("SMTP oturumu açılamadı: %v", err)
+	}
+	defer c.Close()
+
+	if m.cfg.Security == SecurityStartTLS {
+		if ok, _ := c.Extension("STARTTLS"); !ok {
+			return errors.New("SMTP sunucusu STARTTLS desteklemiyor (SMTP_SECURITY=tls veya none deneyin)")
+		}
+		if err := c.StartTLS(m.tlsConfig); err != nil {
+			return fmt.Errorf("STARTTLS hatası: %v", err)
+		}
+	}
+
+	if auth := m.auth(); auth != nil {
+		if err := c.Auth(auth); err != nil {
+			return fmt.Errorf("SMTP kimlik doğrulama hatası: %v", err)
+		}
+	}
+
+	if err := c.Mail(m.cfg.From); err != nil {
+		return fmt.Errorf("MAIL FROM reddedildi: %v", err)
+	}
+	for _, rcpt := range recipients {
+		if err := c.Rcpt(rcpt); err != nil {
+			return fmt.Errorf("alıcı reddedildi (%s): %v", rcpt, err)
+		}
+	}
+
+	w, err := c.Data()
+	if err != nil {
+		return fmt.Errorf("DATA reddedildi: %v", err)
+	}
+	if _, err := w.Write(message); err != nil {
+		return fmt.Errorf("mesaj yazılamadı: %v", err)
+	}
+	if err := w.Close(); err != nil {
+		return fmt.Errorf("mesaj kabul edilmedi: %v", err)
+	}
+	return c.Quit()
+}
+
+func (m *Mailer) auth() smtp.Auth {
+	switch m.cfg.Auth {
+	case AuthPlain:
+		return &plainAuth{username: m.cfg.Username, password: m.cfg.Password}
+	case AuthLogin:
+		return &loginAuth{username: m.cfg.Username, password: m.cfg.Password}
+	case AuthXOAuth2:
+		return &xoauth2Auth{username: m.cfg.Username, token: m.cfg.OAuthToken}
+	}
+	return nil
+}
@@ -0,0 +1,169 @@
+package mailer_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"odoo-efor-tracker/mailer"
+	"odoo-efor-tracker/mailer/smtptest"
+)
+
+const testMessage = "Subject: test\r\n\r\nmerhaba\r\n"
+
+func newServer(t *testing.T, opts smtptest.Options) *smtptest.Server {
+	t.Helper()
+	srv, err := smtptest.NewServer(opts)
+	if err != nil {
+		t.Fatalf("SMTP sunucusu başlatılamadı: %v", err)
+	}
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func send(t *testing.T, cfg mailer.Config, to ...string) error {
+	t.Helper()
+	m, err := mailer.New(cfg)
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	return m.Send(to, []byte(testMessage))
+}
+
+func TestSendSecurityAndAuth(t *testing.T) {
+	for _, security := range []string{mailer.SecurityStartTLS, mailer.SecurityTLS, mailer.SecurityNone} {
+		for _, auth := range []string{mailer.AuthPlain, mailer.AuthLogin, mailer.AuthXOAuth2} {
+			t.Run(security+"/"+auth, func(t *testing.T) {
+				srv := newServer(t, smtptest.Options{Security: security})
+				cfg := srv.Config()
+				cfg.Auth = auth
+				if auth == mailer.AuthXOAuth2 {
+					cfg.Password = ""
+					cfg.OAuthToken = smtptest.Token
+				}
+
+				if err := send(t, cfg, "a@example.com", "b@example.com"); err != nil {
+					t.Fatalf("Send: %v", err)
+				}
+
+				msgs := srv.Messages()
+				if len(msgs) != 1 {
+					t.Fatalf("%d mesaj teslim edildi, beklenen 1", len(msgs))
+				}
+				msg := msgs[0]
+				if msg.From != smtptest.From || strings.Join(msg.To, ",") != "a@example.com,b@example.com" {
+					t.Errorf("zarf = %s -> %v", msg.From, msg.To)
+				}
+				if msg.Auth != strings.ToUpper(auth) {
+					t.Errorf("kimlik doğrulama = %s, beklenen %s", msg.Auth, strings.ToUpper(auth))
+				}
+				if msg.TLS != (security != mailer.SecurityNone) {
+					t.Errorf("TLS = %v", msg.TLS)
+				}
+				if !strings.Contains(string(msg.Data), "merhaba") {
+					t.Errorf("mesaj = %q", msg.Data)
+				}
+			})
+		}
+	}
+}
+
+func TestSendRejectsBadCredentials(t *testing.T) {
+	srv := newServer(t, smtptest.Options{})
+
+	cfg := srv.Config()
+	cfg.Password = "yanlis"
+	if err := send(t, cfg, "a@example.com"); err == nil || !strings.Contains(err.Error(), "kimlik doğrulama") {
+		t.Errorf("yanlış parola için hata = %v", err)
+	}
+
+	cfg = srv.Config()
+	cfg.OAuthToken = "suresi-dolmus"
+	if err := send(t, cfg, "a@example.com"); err == nil || !strings.Contains(err.Error(), "535") {
+		t.Errorf("geçersiz token için hata = %v", err)
+	}
+	if len(srv.Messages()) != 0 {
+		t.Error("kimlik doğrulaması başarısız olan mesaj teslim edilmemeli")
+	}
+}
+
+func TestSendVerifiesCertificate(t *testing.T) {
+	srv := newServer(t, smtptest.Options{Security: mailer.SecurityTLS})
+
+	cfg := srv.Config()
+	cfg.CAFile = ""
+	if err := send(t, cfg, "a@example.com"); err == nil || !strings.Contains(err.Error(), "certificate") {
+		t.Errorf("tanınmayan sertifika için hata = %v", err)
+	}
+
+	cfg.InsecureSkipVerify = true
+	if err := send(t, cfg, "a@example.com"); err != nil {
+		t.Errorf("doğrulama kapalıyken: %v", err)
+	}
+}
+
+func TestSendRequiresStartTLS(t *testing.T) {
+	srv := newServer(t, smtptest.Options{Security: mailer.SecurityNone})
+
+	cfg := srv.Config()
+	cfg.Security = mailer.SecurityStartTLS
+	if err := send(t, cfg, "a@example.com"); err == nil || !strings.Contains(err.Error(), "STARTTLS") {
+		t.Errorf("STARTTLS desteklemeyen sunucu için hata = %v", err)
+	}
+}
+
+func TestSendTimeout(t *testing.T) {
+	srv := newServer(t, smtptest.Options{Silent: true})
+
+	cfg := srv.Config()
+	cfg.Timeout = 200 * time.Millisecond
+	start := time.Now()
+	err := send(t, cfg, "a@example.com")
+	if err == nil || !strings.Contains(err.Error(), "timeout") {
+		t.Errorf("yanıt vermeyen sunucu için hata = %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Errorf("zaman aşımı uygulanmadı: %v", elapsed)
+	}
+}
+
+func TestNewValidatesConfig(t *testing.T) {
+	base := mailer.Config{Host: "smtp.example.com", Port: "587", From: "a@example.com"}
+
+	for name, mutate := range map[string]func(*mailer.Config){
+		"eksik host":        func(c *mailer.Config) { c.Host = "" },
+		"geçersiz güvenlik": func(c *mailer.Config) { c.Security = "ssl3" },
+		"parolasız plain":   func(c *mailer.Config) { c.Auth = mailer.AuthPlain; c.Username = "a" },
+		"tokensız xoauth2":  func(c *mailer.Config) { c.Auth = mailer.AuthXOAuth2; c.Username = "a" },
+		"olmayan CA":        func(c *mailer.Config) { c.CAFile = "/olmayan/ca.pem" },
+	} {
+		cfg := base
+		mutate(&cfg)
+		if _, err := mailer.New(cfg); err == nil {
+			t.Errorf("%s: hata bekleniyordu", name)
+		}
+	}
+}
+
+func TestConfigFromEnv(t *testing.T) {
+	t.Setenv("SMTP_HOST", "smtp.office365.com")
+	t.Setenv("SMTP_PORT", "587")
+	t.Setenv("SMTP_USERNAME", "rapor@example.com")
+	t.Setenv("SMTP_FROM", "rapor@example.com")
+	t.Setenv("SMTP_OAUTH_TOKEN", "token")
+	t.Setenv("SMTP_INSECURE_SKIP_VERIFY", "true")
+	t.Setenv("SMTP_TIMEOUT", "10s")
+
+	cfg, err := mailer.ConfigFromEnv()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !cfg.InsecureSkipVerify || cfg.Timeout != 10*time.Second || cfg.OAuthToken != "token" {
+		t.Errorf("cfg = %+v", cfg)
+	}
+
+	t.Setenv("SMTP_TIMEOUT", "on")
+	if _, err := mailer.ConfigFromEnv(); err == nil {
+		t.Error("geçersiz SMTP_TIMEOUT için hata bekleniyordu")
+	}
+}
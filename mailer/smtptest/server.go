@@ -0,0 +1,452 @@
+// Package smtptest, testlerde gerçek SMTP sunucusu yerine kullanılan süreç
+// içi SMTP sunucusunu sağlar. Sunucu düz bağlantı, STARTTLS ve doğrudan TLS
+// ile PLAIN, LOGIN ve XOAUTH2 kimlik doğrulamasını konuşur ve aldığı
+// mesajları kaydeder. TLS için her sunucuya kendinden imzalı bir sertifika
+// üretilir; sertifika Config'in CAFile alanıyla istemciye verilir.
+package smtptest
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"encoding/pem"
+	"fmt"
+	"io"
+	"math/big"
+	"net"
+	"net/textproto"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"odoo-efor-tracker/mailer"
+)
+
+// Sahte sunucunun kabul ettiği varsayılan bilgiler
+const (
+	Host     = "127.0.0.1"
+	From     = "rapor@example.com"
+	Username = "rapor@example.com"
+	Password = "smtp-parola"
+	Token    = "oauth-erisim-tokeni"
+)
+
+// Options, sunucunun davranışı
+type Options struct {
+	// mailer.SecurityStartTLS, mailer.SecurityTLS ya da mailer.SecurityNone
+	Security string
+	// Doğruysa sunucu bağlantıyı kabul eder ama hiç yanıt vermez
+	// (zaman aşımı testleri için)
+	Silent bool
+}
+
+// Message, sunucuya teslim edilen bir e-posta
+type Message struct {
+	From string
+	To   []string
+	Data []byte
+	Auth string // Kullanılan kimlik doğrulama yöntemi (PLAIN, LOGIN, XOAUTH2)
+	TLS  bool   // Mesaj şifreli bağlantı üzerinden mi geldi
+}
+
+// Server, sahte SMTP sunucusu
+type Server struct {
+	Addr   string
+	Port   string
+	CAFile string // Sunucu sertifikasının PEM dosyası
+
+	opts     Options
+	listener net.Listener
+	tlsCfg   *tls.Config
+	dir      string
+	wg       sync.WaitGroup
+
+	mu       sync.Mutex
+	messages []Message
+	conns    []net.Conn
+}
+
+// Yeni bir sunucu başlat
+func NewServer(opts Options) (*Server, error) {
+	if opts.Security == "" {
+		opts.Security = mailer.SecurityStartTLS
+	}
+
+	dir, err := os.MkdirTemp("", "smtptest")
+	if err != nil {
+		return nil, err
+	}
+	cert, certPEM, err := selfSignedCert()
+	if err != nil {
+		os.RemoveAll(dir)
+		return nil, err
+	}
+	caFile := filepath.Join(dir, "ca.pem")
+	if err := os.WriteFile(caFile, certPEM, 0600); err != nil {
+		os.RemoveAll(dir)
+		return nil, err
+	}
+
+	tlsCfg := &tls.Config{Certificates: []tls.Certificate{cert}}
+	var listener net.Listener
+	if opts.Security == mailer.SecurityTLS {
+		listener, err = tls.Listen("tcp", Host+":0", tlsCfg)
+	} else {
+		listener, err = net.Listen("tcp", Host+":0")
+	}
+	if err != nil {
+		os.RemoveAll(dir)
+		return nil, err
+	}
+
+	_, port, _ := net.SplitHostPort(listener.Addr().String())
+	s := &Server{
+		Addr:     listener.Addr().String(),
+		Port:     port,
+		CAFile:   caFile,
+		opts:     opts,
+		listener: listener,
+		tlsCfg:   tlsCfg,
+		dir:      dir,
+	}
+	s.wg.Add(1)
+	go s.serve()
+	return s, nil
+}
+
+// Sunucuyu ve açık bağlantıları kapat
+func (s *Server) Close() {
+	s.listener.Close()
+	s.mu.Lock()
+	for _, conn := range s.conns {
+		conn.Close()
+	}
+	s.mu.Unlock()
+	s.wg.Wait()
+	os.RemoveAll(s.dir)
+}
+
+// Sunucuya bağlanmak için mailer ayarları (parola ile PLAIN)
+func (s *Server) Config() mailer.Config {
+	return mailer.Config{
+		Host:     Host,
+		Port:     s.Port,
+		Username: Username,
+		Password: Password,
+		From:     From,
+		Security: s.opts.Security,
+		CAFile:   s.CAFile,
+		Timeout:  5 * time.Second,
+	}
+}
+
+// Teslim edilen mesajlar
+func (s *Server) Messages() []Message {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]Message(nil), s.messages...)
+}
+
+func (s *Server) serve() {
+	defer s.wg.Done()
+	for {
+		conn, err := s.listener.Accept()
+		if err != nil {
+			return
+		}
+		s.mu.Lock()
+		s.conns = append(s.conns, conn)
+		s.mu.Unlock()
+
+		s.wg.Add(1)
+		go func() {
+			defer s.wg.Done()
+			defer conn.Close()
+			if s.opts.Silent {
+				io.Copy(io.Discard, conn)
+				return
+			}
+			s.handle(conn)
+		}()
+	}
+}
+
+// SMTP oturumu
+type session struct {
+	srv     *Server
+	conn    net.Conn
+	text    *textproto.Conn
+	tls     bool
+	auth    string
+	from    string
+	to      []string
+	greeted bool
+}
+
+func (s *Server) handle(conn net.Conn) {
+	_, isTLS := conn.(*tls.Conn)
+	sess := &session{srv: s, conn: conn, text: textproto.NewConn(conn), tls: isTLS}
+	sess.reply(220, "smtptest hazır")
+
+	for {
+		line, err := sess.text.ReadLine()
+		if err != nil {
+			return
+		}
+		verb, arg, _ := strings.Cut(line, " ")
+		switch strings.ToUpper(verb) {
+		case "EHLO", "HELO":
+			sess.hello()
+		case "STARTTLS":
+			if !sess.startTLS() {
+				return
+			}
+		case "AUTH":
+			sess.authenticate(arg)
+		case "MAIL":
+			sess.mail(arg)
+		case "RCPT":
+			sess.rcpt(arg)
+		case "DATA":
+			sess.data()
+		case "RSET":
+			sess.from, sess.to = "", nil
+			sess.reply(250, "OK")
+		case "NOOP":
+			sess.reply(250, "OK")
+		case "QUIT":
+			sess.reply(221, "güle güle")
+			return
+		default:
+			sess.reply(502, "komut desteklenmiyor")
+		}
+	}
+}
+
+func (c *session) reply(code int, lines ...string) {
+	for i, line := range lines {
+		sep := " "
+		if i < len(lines)-1 {
+			sep = "-"
+		}
+		c.text.PrintfLine("%d%s%s", code, sep, line)
+	}
+}
+
+// Kimlik doğrulama yalnızca şifreli bağlantıda ya da güvenlik kapalıyken sunulur
+func (c *session) authAllowed() bool {
+	return c.tls || c.srv.opts.Security == mailer.SecurityNone
+}
+
+func (c *session) hello() {
+	c.greeted = true
+	lines := []string{"smtptest", "8BITMIME"}
+	if c.srv.opts.Security == mailer.SecurityStartTLS && !c.tls {
+		lines = append(lines, "STARTTLS")
+	}
+	if c.authAllowed() {
+		lines = append(lines, "AUTH PLAIN LOGIN XOAUTH2")
+	}
+	c.reply(250, lines...)
+}
+
+func (c *session) startTLS() bool {
+	if c.tls || c.srv.opts.Security != mailer.SecurityStartTLS {
+		c.reply(502, "STARTTLS kullanılamaz")
+		return true
+	}
+	c.reply(220, "TLS başlatılıyor")
+	tlsConn := tls.Server(c.conn, c.srv.tlsCfg)
+	if err := tlsConn.Handshake(); err != nil {
+		return false
+	}
+	c.conn = tlsConn
+	c.text = textproto.NewConn(tlsConn)
+	c.tls = true
+	c.greeted = false
+	c.auth = ""
+	return true
+}
+
+// Base64 kodlu yanıtı oku
+func (c *session) readResponse() (string, bool) {
+	line, err := c.text.ReadLine()
+	if err != nil || line == "*" {
+		return "", false
+	}
+	data, err := base64.StdEncoding.DecodeString(line)
+	if err != nil {
+		return "", false
+	}
+	return string(data), true
+}
+
+func (c *session) authenticate(arg string) {
+	if !c.authAllowed() {
+		c.reply(538, "kimlik doğrulama için şifreli bağlantı gerekli")
+		return
+	}
+	mech, initial, _ := strings.Cut(arg, " ")
+	mech = strings.ToUpper(mech)
+
+	var response string
+	if initial != "" {
+		data, err := base64.StdEncoding.DecodeString(initial)
+		if err != nil {
+			c.reply(501, "geçersiz base64")
+			return
+		}
+		response = string(data)
+	}
+
+	ok := false
+	switch mech {
+	case "PLAIN":
+		if initial == "" {
+			c.reply(334, "")
+			var read bool
+			if response, read = c.readResponse(); !read {
+				c.reply(501, "kimlik doğrulama iptal edildi")
+				return
+			}
+		}
+		parts := strings.Split(response, "\x00")
+		ok = len(parts) == 3 && parts[1] == Username && parts[2] == Password
+
+	case "LOGIN":
+		c.reply(334, base64.StdEncoding.EncodeToString([]byte("Username:")))
+		user, read := c.readResponse()
+		if !read {
+			c.reply(501, "kimlik doğrulama iptal edildi")
+			return
+		}
+		c.reply(334, base64.StdEncoding.EncodeToString([]byte("Password:")))
+		pass, read := c.readResponse()
+		if !read {
+			c.reply(501, "kimlik doğrulama iptal edildi")
+			return
+		}
+		ok = user == Username && pass == Password
+
+	case "XOAUTH2":
+		ok = response == "user="+Username+"\x01auth=Bearer "+Token+"\x01\x01"
+		if !ok {
+			// Gerçek sunucular gibi önce hata ayrıntısını gönder, boş yanıt bekle
+			detail := `{"status":"401","schemes":"bearer","scope":"https://mail.google.com/"}`
+			c.reply(334, base64.StdEncoding.EncodeToString([]byte(detail)))
+			c.text.ReadLine()
+		}
+
+	default:
+		c.reply(504, "desteklenmeyen kimlik doğrulama yöntemi")
+		return
+	}
+
+	if !ok {
+		c.reply(535, "kimlik bilgileri geçersiz")
+		return
+	}
+	c.auth = mech
+	c.reply(235, "kimlik doğrulandı")
+}
+
+// "FROM:<adres>" biçimindeki argümandan adresi çıkar
+func address(arg, prefix string) (string, bool) {
+	if !strings.HasPrefix(strings.ToUpper(arg), prefix) {
+		return "", false
+	}
+	addr := strings.TrimSpace(arg[len(prefix):])
+	if i := strings.Index(addr, " "); i >= 0 {
+		addr = addr[:i] // BODY=8BITMIME gibi parametreler
+	}
+	return strings.Trim(addr, "<>"), true
+}
+
+func (c *session) mail(arg string) {
+	if !c.greeted {
+		c.reply(503, "önce EHLO gönderin")
+		return
+	}
+	if c.auth == "" {
+		c.reply(530, "kimlik doğrulama gerekli")
+		return
+	}
+	from, ok := address(arg, "FROM:")
+	if !ok {
+		c.reply(501, "söz dizimi: MAIL FROM:<adres>")
+		return
+	}
+	c.from, c.to = from, nil
+	c.reply(250, "OK")
+}
+
+func (c *session) rcpt(arg string) {
+	if c.from == "" {
+		c.reply(503, "önce MAIL FROM gönderin")
+		return
+	}
+	to, ok := address(arg, "TO:")
+	if !ok {
+		c.reply(501, "söz dizimi: RCPT TO:<adres>")
+		return
+	}
+	c.to = append(c.to, to)
+	c.reply(250, "OK")
+}
+
+func (c *session) data() {
+	if len(c.to) == 0 {
+		c.reply(503, "önce RCPT TO gönderin")
+		return
+	}
+	c.reply(354, "mesajı gönderin, <CRLF>.<CRLF> ile bitirin")
+	data, err := io.ReadAll(c.text.DotReader())
+	if err != nil {
+		return
+	}
+
+	c.srv.mu.Lock()
+	c.srv.messages = append(c.srv.messages, Message{
+		From: c.from,
+		To:   c.to,
+		Data: data,
+		Auth: c.auth,
+		TLS:  c.tls,
+	})
+	c.srv.mu.Unlock()
+
+	c.from, c.to = "", nil
+	c.reply(250, "mesaj alındı")
+}
+
+// 127.0.0.1 ve localhost için kendinden imzalı sertifika üret
+func selfSignedCert() (tls.Certificate, []byte, error) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return tls.Certificate{}, nil, err
+	}
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "smtptest"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(24 * time.Hour),
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+		IPAddresses:           []net.IP{net.ParseIP(Host)},
+		DNSNames:              []string{"localhost"},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		return tls.Certificate{}, nil, fmt.Errorf("sertifika üretilemedi: %v", err)
+	}
+	certPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+	return tls.Certificate{Certificate: [][]byte{der}, PrivateKey: key}, certPEM, nil
+}
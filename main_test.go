@@ -0,0 +1,130 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"odoo-efor-tracker/odoo/odootest"
+)
+
+// Sahte Odoo sunucusunu başlat ve bağlantı bilgilerini ortama yaz
+func newFakeOdoo(t *testing.T) *odootest.Server {
+	t.Helper()
+	srv, err := odootest.NewServer("testdata/odoo")
+	if err != nil {
+		t.Fatalf("sahte Odoo başlatılamadı: %v", err)
+	}
+	t.Cleanup(srv.Close)
+
+	cfg := srv.Config("")
+	t.Setenv("ODOO_BASE_URL", cfg.BaseURL)
+	t.Setenv("ODOO_DB", cfg.DB)
+	t.Setenv("ODOO_USERNAME", cfg.Username)
+	t.Setenv("ODOO_PASSWORD", cfg.Password)
+	t.Setenv("ODOO_PROTOCOL", cfg.Protocol)
+
+	teams, err := filepath.Abs("testdata/teams.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("TEAMS_FILE", teams)
+
+	workHours, err := filepath.Abs("testdata/work_hours.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("WORK_HOURS_FILE", workHours)
+
+	holidays, err := filepath.Abs("testdata/holidays.ics")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("HOLIDAYS_FILE", holidays)
+	return srv
+}
+
+// Rapor dosyalarının yazılacağı geçici çalışma dizinine geç
+func chdirTemp(t *testing.T) {
+	t.Helper()
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chdir(t.TempDir()); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.Chdir(wd) })
+}
+
+func runReport(t *testing.T, dateFilter, employeeFilter, teamFilter string) string {
+	t.Helper()
+	period, err := parsePeriod(dateFilter, time.Now())
+	if err != nil {
+		t.Fatalf("parsePeriod: %v", err)
+	}
+	_, outputFileName, err := generateReport(period, employeeFilter, teamFilter, FormatText, "")
+	if err != nil {
+		t.Fatalf("generateReport: %v", err)
+	}
+	content, err := os.ReadFile(outputFileName)
+	if err != nil {
+		t.Fatalf("rapor dosyası okunamadı: %v", err)
+	}
+	return string(content)
+}
+
+func TestGenerateReport(t *testing.T) {
+	newFakeOdoo(t)
+	chdirTemp(t)
+
+	report := runReport(t, "2025-02-03", "", "")
+
+	for _, want := range []string{
+		"Tarih Aralığı: 2025-02-03 - 2025-02-03",
+		"Toplam Çalışma Saati: 13.50",
+		"Osman Çağrı GENÇ: 8.00 saat ====== En ÇOK Çalışmış",
+		"Ayşegül Şahin: 5.50 saat ====== Az Çalışmış",
+		"TEKNOSA: 11.50 saat",
+	} {
+		if !strings.Contains(report, want) {
+			t.Errorf("raporda %q bulunamadı:\n%s", want, report)
+		}
+	}
+	// Ekip listesinde olmayan çalışanlar rapora girmez
+	if strings.Contains(report, "Harici Danışman") {
+		t.Errorf("ekip dışı çalışan raporda yer aldı:\n%s", report)
+	}
+}
+
+func TestGenerateReportEmployeeFilter(t *testing.T) {
+	newFakeOdoo(t)
+	chdirTemp(t)
+
+	report := runReport(t, "2025-02-04", "Fatih Delice", "")
+
+	if !strings.Contains(report, "Toplam Çalışma Saati: 7.00") {
+		t.Errorf("beklenen toplam bulunamadı:\n%s", report)
+	}
+	if strings.Contains(report, "Ayşegül Şahin: 8.00 saat ======") {
+		t.Errorf("filtre dışı çalışan raporda yer aldı:\n%s", report)
+	}
+}
+
+func TestGenerateReportRange(t *testing.T) {
+	newFakeOdoo(t)
+	chdirTemp(t)
+
+	report := runReport(t, "2025-02-03..2025-02-04", "", "")
+
+	for _, want := range []string{
+		"Tarih Aralığı: 2025-02-03 - 2025-02-04",
+		"Toplam Çalışma Saati: 37.00",
+	} {
+		if !strings.Contains(report, want) {
+			t.Errorf("raporda %q bulunamadı:\n%s", want, report)
+		}
+	}
+}
@@ -0,0 +1,196 @@
+package odoo
+
+import (
+	"fmt"
+	"os"
+	"sync"
+)
+
+// Values, create/write çağrılarına gönderilen alan-değer eşlemesi
+type Values map[string]interface{}
+
+// API, Odoo model çağrılarının tiplenmiş arayüzü. Uygulamanın geri kalanı
+// bu arayüze bağımlıdır; böylece testlerde sahte bir istemci kullanılabilir.
+type API interface {
+	SearchRead(model string, domain Domain, opts *SearchOptions, out interface{}) error
+	SearchCount(model string, domain Domain) (int, error)
+	Read(model string, ids []int64, fields []string, out interface{}) error
+	Create(model string, values Values) (int64, error)
+	CreateMany(model string, values []Values) ([]int64, error)
+	Write(model string, ids []int64, values Values) error
+	Unlink(model string, ids []int64) error
+	Fields(model string) (map[string]bool, error)
+}
+
+// SearchOptions, search_read çağrısının sayfalama ve alan seçenekleri.
+// Fields boş bırakılırsa alanlar hedef struct'ın odoo etiketlerinden çıkarılır.
+type SearchOptions struct {
+	Fields []string
+	Offset int
+	Limit  int
+	Order  string
+}
+
+// Config, Odoo bağlantı bilgileri
+type Config struct {
+	BaseURL  string
+	DB       string
+	Username string
+	Password string
+	Protocol string // xmlrpc (varsayılan), jsonrpc veya web
+}
+
+// Bağlantı bilgilerini çevre değişkenlerinden oku
+func ConfigFromEnv() Config {
+	return Config{
+		BaseURL:  os.Getenv("ODOO_BASE_URL"),
+		DB:       os.Getenv("ODOO_DB"),
+		Username: os.Getenv("ODOO_USERNAME"),
+		Password: os.Getenv("ODOO_PASSWORD"),
+		Protocol: os.Getenv("ODOO_PROTOCOL"),
+	}
+}
+
+// Client, kimliği doğrulanmış bir Odoo oturumu
+type Client struct {
+	cfg       Config
+	uid       int
+	transport Transport
+
+	fieldsMu sync.Mutex
+	fields   map[string]map[string]bool // Model başına fields_get sonucu
+}
+
+var _ API = (*Client)(nil)
+
+// Odoo'ya yapılandırmadaki protokolle bağlan ve kimlik doğrula
+func Dial(cfg Config) (*Client, error) {
+	transport, err := NewTransport(cfg)
+	if err != nil {
+		return nil, err
+	}
+	return DialTransport(cfg, transport)
+}
+
+// Verilen taşıma katmanı üzerinden kimlik doğrula
+func DialTransport(cfg Config, transport Transport) (*Client, error) {
+	uid, err := transport.Authenticate(cfg.DB, cfg.Username, cfg.Password)
+	if err != nil {
+		transport.Close()
+		return nil, fmt.Errorf("authentication error: %v", err)
+	}
+
+	return &Client{cfg: cfg, uid: uid, transport: transport}, nil
+}
+
+// Oturum açan kullanıcının ID'si
+func (c *Client) UID() int {
+	return c.uid
+}
+
+// Bağlantıyı kapat
+func (c *Client) Close() error {
+	return c.transport.Close()
+}
+
+// execute_kw çağrısı yap ve sonucu out'a çöz
+func (c *Client) execute(model, method string, args []interface{}, kwargs map[string]interface{}, out interface{}) error {
+	reply, err := c.transport.Execute(c.cfg.DB, c.uid, c.cfg.Password, model, method, args, kwargs)
+	if err != nil {
+		return fmt.Errorf("%s.%s: %w", model, method, err)
+	}
+	if out == nil {
+		return nil
+	}
+	if err := decode(reply, out); err != nil {
+		return fmt.Errorf("%s.%s: %v", model, method, err)
+	}
+	return nil
+}
+
+func (c *Client) SearchRead(model string, domain Domain, opts *SearchOptions, out interface{}) error {
+	if opts == nil {
+		opts = &SearchOptions{}
+	}
+	fields := opts.Fields
+	if len(fields) == 0 {
+		fields = FieldsOf(out)
+	}
+
+	kwargs := map[string]interface{}{"fields": fields}
+	if opts.Offset > 0 {
+		kwargs["offset"] = opts.Offset
+	}
+	if opts.Limit > 0 {
+		kwargs["limit"] = opts.Limit
+	}
+	if opts.Order != "" {
+		kwargs["order"] = opts.Order
+	}
+
+	return c.execute(model, "search_read", []interface{}{domain.Args()}, kwargs, out)
+}
+
+func (c *Client) SearchCount(model string, domain Domain) (int, error) {
+	var count int
+	err := c.execute(model, "search_count", []interface{}{domain.Args()}, nil, &count)
+	return count, err
+}
+
+func (c *Client) Read(model string, ids []int64, fields []string, out interface{}) error {
+	if len(fields) == 0 {
+		fields = FieldsOf(out)
+	}
+	return c.execute(model, "read", []interface{}{ids}, map[string]interface{}{"fields": fields}, out)
+}
+
+func (c *Client) Create(model string, values Values) (int64, error) {
+	var id int64
+	err := c.execute(model, "create", []interface{}{map[string]interface{}(values)}, nil, &id)
+	return id, err
+}
+
+// Kayıtları tek create çağrısıyla oluştur; ID'ler girdiyle aynı sırada döner
+func (c *Client) CreateMany(model string, values []Values) ([]int64, error) {
+	list := make([]interface{}, 0, len(values))
+	for _, v := range values {
+		list = append(list, map[string]interface{}(v))
+	}
+	var ids []int64
+	err := c.execute(model, "create", []interface{}{list}, nil, &ids)
+	return ids, err
+}
+
+func (c *Client) Write(model string, ids []int64, values Values) error {
+	return c.execute(model, "write", []interface{}{ids, map[string]interface{}(values)}, nil, nil)
+}
+
+func (c *Client) Unlink(model string, ids []int64) error {
+	return c.execute(model, "unlink", []interface{}{ids}, nil, nil)
+}
+
+// Modelin alan adları (fields_get). Alanlar Odoo sürümüne ve kurulu
+// modüllere göre değiştiğinden var olmayan alanları okumamak için kullanılır;
+// sonuç oturum boyunca saklanır.
+func (c *Client) Fields(model string) (map[string]bool, error) {
+	c.fieldsMu.Lock()
+	defer c.fieldsMu.Unlock()
+	if fields, ok := c.fields[model]; ok {
+		return fields, nil
+	}
+
+	var reply map[string]interface{}
+	err := c.execute(model, "fields_get", []interface{}{}, map[string]interface{}{"attributes": []string{"type"}}, &reply)
+	if err != nil {
+		return nil, err
+	}
+	fields := make(map[string]bool, len(reply))
+	for name := range reply {
+		fields[name] = true
+	}
+	if c.fields == nil {
+		c.fields = map[string]map[string]bool{}
+	}
+	c.fields[model] = fields
+	return fields, nil
+}
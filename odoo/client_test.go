@@ -0,0 +1,126 @@
+package odoo_test
+
+import (
+	"errors"
+	"testing"
+
+	"odoo-efor-tracker/odoo"
+	"odoo-efor-tracker/odoo/odootest"
+)
+
+func newServer(t *testing.T) *odootest.Server {
+	t.Helper()
+	srv, err := odootest.NewServer("../testdata/odoo")
+	if err != nil {
+		t.Fatalf("sahte sunucu başlatılamadı: %v", err)
+	}
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestClientProtocols(t *testing.T) {
+	for _, protocol := range []string{odoo.ProtocolXMLRPC, odoo.ProtocolJSONRPC, odoo.ProtocolWeb} {
+		t.Run(protocol, func(t *testing.T) {
+			srv := newServer(t)
+			client, err := odoo.Dial(srv.Config(protocol))
+			if err != nil {
+				t.Fatalf("Dial: %v", err)
+			}
+			defer client.Close()
+
+			if client.UID() != odootest.AdminUID {
+				t.Errorf("UID = %d, beklenen %d", client.UID(), odootest.AdminUID)
+			}
+
+			domain := odoo.NewDomain().
+				Where("date", "=", "2025-02-03").
+				Where("employee_id", "=", 1)
+			var lines []odoo.TimesheetLine
+			if err := client.SearchRead(odoo.ModelTimesheet, domain, &odoo.SearchOptions{Order: "id"}, &lines); err != nil {
+				t.Fatalf("SearchRead: %v", err)
+			}
+			if len(lines) != 2 {
+				t.Fatalf("%d kayıt döndü, beklenen 2", len(lines))
+			}
+			if lines[0].Employee.Name != "Osman Çağrı GENÇ" || lines[0].Task.Name != "CX-7006" || lines[0].UnitAmount != 6 {
+				t.Errorf("beklenmeyen kayıt: %+v", lines[0])
+			}
+
+			count, err := client.SearchCount(odoo.ModelTimesheet, odoo.Or(
+				odoo.NewDomain().Where("employee_id", "=", 3),
+				odoo.NewDomain().Where("project_id", "ilike", "cx portal"),
+			))
+			if err != nil {
+				t.Fatalf("SearchCount: %v", err)
+			}
+			if count != 2 {
+				t.Errorf("SearchCount = %d, beklenen 2", count)
+			}
+
+			id, err := client.Create(odoo.ModelTimesheet, odoo.Values{
+				"date":        "2025-02-05",
+				"name":        "Yeni kayıt",
+				"unit_amount": 1.5,
+				"project_id":  1,
+			})
+			if err != nil {
+				t.Fatalf("Create: %v", err)
+			}
+			if err := client.Write(odoo.ModelTimesheet, []int64{id}, odoo.Values{"unit_amount": 2.25}); err != nil {
+				t.Fatalf("Write: %v", err)
+			}
+
+			var created []odoo.TimesheetLine
+			if err := client.Read(odoo.ModelTimesheet, []int64{id}, nil, &created); err != nil {
+				t.Fatalf("Read: %v", err)
+			}
+			if len(created) != 1 || created[0].UnitAmount != 2.25 || created[0].Project.Name != "TEKNOSA" {
+				t.Errorf("beklenmeyen kayıt: %+v", created)
+			}
+
+			ids, err := client.CreateMany(odoo.ModelTimesheet, []odoo.Values{
+				{"date": "2025-02-05", "name": "Toplu 1", "unit_amount": 1.0, "project_id": 1},
+				{"date": "2025-02-05", "name": "Toplu 2", "unit_amount": 2.0, "project_id": 3},
+			})
+			if err != nil {
+				t.Fatalf("CreateMany: %v", err)
+			}
+			if len(ids) != 2 || ids[0] == ids[1] {
+				t.Fatalf("CreateMany ID'leri = %v", ids)
+			}
+			if err := client.Unlink(odoo.ModelTimesheet, append(ids, id)); err != nil {
+				t.Fatalf("Unlink: %v", err)
+			}
+			if n := len(srv.Records(odoo.ModelTimesheet)); n != 7 {
+				t.Errorf("silme sonrası %d kayıt var, beklenen 7", n)
+			}
+		})
+	}
+}
+
+func TestDialRejectsWrongPassword(t *testing.T) {
+	srv := newServer(t)
+	cfg := srv.Config(odoo.ProtocolXMLRPC)
+	cfg.Password = "yanlış"
+	if _, err := odoo.Dial(cfg); err == nil {
+		t.Fatal("hatalı şifre ile bağlantı kuruldu")
+	}
+}
+
+func TestJSONRPCReportsOdooError(t *testing.T) {
+	srv := newServer(t)
+	client, err := odoo.Dial(srv.Config(odoo.ProtocolJSONRPC))
+	if err != nil {
+		t.Fatalf("Dial: %v", err)
+	}
+	defer client.Close()
+
+	err = client.Write(odoo.ModelTimesheet, []int64{999}, odoo.Values{"name": "x"})
+	var odooErr *odoo.Error
+	if !errors.As(err, &odooErr) {
+		t.Fatalf("odoo.Error bekleniyordu, gelen: %v", err)
+	}
+	if odooErr.Name != "odoo.exceptions.MissingError" {
+		t.Errorf("hata sınıfı = %q", odooErr.Name)
+	}
+}
@@ -0,0 +1,198 @@
+package odoo
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"time"
+)
+
+var many2OneType = reflect.TypeOf(Many2One{})
+
+// Hedef struct'ın odoo etiketlerinden alan listesini çıkar.
+// out bir struct, struct dilimi ya da bunlara işaretçi olabilir.
+func FieldsOf(out interface{}) []string {
+	t := reflect.TypeOf(out)
+	for t != nil && (t.Kind() == reflect.Ptr || t.Kind() == reflect.Slice) {
+		t = t.Elem()
+	}
+	if t == nil || t.Kind() != reflect.Struct {
+		return nil
+	}
+
+	var fields []string
+	for i := 0; i < t.NumField(); i++ {
+		if tag := t.Field(i).Tag.Get("odoo"); tag != "" && tag != "-" {
+			fields = append(fields, tag)
+		}
+	}
+	return fields
+}
+
+// RPC'den gelen genel değeri (map, dilim, sayı, false...) hedef tipe çöz.
+// Taşıma katmanından bağımsızdır; XML-RPC ve JSON-RPC yanıtları aynı
+// şekilde işlenir.
+func decode(src interface{}, out interface{}) error {
+	v := reflect.ValueOf(out)
+	if v.Kind() != reflect.Ptr || v.IsNil() {
+		return fmt.Errorf("decode hedefi işaretçi olmalı: %T", out)
+	}
+	return decodeValue(src, v.Elem())
+}
+
+func decodeValue(src interface{}, dst reflect.Value) error {
+	// Odoo boş alanlar için false döner; hedefi sıfır değerinde bırak
+	if b, ok := src.(bool); src == nil || (ok && !b && dst.Kind() != reflect.Bool) {
+		dst.Set(reflect.Zero(dst.Type()))
+		return nil
+	}
+
+	if dst.Type() == many2OneType {
+		return decodeMany2One(src, dst)
+	}
+
+	switch dst.Kind() {
+	case reflect.Interface:
+		dst.Set(reflect.ValueOf(src))
+		return nil
+
+	case reflect.Ptr:
+		if dst.IsNil() {
+			dst.Set(reflect.New(dst.Type().Elem()))
+		}
+		return decodeValue(src, dst.Elem())
+
+	case reflect.String:
+		switch s := src.(type) {
+		case string:
+			dst.SetString(s)
+		case time.Time:
+			dst.SetString(s.Format("2006-01-02 15:04:05"))
+		default:
+			return fmt.Errorf("%T değeri string'e çözülemez", src)
+		}
+		return nil
+
+	case reflect.Bool:
+		b, ok := src.(bool)
+		if !ok {
+			return fmt.Errorf("%T değeri bool'a çözülemez", src)
+		}
+		dst.SetBool(b)
+		return nil
+
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := toFloat(src)
+		if err != nil {
+			return err
+		}
+		dst.SetInt(int64(n))
+		return nil
+
+	case reflect.Float32, reflect.Float64:
+		n, err := toFloat(src)
+		if err != nil {
+			return err
+		}
+		dst.SetFloat(n)
+		return nil
+
+	case reflect.Slice:
+		items, ok := src.([]interface{})
+		if !ok {
+			return fmt.Errorf("%T değeri dilime çözülemez", src)
+		}
+		slice := reflect.MakeSlice(dst.Type(), len(items), len(items))
+		for i, item := range items {
+			if err := decodeValue(item, slice.Index(i)); err != nil {
+				return err
+			}
+		}
+		dst.Set(slice)
+		return nil
+
+	case reflect.Map:
+		m, ok := src.(map[string]interface{})
+		if !ok || dst.Type().Key().Kind() != reflect.String {
+			return fmt.Errorf("%T değeri %s tipine çözülemez", src, dst.Type())
+		}
+		result := reflect.MakeMapWithSize(dst.Type(), len(m))
+		for k, item := range m {
+			elem := reflect.New(dst.Type().Elem()).Elem()
+			if err := decodeValue(item, elem); err != nil {
+				return fmt.Errorf("%s: %v", k, err)
+			}
+			result.SetMapIndex(reflect.ValueOf(k).Convert(dst.Type().Key()), elem)
+		}
+		dst.Set(result)
+		return nil
+
+	case reflect.Struct:
+		m, ok := src.(map[string]interface{})
+		if !ok {
+			return fmt.Errorf("%T değeri %s tipine çözülemez", src, dst.Type())
+		}
+		t := dst.Type()
+		for i := 0; i < t.NumField(); i++ {
+			tag := t.Field(i).Tag.Get("odoo")
+			if tag == "" || tag == "-" {
+				continue
+			}
+			item, ok := m[tag]
+			if !ok {
+				continue
+			}
+			if err := decodeValue(item, dst.Field(i)); err != nil {
+				return fmt.Errorf("%s: %v", tag, err)
+			}
+		}
+		return nil
+	}
+
+	return fmt.Errorf("desteklenmeyen hedef tip: %s", dst.Type())
+}
+
+// [id, "ad"] çiftini ya da tek başına id'yi Many2One'a çöz
+func decodeMany2One(src interface{}, dst reflect.Value) error {
+	var m Many2One
+	switch v := src.(type) {
+	case []interface{}:
+		if len(v) > 0 {
+			id, err := toFloat(v[0])
+			if err != nil {
+				return err
+			}
+			m.ID = int64(id)
+		}
+		if len(v) > 1 {
+			m.Name, _ = v[1].(string)
+		}
+	default:
+		id, err := toFloat(v)
+		if err != nil {
+			return err
+		}
+		m.ID = int64(id)
+	}
+	dst.Set(reflect.ValueOf(m))
+	return nil
+}
+
+// XML-RPC int64, JSON-RPC float64 döndürdüğü için sayıları ortak tipe çevir
+func toFloat(src interface{}) (float64, error) {
+	switch n := src.(type) {
+	case int:
+		return float64(n), nil
+	case int32:
+		return float64(n), nil
+	case int64:
+		return float64(n), nil
+	case float32:
+		return float64(n), nil
+	case float64:
+		return n, nil
+	case string:
+		return strconv.ParseFloat(n, 64)
+	}
+	return 0, fmt.Errorf("%T değeri sayıya çözülemez", src)
+}
@@ -0,0 +1,80 @@
+package odoo
+
+// Domain, Odoo arama kriteri (prefix/polish notasyonunda koşul listesi).
+// Ardışık koşullar Odoo tarafında VE ile birleştirilir.
+//
+//	odoo.NewDomain().
+//		Where("date", ">=", "2025-02-01").
+//		Where("employee_id", "in", []int64{7, 9})
+type Domain []interface{}
+
+// Boş bir domain oluştur (tüm kayıtlar)
+func NewDomain() Domain {
+	return Domain{}
+}
+
+// Yeni bir koşul ekle
+func (d Domain) Where(field, operator string, value interface{}) Domain {
+	return append(d, []interface{}{field, operator, value})
+}
+
+// Domain'deki üst düzey terimlerin sayısı. Operatörler (&, |, !) kendisinden
+// sonraki terimleri tek bir terimde birleştirir.
+func (d Domain) terms() int {
+	n := 0
+	for _, term := range d {
+		switch term {
+		case "&", "|":
+			n--
+		case "!":
+		default:
+			n++
+		}
+	}
+	return n
+}
+
+// Verilen domain'lerden herhangi birine uyan kayıtlar için VEYA domain'i
+// oluştur. Her bir alt domain kendi içinde VE ile birleştirilir. Boş alt
+// domain'ler atlanır; hepsi boşsa sonuç da boş domain'dir.
+func Or(domains ...Domain) Domain {
+	var subs []Domain
+	for _, sub := range domains {
+		if len(sub) > 0 {
+			subs = append(subs, sub)
+		}
+	}
+
+	result := NewDomain()
+	// n domain için başta n-1 adet "|" operatörü gerekir
+	for i := 1; i < len(subs); i++ {
+		result = append(result, "|")
+	}
+	for _, sub := range subs {
+		result = append(result, sub.grouped()...)
+	}
+	return result
+}
+
+// Alt domain'i tek bir terim gibi davranacak şekilde VE ile grupla
+func (d Domain) grouped() Domain {
+	var result Domain
+	for j := 1; j < d.terms(); j++ {
+		result = append(result, "&")
+	}
+	return append(result, d...)
+}
+
+// Tek bir koşulu ya da alt domain'i değilleyerek ekle
+func (d Domain) Not(sub Domain) Domain {
+	d = append(d, "!")
+	return append(d, sub.grouped()...)
+}
+
+// RPC'ye gönderilecek ham değer
+func (d Domain) Args() []interface{} {
+	if d == nil {
+		return []interface{}{}
+	}
+	return []interface{}(d)
+}
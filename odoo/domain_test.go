@@ -0,0 +1,33 @@
+package odoo_test
+
+import (
+	"reflect"
+	"testing"
+
+	"odoo-efor-tracker/odoo"
+)
+
+func TestOr(t *testing.T) {
+	a := odoo.NewDomain().Where("employee_id", "=", 3)
+	b := odoo.NewDomain().Where("project_id", "=", 1).Where("date", ">=", "2025-02-01")
+
+	for _, tt := range []struct {
+		name    string
+		domains []odoo.Domain
+		want    odoo.Domain
+	}{
+		{"tek", []odoo.Domain{a}, a},
+		{"iki", []odoo.Domain{a, b}, odoo.Domain{"|", a[0], "&", b[0], b[1]}},
+		{"boş alt domain atlanır", []odoo.Domain{odoo.NewDomain(), a, nil, b},
+			odoo.Domain{"|", a[0], "&", b[0], b[1]}},
+		{"tek dolu alt domain", []odoo.Domain{odoo.NewDomain(), a}, a},
+		{"hepsi boş", []odoo.Domain{odoo.NewDomain(), nil}, odoo.NewDomain()},
+		{"hiç yok", nil, odoo.NewDomain()},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := odoo.Or(tt.domains...); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Or = %v, beklenen %v", got, tt.want)
+			}
+		})
+	}
+}
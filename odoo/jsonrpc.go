@@ -0,0 +1,182 @@
+package odoo
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/cookiejar"
+	"sync/atomic"
+	"time"
+)
+
+// Error, JSON-RPC üzerinden dönen Odoo hatası. XML-RPC'nin aksine hata
+// sınıfını (örn. odoo.exceptions.AccessError) ve asıl mesajı ayrı taşır.
+type Error struct {
+	Code    int
+	Message string
+	Name    string
+	Detail  string
+}
+
+func (e *Error) Error() string {
+	if e.Name != "" && e.Detail != "" {
+		return fmt.Sprintf("%s: %s", e.Name, e.Detail)
+	}
+	return fmt.Sprintf("odoo hatası %d: %s", e.Code, e.Message)
+}
+
+type rpcRequest struct {
+	JSONRPC string      `json:"jsonrpc"`
+	Method  string      `json:"method"`
+	Params  interface{} `json:"params"`
+	ID      int64       `json:"id"`
+}
+
+type rpcResponse struct {
+	Result interface{} `json:"result"`
+	Error  *struct {
+		Code    int    `json:"code"`
+		Message string `json:"message"`
+		Data    struct {
+			Name    string `json:"name"`
+			Message string `json:"message"`
+		} `json:"data"`
+	} `json:"error"`
+}
+
+// JSON-RPC isteklerinin ortak HTTP katmanı
+type jsonClient struct {
+	baseURL string
+	http    *http.Client
+	nextID  int64
+}
+
+func (c *jsonClient) call(path string, params interface{}) (interface{}, error) {
+	body, err := json.Marshal(rpcRequest{
+		JSONRPC: "2.0",
+		Method:  "call",
+		Params:  params,
+		ID:      atomic.AddInt64(&c.nextID, 1),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := c.http.Post(c.baseURL+path, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%s: HTTP %s", path, resp.Status)
+	}
+
+	var result rpcResponse
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("%s: yanıt çözülemedi: %v", path, err)
+	}
+	if result.Error != nil {
+		return nil, &Error{
+			Code:    result.Error.Code,
+			Message: result.Error.Message,
+			Name:    result.Error.Data.Name,
+			Detail:  result.Error.Data.Message,
+		}
+	}
+	return result.Result, nil
+}
+
+// /jsonrpc uç noktası; XML-RPC ile aynı servis/metot yapısını kullanır
+type jsonrpcTransport struct {
+	jsonClient
+}
+
+func newJSONRPCTransport(baseURL string) *jsonrpcTransport {
+	return &jsonrpcTransport{jsonClient{
+		baseURL: baseURL,
+		http:    &http.Client{Timeout: 2 * time.Minute},
+	}}
+}
+
+func (t *jsonrpcTransport) service(service, method string, args ...interface{}) (interface{}, error) {
+	return t.call("/jsonrpc", map[string]interface{}{
+		"service": service,
+		"method":  method,
+		"args":    args,
+	})
+}
+
+func (t *jsonrpcTransport) Authenticate(db, login, password string) (int, error) {
+	reply, err := t.service("common", "authenticate", db, login, password, map[string]interface{}{})
+	if err != nil {
+		return 0, err
+	}
+	return authenticatedUID(reply)
+}
+
+func (t *jsonrpcTransport) Execute(db string, uid int, password, model, method string, args []interface{}, kwargs map[string]interface{}) (interface{}, error) {
+	if kwargs == nil {
+		kwargs = map[string]interface{}{}
+	}
+	return t.service("object", "execute_kw", db, uid, password, model, method, args, kwargs)
+}
+
+func (t *jsonrpcTransport) Close() error {
+	t.http.CloseIdleConnections()
+	return nil
+}
+
+// Web istemcisinin kullandığı oturum tabanlı uç noktalar. Kimlik doğrulama
+// bir oturum çerezi üretir; sonraki çağrılar bu çerezle yapılır.
+type webTransport struct {
+	jsonClient
+}
+
+func newWebTransport(baseURL string) (*webTransport, error) {
+	jar, err := cookiejar.New(nil)
+	if err != nil {
+		return nil, err
+	}
+	return &webTransport{jsonClient{
+		baseURL: baseURL,
+		http:    &http.Client{Timeout: 2 * time.Minute, Jar: jar},
+	}}, nil
+}
+
+func (t *webTransport) Authenticate(db, login, password string) (int, error) {
+	reply, err := t.call("/web/session/authenticate", map[string]interface{}{
+		"db":       db,
+		"login":    login,
+		"password": password,
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	var session struct {
+		UID int `odoo:"uid"`
+	}
+	if err := decode(reply, &session); err != nil || session.UID == 0 {
+		return 0, fmt.Errorf("geçersiz kullanıcı adı veya şifre")
+	}
+	return session.UID, nil
+}
+
+func (t *webTransport) Execute(db string, uid int, password, model, method string, args []interface{}, kwargs map[string]interface{}) (interface{}, error) {
+	if kwargs == nil {
+		kwargs = map[string]interface{}{}
+	}
+	return t.call(fmt.Sprintf("/web/dataset/call_kw/%s/%s", model, method), map[string]interface{}{
+		"model":  model,
+		"method": method,
+		"args":   args,
+		"kwargs": kwargs,
+	})
+}
+
+func (t *webTransport) Close() error {
+	t.http.CloseIdleConnections()
+	return nil
+}
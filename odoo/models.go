@@ -0,0 +1,100 @@
+package odoo
+
+// Kullanılan Odoo model adları
+const (
+	ModelTimesheet  = "account.analytic.line"
+	ModelProject    = "project.project"
+	ModelTask       = "project.task"
+	ModelEmployee   = "hr.employee"
+	ModelLeave      = "hr.leave"
+	ModelHoliday    = "resource.calendar.leaves"
+	ModelCalendar   = "resource.calendar"
+	ModelAttendance = "resource.calendar.attendance"
+)
+
+// Odoo tarih-saat alanlarının biçimi; değerler UTC'dir
+const DateTimeLayout = "2006-01-02 15:04:05"
+
+// Many2One, Odoo'nun [id, "görünen ad"] biçimindeki ilişki alanı.
+// Alan boşsa Odoo false döner; bu durumda ID sıfırdır.
+type Many2One struct {
+	ID   int64  `json:"id"`
+	Name string `json:"name"`
+}
+
+// İlişki alanı dolu mu
+func (m Many2One) Valid() bool {
+	return m.ID != 0
+}
+
+// Zaman çizelgesi kaydı (account.analytic.line)
+type TimesheetLine struct {
+	ID          int64    `odoo:"id" json:"id"`
+	Date        string   `odoo:"date" json:"date"`
+	Employee    Many2One `odoo:"employee_id" json:"employee"`
+	Project     Many2One `odoo:"project_id" json:"project"`
+	Task        Many2One `odoo:"task_id" json:"task"`
+	Description string   `odoo:"name" json:"description"`
+	UnitAmount  float64  `odoo:"unit_amount" json:"hours"`
+}
+
+// Proje (project.project)
+type Project struct {
+	ID   int64  `odoo:"id"`
+	Name string `odoo:"name"`
+}
+
+// Görev (project.task)
+type Task struct {
+	ID      int64    `odoo:"id"`
+	Name    string   `odoo:"name"`
+	Project Many2One `odoo:"project_id"`
+}
+
+// Çalışan (hr.employee)
+type Employee struct {
+	ID         int64    `odoo:"id"`
+	Name       string   `odoo:"name"`
+	Department Many2One `odoo:"department_id"`
+	WorkEmail  string   `odoo:"work_email"`
+	Calendar   Many2One `odoo:"resource_calendar_id"`
+}
+
+// İzin talebi (hr.leave). State "validate" ise izin onaylanmıştır.
+type Leave struct {
+	ID       int64    `odoo:"id"`
+	Employee Many2One `odoo:"employee_id"`
+	DateFrom string   `odoo:"date_from"`
+	DateTo   string   `odoo:"date_to"`
+	State    string   `odoo:"state"`
+	HalfDay  bool     `odoo:"request_unit_half"`
+}
+
+// Çalışma takvimi izni (resource.calendar.leaves). Resource boşsa kayıt
+// herkes için geçerli bir tatildir.
+type Holiday struct {
+	ID       int64    `odoo:"id"`
+	Name     string   `odoo:"name"`
+	DateFrom string   `odoo:"date_from"`
+	DateTo   string   `odoo:"date_to"`
+	Resource Many2One `odoo:"resource_id"`
+}
+
+// Çalışma takvimi satırı (resource.calendar.attendance). DayOfWeek "0"
+// (pazartesi) ile "6" (pazar) arasındadır; saatler ondalık gösterimdedir
+// (13.5 = 13:30). Odoo 17'de öğle arası da "lunch" dönemi olarak gelir.
+// İki haftalık takvimlerde WeekType "0" ya da "1"dir ve haftaları ayıran
+// başlık satırlarında DisplayType doludur. DateFrom/DateTo boşsa satır
+// her zaman geçerlidir.
+type Attendance struct {
+	ID          int64    `odoo:"id"`
+	Calendar    Many2One `odoo:"calendar_id"`
+	DayOfWeek   string   `odoo:"dayofweek"`
+	HourFrom    float64  `odoo:"hour_from"`
+	HourTo      float64  `odoo:"hour_to"`
+	DayPeriod   string   `odoo:"day_period"`
+	WeekType    string   `odoo:"week_type"`
+	DisplayType string   `odoo:"display_type"`
+	DateFrom    string   `odoo:"date_from"`
+	DateTo      string   `odoo:"date_to"`
+}
@@ -0,0 +1,215 @@
+package odootest
+
+import (
+	"fmt"
+	"strings"
+)
+
+type matcher func(record) bool
+
+// Odoo domain'ini (prefix notasyonu) kayıt eşleyicisine çevir.
+// Üst düzeydeki terimler VE ile birleştirilir.
+func (s *store) compileDomain(domain []interface{}) (matcher, error) {
+	var terms []matcher
+	for pos := 0; pos < len(domain); {
+		m, next, err := s.compileTerm(domain, pos)
+		if err != nil {
+			return nil, err
+		}
+		terms = append(terms, m)
+		pos = next
+	}
+	return func(rec record) bool {
+		for _, m := range terms {
+			if !m(rec) {
+				return false
+			}
+		}
+		return true
+	}, nil
+}
+
+func (s *store) compileTerm(domain []interface{}, pos int) (matcher, int, error) {
+	if pos >= len(domain) {
+		return nil, pos, fmt.Errorf("eksik domain terimi")
+	}
+
+	switch term := domain[pos].(type) {
+	case string:
+		switch term {
+		case "!":
+			m, next, err := s.compileTerm(domain, pos+1)
+			if err != nil {
+				return nil, 0, err
+			}
+			return func(rec record) bool { return !m(rec) }, next, nil
+		case "&", "|":
+			left, next, err := s.compileTerm(domain, pos+1)
+			if err != nil {
+				return nil, 0, err
+			}
+			right, next, err := s.compileTerm(domain, next)
+			if err != nil {
+				return nil, 0, err
+			}
+			if term == "&" {
+				return func(rec record) bool { return left(rec) && right(rec) }, next, nil
+			}
+			return func(rec record) bool { return left(rec) || right(rec) }, next, nil
+		}
+		return nil, 0, fmt.Errorf("bilinmeyen domain operatörü: %s", term)
+
+	case []interface{}:
+		if len(term) != 3 {
+			return nil, 0, fmt.Errorf("geçersiz domain koşulu: %v", term)
+		}
+		field, _ := term[0].(string)
+		op, _ := term[1].(string)
+		if related, ok := many2many[field]; ok {
+			m, err := s.compileMany2Many(field, related, op, term[2])
+			return m, pos + 1, err
+		}
+		m, err := compileLeaf(field, op, term[2])
+		return m, pos + 1, err
+	}
+	return nil, 0, fmt.Errorf("geçersiz domain terimi: %v", domain[pos])
+}
+
+// Kayıttaki alan değeri; kayıtta yoksa okumadaki gibi varsayılanı döner
+func fieldValue(rec record, field string) interface{} {
+	if v, ok := rec[field]; ok {
+		return v
+	}
+	return defaults[field]
+}
+
+func compileLeaf(field, op string, value interface{}) (matcher, error) {
+	switch op {
+	case "=", "!=":
+		return func(rec record) bool {
+			return equal(fieldValue(rec, field), value) == (op == "=")
+		}, nil
+	case "<", "<=", ">", ">=":
+		return func(rec record) bool {
+			v, ok := rec[field]
+			if !ok || v == nil || v == false {
+				return false
+			}
+			c := compare(v, value)
+			switch op {
+			case "<":
+				return c < 0
+			case "<=":
+				return c <= 0
+			case ">":
+				return c > 0
+			}
+			return c >= 0
+		}, nil
+	case "in", "not in":
+		values, _ := value.([]interface{})
+		return func(rec record) bool {
+			found := false
+			for _, v := range values {
+				if equal(fieldValue(rec, field), v) {
+					found = true
+					break
+				}
+			}
+			return found == (op == "in")
+		}, nil
+	case "like", "ilike", "not ilike", "=like", "=ilike":
+		pattern := fmt.Sprint(value)
+		fold := strings.Contains(op, "ilike")
+		exact := strings.HasPrefix(op, "=")
+		negate := strings.HasPrefix(op, "not")
+		return func(rec record) bool {
+			text := fmt.Sprint(displayValue(rec[field]))
+			p := pattern
+			if fold {
+				text, p = strings.ToLower(text), strings.ToLower(p)
+			}
+			var ok bool
+			if exact {
+				ok = text == strings.ReplaceAll(p, "%", "")
+			} else {
+				ok = strings.Contains(text, strings.ReplaceAll(p, "%", ""))
+			}
+			return ok != negate
+		}, nil
+	}
+	return nil, fmt.Errorf("desteklenmeyen domain operatörü: %s", op)
+}
+
+// Many2many koşulu: ilişkili kayıtlardan herhangi biri değerle (ID ya da ad)
+// eşleşiyorsa kayıt eşleşir
+func (s *store) compileMany2Many(field, related, op string, value interface{}) (matcher, error) {
+	var values []interface{}
+	switch op {
+	case "=", "in", "ilike":
+		if list, ok := value.([]interface{}); ok {
+			values = list
+		} else {
+			values = []interface{}{value}
+		}
+	default:
+		return nil, fmt.Errorf("many2many için desteklenmeyen operatör: %s", op)
+	}
+
+	return func(rec record) bool {
+		list, _ := rec[field].([]interface{})
+		for _, item := range list {
+			id, _ := toInt(item)
+			name := ""
+			if target := s.find(related, id); target != nil {
+				name, _ = target["name"].(string)
+			}
+			for _, v := range values {
+				if n, ok := toInt(v); ok && n == id {
+					return true
+				}
+				text, _ := v.(string)
+				if op == "ilike" && text != "" && strings.Contains(strings.ToLower(name), strings.ToLower(text)) {
+					return true
+				}
+				if op != "ilike" && text == name {
+					return true
+				}
+			}
+		}
+		return false
+	}, nil
+}
+
+// Many2one alanlarında eşitlik ID üzerinden, diğerlerinde değer üzerinden
+func equal(field, value interface{}) bool {
+	if field == nil {
+		field = false
+	}
+	if pair, ok := field.([]interface{}); ok {
+		id, _ := toInt(pair)
+		if value == false {
+			return false
+		}
+		if v, ok := toInt(value); ok {
+			return id == v
+		}
+		return len(pair) > 1 && pair[1] == value
+	}
+	if fa, ok := toFloat(field); ok {
+		fb, ok := toFloat(value)
+		return ok && fa == fb
+	}
+	return field == value
+}
+
+// Metin karşılaştırmaları için many2one alanının görünen adı
+func displayValue(v interface{}) interface{} {
+	if pair, ok := v.([]interface{}); ok && len(pair) > 1 {
+		return pair[1]
+	}
+	if v == nil || v == false {
+		return ""
+	}
+	return v
+}
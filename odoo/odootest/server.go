@@ -0,0 +1,459 @@
+// Package odootest, testlerde canlı Odoo yerine kullanılan süreç içi sahte
+// Odoo sunucusunu sağlar. Sunucu XML-RPC (/xmlrpc/2/common, /xmlrpc/2/object),
+// /jsonrpc ve /web/dataset/call_kw uç noktalarını konuşur; kayıtlar
+// fixture dizinindeki <model>.json dosyalarından yüklenir.
+package odootest
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+
+	"odoo-efor-tracker/odoo"
+)
+
+// Sahte sunucunun kabul ettiği varsayılan bağlantı bilgileri
+const (
+	DB       = "test"
+	Username = "admin"
+	Password = "admin"
+	AdminUID = 2
+)
+
+// Fault, sahte sunucunun çağrıya döndürdüğü Odoo hatası
+type Fault struct {
+	Name    string
+	Message string
+}
+
+func (f *Fault) Error() string {
+	return fmt.Sprintf("%s: %s", f.Name, f.Message)
+}
+
+// Call, sunucuya yapılan bir execute_kw çağrısının kaydı
+type Call struct {
+	UID    int64
+	Model  string
+	Method string
+	Args   []interface{}
+	Kwargs map[string]interface{}
+}
+
+// Server, httptest tabanlı sahte Odoo sunucusu
+type Server struct {
+	URL string
+
+	srv      *httptest.Server
+	mu       sync.Mutex
+	store    *store
+	calls    []Call
+	sessions map[string]int64
+}
+
+// Fixture dizinindeki kayıtlarla yeni bir sunucu başlat
+func NewServer(fixtureDir string) (*Server, error) {
+	st, err := loadStore(fixtureDir)
+	if err != nil {
+		return nil, err
+	}
+
+	s := &Server{store: st, sessions: map[string]int64{}}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/xmlrpc/2/common", s.handleXMLRPC)
+	mux.HandleFunc("/xmlrpc/2/object", s.handleXMLRPC)
+	mux.HandleFunc("/jsonrpc", s.handleJSONRPC)
+	mux.HandleFunc("/web/session/authenticate", s.handleJSONRPC)
+	mux.HandleFunc("/web/dataset/call_kw/", s.handleJSONRPC)
+	s.srv = httptest.NewServer(mux)
+	s.URL = s.srv.URL
+	return s, nil
+}
+
+// Sunucuyu kapat
+func (s *Server) Close() {
+	s.srv.Close()
+}
+
+// Sunucuya bağlanmak için yönetici bağlantı bilgileri
+func (s *Server) Config(protocol string) odoo.Config {
+	return odoo.Config{
+		BaseURL:  s.URL,
+		DB:       DB,
+		Username: Username,
+		Password: Password,
+		Protocol: protocol,
+	}
+}
+
+// Modeldeki kayıtların kopyası
+func (s *Server) Records(model string) []map[string]interface{} {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var result []map[string]interface{}
+	for _, rec := range s.store.models[model] {
+		result = append(result, project(rec, nil))
+	}
+	return result
+}
+
+// Modele doğrudan kayıt ekle ve ID'sini döndür
+func (s *Server) Insert(model string, values map[string]interface{}) int64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	rec := record{}
+	for k, v := range values {
+		rec[k] = s.store.resolve(k, normalize(v))
+	}
+	return s.store.insert(model, rec)
+}
+
+// Yapılan execute_kw çağrılarının kopyası
+func (s *Server) Calls() []Call {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]Call(nil), s.calls...)
+}
+
+func (s *Server) handleXMLRPC(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	method, params, err := parseMethodCall(body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/xml")
+	result, err := s.dispatch(strings.TrimPrefix(r.URL.Path, "/xmlrpc/2/"), method, params)
+	if err != nil {
+		w.Write(encodeFault(1, err.Error()))
+		return
+	}
+	w.Write(encodeResponse(result))
+}
+
+func (s *Server) handleJSONRPC(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		ID     interface{}            `json:"id"`
+		Params map[string]interface{} `json:"params"`
+	}
+	dec := json.NewDecoder(r.Body)
+	dec.UseNumber()
+	if err := dec.Decode(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	params := normalize(req.Params).(map[string]interface{})
+
+	var result interface{}
+	var err error
+	switch {
+	case r.URL.Path == "/jsonrpc":
+		service, _ := params["service"].(string)
+		method, _ := params["method"].(string)
+		args, _ := params["args"].([]interface{})
+		result, err = s.dispatch(service, method, args)
+
+	case r.URL.Path == "/web/session/authenticate":
+		var uid interface{}
+		uid, err = s.authenticate([]interface{}{params["db"], params["login"], params["password"]})
+		if err == nil && uid != false {
+			sid := fmt.Sprintf("session-%d", uid)
+			s.mu.Lock()
+			s.sessions[sid] = uid.(int64)
+			s.mu.Unlock()
+			http.SetCookie(w, &http.Cookie{Name: "session_id", Value: sid, Path: "/"})
+			result = map[string]interface{}{"uid": uid}
+		} else if err == nil {
+			err = &Fault{Name: "odoo.exceptions.AccessDenied", Message: "Access Denied"}
+		}
+
+	default:
+		cookie, cerr := r.Cookie("session_id")
+		s.mu.Lock()
+		uid, ok := int64(0), false
+		if cerr == nil {
+			uid, ok = s.sessions[cookie.Value]
+		}
+		s.mu.Unlock()
+		if !ok {
+			err = &Fault{Name: "odoo.http.SessionExpiredException", Message: "Session expired"}
+			break
+		}
+		model, _ := params["model"].(string)
+		method, _ := params["method"].(string)
+		args, _ := params["args"].([]interface{})
+		kwargs, _ := params["kwargs"].(map[string]interface{})
+		result, err = s.execute(uid, model, method, args, kwargs)
+	}
+
+	resp := map[string]interface{}{"jsonrpc": "2.0", "id": req.ID}
+	if err != nil {
+		fault, ok := err.(*Fault)
+		if !ok {
+			fault = &Fault{Name: "builtins.Exception", Message: err.Error()}
+		}
+		resp["error"] = map[string]interface{}{
+			"code":    200,
+			"message": "Odoo Server Error",
+			"data":    map[string]interface{}{"name": fault.Name, "message": fault.Message},
+		}
+	} else {
+		if result == nil {
+			result = false
+		}
+		resp["result"] = result
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(resp)
+}
+
+// common/object servis çağrılarını yönlendir
+func (s *Server) dispatch(service, method string, params []interface{}) (interface{}, error) {
+	switch {
+	case service == "common" && method == "authenticate":
+		return s.authenticate(params)
+	case service == "common" && method == "version":
+		return map[string]interface{}{"server_version": "17.0"}, nil
+	case service == "object" && method == "execute_kw":
+		if len(params) < 6 {
+			return nil, fmt.Errorf("execute_kw: eksik parametre")
+		}
+		uid, err := s.login(params[0], params[1], params[2])
+		if err != nil {
+			return nil, err
+		}
+		model, _ := params[3].(string)
+		method, _ := params[4].(string)
+		args, _ := params[5].([]interface{})
+		var kwargs map[string]interface{}
+		if len(params) > 6 {
+			kwargs, _ = params[6].(map[string]interface{})
+		}
+		return s.execute(uid, model, method, args, kwargs)
+	}
+	return nil, fmt.Errorf("bilinmeyen çağrı: %s.%s", service, method)
+}
+
+// authenticate(db, login, password, {}) — başarısızlıkta false döner
+func (s *Server) authenticate(params []interface{}) (interface{}, error) {
+	if len(params) < 3 {
+		return nil, fmt.Errorf("authenticate: eksik parametre")
+	}
+	if params[0] != DB {
+		return nil, &Fault{Name: "odoo.exceptions.AccessError", Message: fmt.Sprintf("database %v does not exist", params[0])}
+	}
+	login, _ := params[1].(string)
+	password, _ := params[2].(string)
+	if uid := s.uidFor(login, password); uid != 0 {
+		return uid, nil
+	}
+	return false, nil
+}
+
+// Kullanıcı adı ve şifreyi (ya da API anahtarını) doğrula. Yönetici her
+// zaman kabul edilir; diğer kullanıcılar res.users fixture'ından okunur.
+func (s *Server) uidFor(login, password string) int64 {
+	if login == Username && password == Password {
+		return AdminUID
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, user := range s.store.models["res.users"] {
+		if user["login"] == login && user["password"] == password {
+			id, _ := toInt(user["id"])
+			return id
+		}
+	}
+	return 0
+}
+
+// execute_kw içindeki uid/şifre çiftini doğrula
+func (s *Server) login(db, uid, password interface{}) (int64, error) {
+	id, _ := toInt(uid)
+	pw, _ := password.(string)
+	if db != DB {
+		return 0, &Fault{Name: "odoo.exceptions.AccessError", Message: "wrong database"}
+	}
+	if id == AdminUID && pw == Password {
+		return id, nil
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, user := range s.store.models["res.users"] {
+		if user["id"] == id && user["password"] == pw {
+			return id, nil
+		}
+	}
+	return 0, &Fault{Name: "odoo.exceptions.AccessDenied", Message: "Access Denied"}
+}
+
+// Model metodunu çalıştır
+func (s *Server) execute(uid int64, model, method string, args []interface{}, kwargs map[string]interface{}) (interface{}, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.calls = append(s.calls, Call{UID: uid, Model: model, Method: method, Args: args, Kwargs: kwargs})
+
+	arg := func(i int) interface{} {
+		if i < len(args) {
+			return args[i]
+		}
+		return nil
+	}
+
+	switch method {
+	case "search_read", "search", "search_count":
+		domain, _ := arg(0).([]interface{})
+		records, err := s.store.search(model, domain)
+		if err != nil {
+			return nil, err
+		}
+		if method == "search_count" {
+			return int64(len(records)), nil
+		}
+
+		order, _ := kwargs["order"].(string)
+		sortRecords(records, order)
+		offset, _ := toInt(kwargs["offset"])
+		limit, _ := toInt(kwargs["limit"])
+		if offset > int64(len(records)) {
+			offset = int64(len(records))
+		}
+		records = records[offset:]
+		if limit > 0 && limit < int64(len(records)) {
+			records = records[:limit]
+		}
+
+		if method == "search" {
+			ids := make([]interface{}, 0, len(records))
+			for _, rec := range records {
+				ids = append(ids, rec["id"])
+			}
+			return ids, nil
+		}
+		return s.read(records, kwargs, arg(1)), nil
+
+	case "read":
+		var records []record
+		for _, id := range ids(arg(0)) {
+			if rec := s.store.find(model, id); rec != nil {
+				records = append(records, rec)
+			}
+		}
+		return s.read(records, kwargs, arg(1)), nil
+
+	case "fields_get":
+		return s.store.fields(model), nil
+
+	case "create":
+		if list, ok := arg(0).([]interface{}); ok {
+			created := make([]interface{}, 0, len(list))
+			for _, item := range list {
+				values, _ := item.(map[string]interface{})
+				created = append(created, s.create(uid, model, values))
+			}
+			return created, nil
+		}
+		values, _ := arg(0).(map[string]interface{})
+		return s.create(uid, model, values), nil
+
+	case "write":
+		values, _ := arg(1).(map[string]interface{})
+		for _, id := range ids(arg(0)) {
+			rec := s.store.find(model, id)
+			if rec == nil {
+				return nil, &Fault{Name: "odoo.exceptions.MissingError", Message: fmt.Sprintf("%s(%d) does not exist", model, id)}
+			}
+			for k, v := range values {
+				rec[k] = s.store.resolve(k, v)
+			}
+		}
+		return true, nil
+
+	case "unlink":
+		remove := map[int64]bool{}
+		for _, id := range ids(arg(0)) {
+			remove[id] = true
+		}
+		kept := s.store.models[model][:0]
+		for _, rec := range s.store.models[model] {
+			id, _ := toInt(rec["id"])
+			if !remove[id] {
+				kept = append(kept, rec)
+			}
+		}
+		s.store.models[model] = kept
+		return true, nil
+	}
+
+	return nil, &Fault{Name: "builtins.AttributeError", Message: fmt.Sprintf("'%s' object has no attribute '%s'", model, method)}
+}
+
+func (s *Server) create(uid int64, model string, values map[string]interface{}) int64 {
+	rec := record{}
+	for k, v := range values {
+		rec[k] = s.store.resolve(k, v)
+	}
+	delete(rec, "id")
+
+	// Odoo, çalışan belirtilmeyen zaman kayıtlarını oturum sahibine yazar
+	if _, ok := rec["employee_id"]; !ok && model == "account.analytic.line" {
+		rec["employee_id"] = false
+		for _, emp := range s.store.models["hr.employee"] {
+			if equal(emp["user_id"], uid) {
+				rec["employee_id"] = []interface{}{emp["id"], emp["name"]}
+				break
+			}
+		}
+	}
+	return s.store.insert(model, rec)
+}
+
+func (s *Server) read(records []record, kwargs map[string]interface{}, positional interface{}) []interface{} {
+	fields := stringList(kwargs["fields"])
+	if len(fields) == 0 {
+		fields = stringList(positional)
+	}
+	result := make([]interface{}, 0, len(records))
+	for _, rec := range records {
+		result = append(result, project(rec, fields))
+	}
+	return result
+}
+
+func ids(v interface{}) []int64 {
+	if id, ok := toInt(v); ok {
+		if _, isList := v.([]interface{}); !isList {
+			return []int64{id}
+		}
+	}
+	list, _ := v.([]interface{})
+	result := make([]int64, 0, len(list))
+	for _, item := range list {
+		if id, ok := toInt(item); ok {
+			result = append(result, id)
+		}
+	}
+	return result
+}
+
+func stringList(v interface{}) []string {
+	list, _ := v.([]interface{})
+	result := make([]string, 0, len(list))
+	for _, item := range list {
+		if s, ok := item.(string); ok {
+			result = append(result, s)
+		}
+	}
+	return result
+}
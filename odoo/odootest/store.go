@@ -0,0 +1,266 @@
+package odootest
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+type record = map[string]interface{}
+
+// Many2one alanlarının işaret ettiği modeller. create/write sırasında
+// gönderilen ID'ler [id, "ad"] çiftine çevrilir.
+var relations = map[string]string{
+	"employee_id":   "hr.employee",
+	"project_id":    "project.project",
+	"task_id":       "project.task",
+	"department_id": "hr.department",
+	"user_id":       "res.users",
+}
+
+// Many2many alanlarının işaret ettiği modeller; değerler ID listesi olarak tutulur
+var many2many = map[string]string{
+	"category_ids": "hr.employee.category",
+}
+
+// Fixture'larda bulunmasa da modelde tanımlı sayılan alanlar (fields_get)
+var schema = map[string][]string{
+	"project.project":       {"active", "allow_timesheets"},
+	"project.task":          {"active", "state"},
+	"account.analytic.line": {"validated", "timesheet_invoice_id"},
+}
+
+// Kayıtta değeri olmayan alanların varsayılanları; diğerleri false döner
+var defaults = map[string]interface{}{
+	"active":           true,
+	"allow_timesheets": true,
+}
+
+// Bellekteki model kayıtları
+type store struct {
+	models map[string][]record
+	nextID map[string]int64
+}
+
+// Dizindeki <model>.json dosyalarından kayıtları yükle
+func loadStore(dir string) (*store, error) {
+	s := &store{models: map[string][]record{}, nextID: map[string]int64{}}
+
+	files, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		return nil, err
+	}
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return nil, err
+		}
+
+		dec := json.NewDecoder(bytes.NewReader(data))
+		dec.UseNumber()
+		var raw []interface{}
+		if err := dec.Decode(&raw); err != nil {
+			return nil, fmt.Errorf("%s: %v", file, err)
+		}
+
+		model := strings.TrimSuffix(filepath.Base(file), ".json")
+		for _, item := range raw {
+			rec, ok := normalize(item).(record)
+			if !ok {
+				return nil, fmt.Errorf("%s: kayıtlar nesne olmalı", file)
+			}
+			s.insert(model, rec)
+		}
+	}
+	return s, nil
+}
+
+// json.Number değerlerini int64/float64'e çevir
+func normalize(v interface{}) interface{} {
+	switch val := v.(type) {
+	case json.Number:
+		if i, err := val.Int64(); err == nil {
+			return i
+		}
+		f, _ := val.Float64()
+		return f
+	case []interface{}:
+		for i := range val {
+			val[i] = normalize(val[i])
+		}
+		return val
+	case map[string]interface{}:
+		for k := range val {
+			val[k] = normalize(val[k])
+		}
+		return val
+	}
+	return v
+}
+
+func (s *store) insert(model string, rec record) int64 {
+	id, ok := rec["id"].(int64)
+	if !ok || id == 0 {
+		id = s.nextID[model] + 1
+		rec["id"] = id
+	}
+	if id > s.nextID[model] {
+		s.nextID[model] = id
+	}
+	s.models[model] = append(s.models[model], rec)
+	return id
+}
+
+// Modelin alanları: şemadaki ve kayıtlarda geçen alanlar
+func (s *store) fields(model string) map[string]interface{} {
+	fields := map[string]interface{}{"id": record{}}
+	for _, name := range schema[model] {
+		fields[name] = record{}
+	}
+	for _, rec := range s.models[model] {
+		for name := range rec {
+			fields[name] = record{}
+		}
+	}
+	return fields
+}
+
+func (s *store) find(model string, id int64) record {
+	for _, rec := range s.models[model] {
+		if rec["id"] == id {
+			return rec
+		}
+	}
+	return nil
+}
+
+// Many2one için gönderilen ID'yi [id, "ad"] çiftine çevir
+func (s *store) resolve(field string, value interface{}) interface{} {
+	related, ok := relations[field]
+	if !ok {
+		return value
+	}
+	id, ok := toInt(value)
+	if !ok || id == 0 {
+		return false
+	}
+	name := ""
+	if rec := s.find(related, id); rec != nil {
+		name, _ = rec["name"].(string)
+	}
+	return []interface{}{id, name}
+}
+
+func (s *store) search(model string, domain []interface{}) ([]record, error) {
+	match, err := s.compileDomain(domain)
+	if err != nil {
+		return nil, err
+	}
+	var result []record
+	for _, rec := range s.models[model] {
+		if match(rec) {
+			result = append(result, rec)
+		}
+	}
+	return result, nil
+}
+
+// Kayıtları "alan asc/desc" biçimindeki sıralama ifadesine göre sırala
+func sortRecords(records []record, order string) {
+	if order == "" {
+		order = "id"
+	}
+	keys := strings.Split(order, ",")
+	sort.SliceStable(records, func(i, j int) bool {
+		for _, key := range keys {
+			parts := strings.Fields(key)
+			if len(parts) == 0 {
+				continue
+			}
+			c := compare(records[i][parts[0]], records[j][parts[0]])
+			if c == 0 {
+				continue
+			}
+			if len(parts) > 1 && strings.EqualFold(parts[1], "desc") {
+				return c > 0
+			}
+			return c < 0
+		}
+		return false
+	})
+}
+
+// İstenen alanlarla kaydın kopyasını oluştur; olmayan alanlar false döner
+func project(rec record, fields []string) record {
+	out := record{"id": rec["id"]}
+	if len(fields) == 0 {
+		for k, v := range rec {
+			out[k] = v
+		}
+		return out
+	}
+	for _, f := range fields {
+		if v, ok := rec[f]; ok && v != nil {
+			out[f] = v
+		} else if v, ok := defaults[f]; ok {
+			out[f] = v
+		} else {
+			out[f] = false
+		}
+	}
+	return out
+}
+
+func toInt(v interface{}) (int64, bool) {
+	switch n := v.(type) {
+	case int64:
+		return n, true
+	case int:
+		return int64(n), true
+	case float64:
+		return int64(n), n == float64(int64(n))
+	case []interface{}:
+		if len(n) > 0 {
+			return toInt(n[0])
+		}
+	}
+	return 0, false
+}
+
+func toFloat(v interface{}) (float64, bool) {
+	switch n := v.(type) {
+	case int64:
+		return float64(n), true
+	case int:
+		return float64(n), true
+	case float64:
+		return n, true
+	}
+	return 0, false
+}
+
+// İki değeri karşılaştır: sayılar sayısal, diğerleri metin olarak
+func compare(a, b interface{}) int {
+	if pair, ok := a.([]interface{}); ok && len(pair) > 1 {
+		a = pair[1]
+	}
+	if pair, ok := b.([]interface{}); ok && len(pair) > 1 {
+		b = pair[1]
+	}
+	fa, okA := toFloat(a)
+	fb, okB := toFloat(b)
+	if okA && okB {
+		switch {
+		case fa < fb:
+			return -1
+		case fa > fb:
+			return 1
+		}
+		return 0
+	}
+	return strings.Compare(fmt.Sprint(a), fmt.Sprint(b))
+}
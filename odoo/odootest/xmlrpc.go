@@ -0,0 +1,184 @@
+package odootest
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// XML-RPC isteğinin ayrıştırılmış hali
+type methodCall struct {
+	MethodName string     `xml:"methodName"`
+	Params     []xmlValue `xml:"params>param>value"`
+}
+
+type xmlMember struct {
+	Name  string   `xml:"name"`
+	Value xmlValue `xml:"value"`
+}
+
+type xmlValue struct {
+	String   *string   `xml:"string"`
+	Int      *string   `xml:"int"`
+	I4       *string   `xml:"i4"`
+	I8       *string   `xml:"i8"`
+	Double   *string   `xml:"double"`
+	Boolean  *string   `xml:"boolean"`
+	DateTime *string   `xml:"dateTime.iso8601"`
+	Base64   *string   `xml:"base64"`
+	Nil      *struct{} `xml:"nil"`
+	Array    *struct {
+		Values []xmlValue `xml:"data>value"`
+	} `xml:"array"`
+	Struct *struct {
+		Members []xmlMember `xml:"member"`
+	} `xml:"struct"`
+	Text string `xml:",chardata"`
+}
+
+// XML-RPC değerini genel Go değerine çevir
+func (v xmlValue) value() (interface{}, error) {
+	switch {
+	case v.String != nil:
+		return *v.String, nil
+	case v.Int != nil, v.I4 != nil, v.I8 != nil:
+		s := v.Int
+		if s == nil {
+			s = v.I4
+		}
+		if s == nil {
+			s = v.I8
+		}
+		return strconv.ParseInt(strings.TrimSpace(*s), 10, 64)
+	case v.Double != nil:
+		return strconv.ParseFloat(strings.TrimSpace(*v.Double), 64)
+	case v.Boolean != nil:
+		return strings.TrimSpace(*v.Boolean) == "1", nil
+	case v.DateTime != nil:
+		return *v.DateTime, nil
+	case v.Base64 != nil:
+		return *v.Base64, nil
+	case v.Nil != nil:
+		return nil, nil
+	case v.Array != nil:
+		items := make([]interface{}, 0, len(v.Array.Values))
+		for _, item := range v.Array.Values {
+			val, err := item.value()
+			if err != nil {
+				return nil, err
+			}
+			items = append(items, val)
+		}
+		return items, nil
+	case v.Struct != nil:
+		m := make(map[string]interface{}, len(v.Struct.Members))
+		for _, member := range v.Struct.Members {
+			val, err := member.Value.value()
+			if err != nil {
+				return nil, err
+			}
+			m[member.Name] = val
+		}
+		return m, nil
+	}
+	// Tip etiketi olmayan değer string kabul edilir
+	return v.Text, nil
+}
+
+func parseMethodCall(body []byte) (string, []interface{}, error) {
+	var call methodCall
+	if err := xml.Unmarshal(body, &call); err != nil {
+		return "", nil, err
+	}
+	params := make([]interface{}, 0, len(call.Params))
+	for _, p := range call.Params {
+		val, err := p.value()
+		if err != nil {
+			return "", nil, err
+		}
+		params = append(params, val)
+	}
+	return call.MethodName, params, nil
+}
+
+func encodeResponse(result interface{}) []byte {
+	var b bytes.Buffer
+	b.WriteString(`<?xml version="1.0"?><methodResponse><params><param>`)
+	encodeValue(&b, result)
+	b.WriteString(`</param></params></methodResponse>`)
+	return b.Bytes()
+}
+
+func encodeFault(code int, message string) []byte {
+	var b bytes.Buffer
+	b.WriteString(`<?xml version="1.0"?><methodResponse><fault>`)
+	encodeValue(&b, map[string]interface{}{"faultCode": code, "faultString": message})
+	b.WriteString(`</fault></methodResponse>`)
+	return b.Bytes()
+}
+
+func encodeValue(b *bytes.Buffer, v interface{}) {
+	b.WriteString("<value>")
+	switch val := v.(type) {
+	case nil:
+		// Odoo boş değerleri false olarak döner
+		b.WriteString("<boolean>0</boolean>")
+	case bool:
+		if val {
+			b.WriteString("<boolean>1</boolean>")
+		} else {
+			b.WriteString("<boolean>0</boolean>")
+		}
+	case int:
+		fmt.Fprintf(b, "<int>%d</int>", val)
+	case int64:
+		fmt.Fprintf(b, "<int>%d</int>", val)
+	case float64:
+		fmt.Fprintf(b, "<double>%s</double>", strconv.FormatFloat(val, 'f', -1, 64))
+	case string:
+		b.WriteString("<string>")
+		xml.EscapeText(b, []byte(val))
+		b.WriteString("</string>")
+	case []interface{}:
+		b.WriteString("<array><data>")
+		for _, item := range val {
+			encodeValue(b, item)
+		}
+		b.WriteString("</data></array>")
+	case []int64:
+		b.WriteString("<array><data>")
+		for _, item := range val {
+			encodeValue(b, item)
+		}
+		b.WriteString("</data></array>")
+	case map[string]interface{}:
+		keys := make([]string, 0, len(val))
+		for k := range val {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		b.WriteString("<struct>")
+		for _, k := range keys {
+			b.WriteString("<member><name>")
+			xml.EscapeText(b, []byte(k))
+			b.WriteString("</name>")
+			encodeValue(b, val[k])
+			b.WriteString("</member>")
+		}
+		b.WriteString("</struct>")
+	case []map[string]interface{}:
+		b.WriteString("<array><data>")
+		for _, item := range val {
+			encodeValue(b, item)
+		}
+		b.WriteString("</data></array>")
+	default:
+		b.WriteString("<string>")
+		xml.EscapeText(b, []byte(fmt.Sprint(val)))
+		b.WriteString("</string>")
+	}
+	b.WriteString("</value>")
+}
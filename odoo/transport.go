@@ -0,0 +1,93 @@
+package odoo
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/kolo/xmlrpc"
+)
+
+// Desteklenen taşıma protokolleri
+const (
+	ProtocolXMLRPC  = "xmlrpc"  // /xmlrpc/2/common ve /xmlrpc/2/object
+	ProtocolJSONRPC = "jsonrpc" // /jsonrpc
+	ProtocolWeb     = "web"     // /web/session/authenticate ve /web/dataset/call_kw
+)
+
+// Transport, Odoo ile konuşan protokol katmanı. Yanıtlar genel değerler
+// (map, dilim, sayı, false...) olarak döner ve Client tarafından çözülür.
+type Transport interface {
+	Authenticate(db, login, password string) (int, error)
+	Execute(db string, uid int, password, model, method string, args []interface{}, kwargs map[string]interface{}) (interface{}, error)
+	Close() error
+}
+
+// Yapılandırmadaki protokole göre taşıma katmanı oluştur
+func NewTransport(cfg Config) (Transport, error) {
+	switch strings.ToLower(cfg.Protocol) {
+	case "", ProtocolXMLRPC:
+		return newXMLRPCTransport(cfg.BaseURL)
+	case ProtocolJSONRPC:
+		return newJSONRPCTransport(cfg.BaseURL), nil
+	case ProtocolWeb:
+		return newWebTransport(cfg.BaseURL)
+	}
+	return nil, fmt.Errorf("desteklenmeyen Odoo protokolü: %s", cfg.Protocol)
+}
+
+type xmlrpcTransport struct {
+	common *xmlrpc.Client
+	object *xmlrpc.Client
+}
+
+func newXMLRPCTransport(baseURL string) (*xmlrpcTransport, error) {
+	common, err := xmlrpc.NewClient(fmt.Sprintf("%s/xmlrpc/2/common", baseURL), nil)
+	if err != nil {
+		return nil, err
+	}
+	object, err := xmlrpc.NewClient(fmt.Sprintf("%s/xmlrpc/2/object", baseURL), nil)
+	if err != nil {
+		common.Close()
+		return nil, err
+	}
+	return &xmlrpcTransport{common: common, object: object}, nil
+}
+
+func (t *xmlrpcTransport) Authenticate(db, login, password string) (int, error) {
+	var reply interface{}
+	err := t.common.Call("authenticate", []interface{}{
+		db,
+		login,
+		password,
+		map[string]interface{}{},
+	}, &reply)
+	if err != nil {
+		return 0, err
+	}
+	return authenticatedUID(reply)
+}
+
+func (t *xmlrpcTransport) Execute(db string, uid int, password, model, method string, args []interface{}, kwargs map[string]interface{}) (interface{}, error) {
+	params := []interface{}{db, uid, password, model, method, args}
+	if kwargs != nil {
+		params = append(params, kwargs)
+	}
+
+	var reply interface{}
+	err := t.object.Call("execute_kw", params, &reply)
+	return reply, err
+}
+
+func (t *xmlrpcTransport) Close() error {
+	t.common.Close()
+	return t.object.Close()
+}
+
+// Hatalı kimlik bilgilerinde Odoo uid yerine false döner
+func authenticatedUID(reply interface{}) (int, error) {
+	var uid int
+	if err := decode(reply, &uid); err != nil || uid == 0 {
+		return 0, fmt.Errorf("geçersiz kullanıcı adı veya şifre")
+	}
+	return uid, nil
+}
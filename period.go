@@ -0,0 +1,204 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const dateLayout = "2006-01-02"
+
+// Rapor dönemi; başlangıç ve bitiş günleri dahildir
+type Period struct {
+	Start time.Time
+	End   time.Time
+}
+
+func (p Period) String() string {
+	return fmt.Sprintf("%s - %s", p.Start.Format(dateLayout), p.End.Format(dateLayout))
+}
+
+// JSON çıktısında dönem {"start": "YYYY-MM-DD", "end": "YYYY-MM-DD"} olarak yazılır
+func (p Period) MarshalJSON() ([]byte, error) {
+	return json.Marshal(map[string]string{
+		"start": p.Start.Format(dateLayout),
+		"end":   p.End.Format(dateLayout),
+	})
+}
+
+// Dönemin gün listesi (başlangıç ve bitiş dahil)
+func (p Period) Days() []time.Time {
+	var days []time.Time
+	for d := p.Start; !d.After(p.End); d = d.AddDate(0, 0, 1) {
+		days = append(days, d)
+	}
+	return days
+}
+
+var (
+	isoWeekPattern = regexp.MustCompile(`^(?:(\d{4})-)?[Ww](\d{1,2})$`)
+	quarterPattern = regexp.MustCompile(`^(\d{4})-[Qq]([1-4])$`)
+	monthPattern   = regexp.MustCompile(`^(\d{4})-(\d{2})$`)
+)
+
+// Adlandırılmış dönemlerin Türkçe karşılıkları
+var periodAliases = map[string]string{
+	"daily":        "today",
+	"bugun":        "today",
+	"bugün":        "today",
+	"dun":          "yesterday",
+	"dün":          "yesterday",
+	"week":         "this-week",
+	"bu-hafta":     "this-week",
+	"gecen-hafta":  "last-week",
+	"geçen-hafta":  "last-week",
+	"month":        "this-month",
+	"bu-ay":        "this-month",
+	"gecen-ay":     "last-month",
+	"geçen-ay":     "last-month",
+	"this-quarter": "quarter",
+	"ceyrek":       "quarter",
+	"çeyrek":       "quarter",
+	"gecen-ceyrek": "last-quarter",
+	"geçen-çeyrek": "last-quarter",
+}
+
+// Desteklenen dönem ifadeleri (yardım metinleri için)
+const periodHelp = "today, yesterday, this-week, last-week, this-month, last-month, quarter, last-quarter, " +
+	"YYYY-MM-DD, YYYY-MM, YYYY-Www, YYYY-Qn, YYYY-MM-DD..YYYY-MM-DD"
+
+// Dönem ifadesini tarih aralığına çevir. Boş ifade ayın başından bugüne
+// kadar olan dönemi verir. "Bu" ile başlayan dönemler bugünde biter.
+func parsePeriod(spec string, now time.Time) (Period, error) {
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	spec = strings.ToLower(strings.TrimSpace(spec))
+	if alias, ok := periodAliases[spec]; ok {
+		spec = alias
+	}
+
+	switch spec {
+	case "", "this-month":
+		return Period{monthStart(today), today}, nil
+	case "today":
+		return Period{today, today}, nil
+	case "yesterday":
+		y := today.AddDate(0, 0, -1)
+		return Period{y, y}, nil
+	case "this-week":
+		return Period{weekStart(today), today}, nil
+	case "last-week":
+		start := weekStart(today).AddDate(0, 0, -7)
+		return Period{start, start.AddDate(0, 0, 6)}, nil
+	case "last-month":
+		start := monthStart(today).AddDate(0, -1, 0)
+		return Period{start, start.AddDate(0, 1, -1)}, nil
+	case "quarter":
+		return Period{quarterStart(today), today}, nil
+	case "last-quarter":
+		start := quarterStart(today).AddDate(0, -3, 0)
+		return Period{start, start.AddDate(0, 3, -1)}, nil
+	}
+
+	// Açık aralık: YYYY-MM-DD..YYYY-MM-DD
+	if from, to, ok := strings.Cut(spec, ".."); ok {
+		return periodBetween(from, to, now)
+	}
+
+	if m := isoWeekPattern.FindStringSubmatch(spec); m != nil {
+		year := today.Year()
+		if m[1] != "" {
+			year, _ = strconv.Atoi(m[1])
+		}
+		week, _ := strconv.Atoi(m[2])
+		start, err := isoWeekStart(year, week, now.Location())
+		if err != nil {
+			return Period{}, err
+		}
+		return Period{start, start.AddDate(0, 0, 6)}, nil
+	}
+
+	if m := quarterPattern.FindStringSubmatch(spec); m != nil {
+		year, _ := strconv.Atoi(m[1])
+		q, _ := strconv.Atoi(m[2])
+		start := time.Date(year, time.Month(3*(q-1)+1), 1, 0, 0, 0, 0, now.Location())
+		return Period{start, start.AddDate(0, 3, -1)}, nil
+	}
+
+	if m := monthPattern.FindStringSubmatch(spec); m != nil {
+		start, err := time.ParseInLocation("2006-01", spec, now.Location())
+		if err != nil {
+			return Period{}, fmt.Errorf("geçersiz ay: %s", spec)
+		}
+		return Period{start, start.AddDate(0, 1, -1)}, nil
+	}
+
+	day, err := time.ParseInLocation(dateLayout, spec, now.Location())
+	if err != nil {
+		return Period{}, fmt.Errorf("geçersiz tarih filtresi %q. Desteklenen değerler: %s", spec, periodHelp)
+	}
+	return Period{day, day}, nil
+}
+
+// -from/-to bayraklarından dönem oluştur. -to verilmezse bugün kullanılır.
+func periodBetween(from, to string, now time.Time) (Period, error) {
+	start, err := time.ParseInLocation(dateLayout, strings.TrimSpace(from), now.Location())
+	if err != nil {
+		return Period{}, fmt.Errorf("geçersiz başlangıç tarihi. Doğru format: YYYY-MM-DD: %v", err)
+	}
+
+	end := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	if strings.TrimSpace(to) != "" {
+		end, err = time.ParseInLocation(dateLayout, strings.TrimSpace(to), now.Location())
+		if err != nil {
+			return Period{}, fmt.Errorf("geçersiz bitiş tarihi. Doğru format: YYYY-MM-DD: %v", err)
+		}
+	}
+
+	if end.Before(start) {
+		return Period{}, fmt.Errorf("bitiş tarihi (%s) başlangıçtan (%s) önce olamaz",
+			end.Format(dateLayout), start.Format(dateLayout))
+	}
+	return Period{start, end}, nil
+}
+
+// CLI bayraklarını tek bir döneme çevir; -from/-to, -date'ten önceliklidir
+func resolvePeriod(dateSpec, from, to string, now time.Time) (Period, error) {
+	if from != "" {
+		if dateSpec != "" {
+			return Period{}, fmt.Errorf("-date ile -from/-to birlikte kullanılamaz")
+		}
+		return periodBetween(from, to, now)
+	}
+	if to != "" {
+		return Period{}, fmt.Errorf("-to için -from da belirtilmeli")
+	}
+	return parsePeriod(dateSpec, now)
+}
+
+func monthStart(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, t.Location())
+}
+
+// Haftanın başlangıcı pazartesidir
+func weekStart(t time.Time) time.Time {
+	offset := (int(t.Weekday()) + 6) % 7
+	return t.AddDate(0, 0, -offset)
+}
+
+func quarterStart(t time.Time) time.Time {
+	month := time.Month(3*((int(t.Month())-1)/3) + 1)
+	return time.Date(t.Year(), month, 1, 0, 0, 0, 0, t.Location())
+}
+
+// ISO 8601 haftasının pazartesi gününü bul. 1. hafta, 4 Ocak'ı içeren haftadır.
+func isoWeekStart(year, week int, loc *time.Location) (time.Time, error) {
+	jan4 := time.Date(year, time.January, 4, 0, 0, 0, 0, loc)
+	start := weekStart(jan4).AddDate(0, 0, 7*(week-1))
+	if y, w := start.ISOWeek(); week < 1 || y != year || w != week {
+		return time.Time{}, fmt.Errorf("%d yılında %d. hafta yok", year, week)
+	}
+	return start, nil
+}
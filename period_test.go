@@ -0,0 +1,66 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParsePeriod(t *testing.T) {
+	// 2025-02-12 bir çarşamba
+	now := time.Date(2025, 2, 12, 15, 30, 0, 0, time.UTC)
+
+	tests := []struct {
+		spec       string
+		start, end string
+	}{
+		{"", "2025-02-01", "2025-02-12"},
+		{"daily", "2025-02-12", "2025-02-12"},
+		{"yesterday", "2025-02-11", "2025-02-11"},
+		{"dün", "2025-02-11", "2025-02-11"},
+		{"this-week", "2025-02-10", "2025-02-12"},
+		{"last-week", "2025-02-03", "2025-02-09"},
+		{"last-month", "2025-01-01", "2025-01-31"},
+		{"quarter", "2025-01-01", "2025-02-12"},
+		{"last-quarter", "2024-10-01", "2024-12-31"},
+		{"2025-02-05", "2025-02-05", "2025-02-05"},
+		{"2024-02", "2024-02-01", "2024-02-29"},
+		{"2025-W01", "2024-12-30", "2025-01-05"},
+		{"W06", "2025-02-03", "2025-02-09"},
+		{"2024-Q3", "2024-07-01", "2024-09-30"},
+		{"2025-01-20..2025-01-24", "2025-01-20", "2025-01-24"},
+	}
+	for _, tt := range tests {
+		p, err := parsePeriod(tt.spec, now)
+		if err != nil {
+			t.Errorf("parsePeriod(%q): %v", tt.spec, err)
+			continue
+		}
+		if got := p.Start.Format(dateLayout); got != tt.start {
+			t.Errorf("parsePeriod(%q) başlangıç = %s, beklenen %s", tt.spec, got, tt.start)
+		}
+		if got := p.End.Format(dateLayout); got != tt.end {
+			t.Errorf("parsePeriod(%q) bitiş = %s, beklenen %s", tt.spec, got, tt.end)
+		}
+	}
+
+	for _, spec := range []string{"2025-W54", "2025-02-30", "geçen-yıl", "2025-02-10..2025-02-01"} {
+		if _, err := parsePeriod(spec, now); err == nil {
+			t.Errorf("parsePeriod(%q) hata döndürmedi", spec)
+		}
+	}
+}
+
+func TestResolvePeriod(t *testing.T) {
+	now := time.Date(2025, 2, 12, 9, 0, 0, 0, time.UTC)
+
+	p, err := resolvePeriod("", "2025-02-01", "", now)
+	if err != nil || p.String() != "2025-02-01 - 2025-02-12" {
+		t.Errorf("-from tek başına: %v, %v", p, err)
+	}
+	if _, err := resolvePeriod("daily", "2025-02-01", "", now); err == nil {
+		t.Error("-date ile -from birlikte kabul edildi")
+	}
+	if _, err := resolvePeriod("", "", "2025-02-05", now); err == nil {
+		t.Error("-from olmadan -to kabul edildi")
+	}
+}
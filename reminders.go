@@ -0,0 +1,287 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"odoo-efor-tracker/odoo"
+)
+
+// Hatırlatma kanalları
+const (
+	ReminderTelegram = "telegram"
+	ReminderEmail    = "email"
+	ReminderBoth     = "both"
+)
+
+// Ardışık eksik gün sayılırken geriye bakılan gün sayısı
+const reminderLookback = 31
+
+// Eksik kayıt hatırlatmalarının ayarları (teams.yaml "reminders")
+type ReminderConfig struct {
+	Time          string `yaml:"time"`           // "17:30"; boşsa hatırlatma kapalı
+	Day           string `yaml:"day"`            // today (varsayılan) ya da yesterday
+	Channel       string `yaml:"channel"`        // telegram (varsayılan), email ya da both
+	EscalateAfter int    `yaml:"escalate_after"` // Ekip liderine bildirilecek ardışık eksik gün; 0 kapalı
+
+	// Çalışan adı ya da hr.employee ID'si → Telegram kullanıcı ID'si
+	Telegram map[string]int64 `yaml:"telegram"`
+}
+
+// Ekip liderinin iletişim bilgileri
+type Contact struct {
+	Name     string `yaml:"name"`
+	Email    string `yaml:"email"`
+	Telegram int64  `yaml:"telegram"`
+}
+
+// Ayarları doğrula ve cron ifadesini döndür
+func (c *ReminderConfig) cronSpec() (string, error) {
+	t, err := time.Parse("15:04", c.Time)
+	if err != nil {
+		return "", fmt.Errorf("geçersiz hatırlatma saati %q (SS:DD)", c.Time)
+	}
+	switch c.Day {
+	case "", "today", "yesterday":
+	default:
+		return "", fmt.Errorf("geçersiz hatırlatma günü %q (today, yesterday)", c.Day)
+	}
+	switch c.Channel {
+	case "", ReminderTelegram, ReminderEmail, ReminderBoth:
+	default:
+		return "", fmt.Errorf("geçersiz hatırlatma kanalı %q (telegram, email, both)", c.Channel)
+	}
+	return fmt.Sprintf("%d %d * * *", t.Minute(), t.Hour()), nil
+}
+
+// Hatırlatılacak gün: bugün ya da dün
+func (c *ReminderConfig) target(now time.Time) time.Time {
+	day := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	if c.Day == "yesterday" {
+		day = day.AddDate(0, 0, -1)
+	}
+	return day
+}
+
+// Çalışanın Telegram kullanıcı ID'si (ad ya da ID ile)
+func (c *ReminderConfig) TelegramID(emp odoo.Employee) (int64, bool) {
+	for key, id := range c.Telegram {
+		key = strings.TrimSpace(key)
+		if n, err := strconv.ParseInt(key, 10, 64); err == nil && n == emp.ID {
+			return id, true
+		}
+		if strings.EqualFold(key, emp.Name) {
+			return id, true
+		}
+	}
+	return 0, false
+}
+
+// Her ekip için hatırlatma gününde beklenen saatin altında kalan üyelere
+// Telegram ya da e-posta ile hatırlatma gönder. Ardışık eksik gün sayısı
+// EscalateAfter'a ulaşan üyeler ekip liderine bildirilir.
+func runReminders(client odoo.API, now time.Time) error {
+	cfg, err := loadRosterConfig()
+	if err != nil {
+		return err
+	}
+	rc := cfg.Reminders
+	registry, err := loadUserRegistry()
+	if err != nil {
+		return err
+	}
+	day := rc.target(now)
+	period := Period{Start: day.AddDate(0, 0, -reminderLookback), End: day}
+
+	teams := cfg.Teams
+	if len(teams) == 0 {
+		// Ekip yoksa yalnızca dönemde kaydı olan çalışanlar kontrol edilebilir
+		teams = []TeamConfig{{}}
+	}
+
+	reminded := make(map[int64]bool)
+	var failed []string
+	for _, team := range teams {
+		r, err := loadReport(client, period, "", team.Name, now)
+		if err != nil {
+			return err
+		}
+
+		employees, err := scopeEmployees(client, r)
+		if err != nil {
+			return err
+		}
+		var escalations []string
+		for _, emp := range employees {
+			missing, streak := missingStreak(r, emp.ID, day)
+			if missing == nil {
+				continue
+			}
+			if !reminded[emp.ID] {
+				reminded[emp.ID] = true
+				if err := remindEmployee(&rc, registry, emp, *missing, streak); err != nil {
+					log.Printf("%s için hatırlatma gönderilemedi: %v", emp.Name, err)
+					failed = append(failed, emp.Name)
+				}
+			}
+			if rc.EscalateAfter > 0 && streak >= rc.EscalateAfter {
+				escalations = append(escalations, fmt.Sprintf("• %s: %d iş günü", emp.Name, streak))
+			}
+		}
+
+		if len(escalations) > 0 {
+			if err := escalate(&rc, registry, &team, day, escalations); err != nil {
+				log.Printf("%s ekibinin liderine bildirim gönderilemedi: %v", team.Name, err)
+				failed = append(failed, team.Name+" lideri")
+			}
+		}
+	}
+
+	if len(failed) > 0 {
+		return fmt.Errorf("%d hatırlatma gönderilemedi: %s", len(failed), strings.Join(failed, ", "))
+	}
+	return nil
+}
+
+// Çalışanın gün için eksik kaydı ve o güne kadar ardışık eksik iş günü
+// sayısı. Gün eksik değilse nil döner.
+func missingStreak(r *Report, employeeID int64, day time.Time) (*MissingDay, int) {
+	missing := make(map[string]MissingDay)
+	for _, m := range r.Missing {
+		if m.EmployeeID == employeeID {
+			missing[m.Date] = m
+		}
+	}
+
+	target, ok := missing[day.Format(dateLayout)]
+	if !ok {
+		return nil, 0
+	}
+
+	streak := 0
+	for d := day; !d.Before(r.Period.Start); d = d.AddDate(0, 0, -1) {
+		if r.calendar != nil && r.calendar.Expected(employeeID, d) == 0 {
+			continue
+		}
+		if _, ok := missing[d.Format(dateLayout)]; !ok {
+			break
+		}
+		streak++
+	}
+	return &target, streak
+}
+
+// Çalışana eksik gününü hatırlat
+func remindEmployee(rc *ReminderConfig, registry *UserRegistry, emp odoo.Employee, day MissingDay, streak int) error {
+	text := fmt.Sprintf("⏰ Merhaba %s, %s için zaman kaydın eksik görünüyor (%s).", emp.Name, day.Date, missingText(day))
+	if streak > 1 {
+		text += fmt.Sprintf(" Son %d iş günüdür eksik kayıt var.", streak)
+	}
+	text += " Lütfen Odoo'da kayıtlarını tamamla."
+
+	channel := rc.Channel
+	if channel == "" {
+		channel = ReminderTelegram
+	}
+
+	sent := false
+	if channel == ReminderTelegram || channel == ReminderBoth {
+		if id, ok := telegramIDFor(rc, registry, emp); ok {
+			if err := deliverTelegram(id, text); err != nil {
+				return err
+			}
+			sent = true
+		} else if channel == ReminderTelegram {
+			return fmt.Errorf("Telegram kullanıcı ID'si tanımlı değil")
+		}
+	}
+	if channel == ReminderEmail || channel == ReminderBoth {
+		if emp.WorkEmail == "" {
+			if !sent {
+				return fmt.Errorf("iş e-postası tanımlı değil")
+			}
+			return nil
+		}
+		email := Email{
+			Recipients: Recipients{To: []string{emp.WorkEmail}},
+			Subject:    fmt.Sprintf("Zaman Kaydı Hatırlatması - %s", day.Date),
+			Text:       text,
+		}
+		if err := deliverEmail(email); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Çalışanın Telegram kullanıcı ID'si; hatırlatma ayarlarında yoksa bot
+// kullanıcı kaydından bulunur
+func telegramIDFor(rc *ReminderConfig, registry *UserRegistry, emp odoo.Employee) (int64, bool) {
+	if id, ok := rc.TelegramID(emp); ok {
+		return id, true
+	}
+	if u, ok := registry.ByEmployee(emp); ok {
+		return u.Telegram, true
+	}
+	return 0, false
+}
+
+// Ardışık eksik gün sınırını aşan üyeleri ekip liderine bildir. Ekipte
+// lider tanımlı değilse bot kaydında bu ekibe lider olarak atanan
+// kullanıcılara Telegram'dan gönderilir.
+func escalate(rc *ReminderConfig, registry *UserRegistry, team *TeamConfig, day time.Time, lines []string) error {
+	name := team.Name
+	if name == "" {
+		name = "ekip"
+	}
+	lead := team.Lead
+	var leads []int64
+	if lead.Telegram != 0 {
+		leads = append(leads, lead.Telegram)
+	} else {
+		for _, u := range registry.List() {
+			if u.Role == RoleLead && containsFold(u.Teams, team.Name) {
+				leads = append(leads, u.Telegram)
+			}
+		}
+	}
+	if len(leads) == 0 && lead.Email == "" {
+		log.Printf("Uyarı: %s ekibi için lider tanımlı değil, ardışık eksik günler bildirilmedi", name)
+		return nil
+	}
+
+	sort.Strings(lines)
+	text := fmt.Sprintf("⚠️ %s ekibinde %d ya da daha fazla iş günüdür eksik kayıt giren üyeler (%s itibarıyla):\n\n%s",
+		name, rc.EscalateAfter, day.Format(dateLayout), strings.Join(lines, "\n"))
+
+	for _, id := range leads {
+		if err := deliverTelegram(id, text); err != nil {
+			return err
+		}
+	}
+	if lead.Email != "" {
+		email := Email{
+			Recipients: Recipients{To: []string{lead.Email}},
+			Subject:    fmt.Sprintf("Eksik Zaman Kayıtları - %s", name),
+			Text:       text,
+		}
+		if err := deliverEmail(email); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Listede büyük/küçük harf duyarsız eşleşme var mı
+func containsFold(list []string, s string) bool {
+	for _, v := range list {
+		if strings.EqualFold(v, s) {
+			return true
+		}
+	}
+	return false
+}
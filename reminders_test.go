@@ -0,0 +1,108 @@
+package main
+
+import (
+	"sort"
+	"strings"
+	"testing"
+	"time"
+)
+
+type telegramMessage struct {
+	chat int64
+	text string
+}
+
+func captureTelegram(t *testing.T) *[]telegramMessage {
+	t.Helper()
+	var sent []telegramMessage
+	deliverTelegram = func(chat int64, text string) error {
+		sent = append(sent, telegramMessage{chat, text})
+		return nil
+	}
+	t.Cleanup(func() { deliverTelegram = sendTelegramTo })
+	return &sent
+}
+
+func TestRunReminders(t *testing.T) {
+	newFakeOdoo(t)
+	emails := captureEmails(t)
+	messages := captureTelegram(t)
+	client, err := authenticateOdoo()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// 2025-02-06 perşembe: Ayşegül izinli; Fatih, Osman ve Harici eksik
+	now := time.Date(2025, 2, 6, 17, 30, 0, 0, time.Local)
+	if err := runReminders(client, now); err != nil {
+		t.Fatal(err)
+	}
+
+	var emailTo []string
+	for _, e := range *emails {
+		emailTo = append(emailTo, e.To...)
+	}
+	sort.Strings(emailTo)
+	// Osman iki ekipte olsa da bir kez hatırlatılır; lider bildirimi de
+	// Osman'ın adresine gider
+	want := []string{"danisman@example.com", "fatih@example.com", "osman@example.com", "osman@example.com"}
+	if strings.Join(emailTo, ",") != strings.Join(want, ",") {
+		t.Errorf("e-posta alıcıları = %v, beklenen %v", emailTo, want)
+	}
+
+	dm := make(map[int64][]string)
+	for _, m := range *messages {
+		dm[m.chat] = append(dm[m.chat], m.text)
+	}
+	if len(dm[3003]) != 1 || !strings.Contains(dm[3003][0], "2025-02-06 için") {
+		t.Errorf("Fatih'e hatırlatma gönderilmedi: %v", dm[3003])
+	}
+	// Osman: kendi hatırlatması (2 gün) ve lider olarak Fatih bildirimi
+	if len(dm[1001]) != 2 {
+		t.Fatalf("Osman'a giden mesajlar: %v", dm[1001])
+	}
+	if !strings.Contains(dm[1001][0], "Son 2 iş günüdür") {
+		t.Errorf("Osman'ın hatırlatması: %q", dm[1001][0])
+	}
+	escalation := dm[1001][1]
+	if !strings.Contains(escalation, "Fatih Delice") || strings.Contains(escalation, "Osman") {
+		t.Errorf("lider bildirimi: %q", escalation)
+	}
+}
+
+func TestRunRemindersSkipsWeekend(t *testing.T) {
+	newFakeOdoo(t)
+	captureEmails(t)
+	messages := captureTelegram(t)
+	client, err := authenticateOdoo()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// 2025-02-08 cumartesi; hafta sonu eksik sayılmaz
+	if err := runReminders(client, time.Date(2025, 2, 8, 17, 30, 0, 0, time.Local)); err != nil {
+		t.Fatal(err)
+	}
+	if len(*messages) != 0 {
+		t.Errorf("hafta sonu hatırlatma gönderildi: %v", *messages)
+	}
+}
+
+func TestReminderConfig(t *testing.T) {
+	rc := ReminderConfig{Time: "17:30", Day: "yesterday"}
+	if spec, err := rc.cronSpec(); err != nil || spec != "30 17 * * *" {
+		t.Errorf("cronSpec = %q, %v", spec, err)
+	}
+	if got := rc.target(time.Date(2025, 2, 5, 9, 0, 0, 0, time.Local)); got.Format(dateLayout) != "2025-02-04" {
+		t.Errorf("hedef gün = %s", got.Format(dateLayout))
+	}
+	for _, bad := range []ReminderConfig{
+		{Time: "5pm"},
+		{Time: "17:30", Day: "tomorrow"},
+		{Time: "17:30", Channel: "sms"},
+	} {
+		if _, err := bad.cronSpec(); err == nil {
+			t.Errorf("%+v için hata bekleniyordu", bad)
+		}
+	}
+}
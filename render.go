@@ -0,0 +1,203 @@
+package main
+
+import (
+	"fmt"
+	"html/template"
+	"io"
+)
+
+// Raporu düz metin olarak yaz (results/*.txt ve konsol çıktısı)
+func renderText(w io.Writer, r *Report) {
+	fmt.Fprintf(w, "Tarih Aralığı: %s\n", r.Period)
+
+	for _, entry := range r.Entries {
+		fmt.Fprintf(w, "Tarih: %s\nÇalışan: %s\nProje: %s\nAçıklama: %s\nSaat: %.2f\n\n",
+			entry.Date, entry.Employee.Name, entry.Project.Name, entry.Description, entry.UnitAmount)
+	}
+
+	// Özet istatistikleri yazdır
+	fmt.Fprint(w, "\n=== Özet İstatistikler ===\n\n")
+	fmt.Fprintf(w, "Toplam Çalışma Saati: %.2f\n\n", r.TotalHours)
+
+	if r.Employee != "" {
+		// Tek bir çalışan için filtreleme yapıldığında
+		fmt.Fprintf(w, "Çalışan: %s\n\n", r.Employee)
+	} else {
+		// Tüm çalışanların toplam saatlerini listele
+		fmt.Fprintln(w, "Çalışan Bazında Toplam Saatler:")
+		for _, emp := range r.Employees {
+			fmt.Fprintf(w, "%s: %.2f saat\n", emp.Name, emp.Hours)
+		}
+	}
+
+	fmt.Fprintln(w, "\nProje Bazında Saatler:")
+	for _, proj := range r.Projects {
+		fmt.Fprintf(w, "%s: %.2f saat\n", proj.Name, proj.Hours)
+	}
+
+	fmt.Fprintln(w, "\nGünlük Çalışma Saatleri:")
+	for _, day := range r.Days {
+		fmt.Fprintf(w, "\n%s:\n", day.Date)
+		for _, emp := range day.Employees {
+			fmt.Fprintf(w, "  %s: %.2f saat ====== %s\n", emp.Employee, emp.Hours, emp.Status)
+		}
+	}
+	for _, emp := range r.Roster {
+		fmt.Fprintf(w, "%s: %.2f saat\n", emp.Name, emp.Hours)
+	}
+
+	if len(r.Missing) > 0 {
+		fmt.Fprintln(w, "\nEksik Girilen İş Günleri:")
+		for _, day := range r.Missing {
+			fmt.Fprintf(w, "  %s - %s: %s\n", day.Employee, day.Date, missingText(day))
+		}
+	}
+
+	if len(r.Warnings) > 0 {
+		fmt.Fprintln(w, "\nUyarılar:")
+		for _, warning := range r.Warnings {
+			fmt.Fprintf(w, "⚠️ %s\n", warning)
+		}
+	}
+}
+
+// Telegram için Markdown özet oluştur
+func renderSummary(r *Report) string {
+	summary := "📊 **Günlük Çalışma Raporu Özeti**\n\n"
+	summary += fmt.Sprintf("Tarih Aralığı: %s\n\n", r.Period)
+	summary += fmt.Sprintf("Toplam Çalışma Saati: %.2f\n\n", r.TotalHours)
+
+	// Çalışan bazında saatler
+	summary += "👥 **Çalışan Bazında Saatler:**\n"
+	if len(r.Employees) == 0 {
+		summary += "Çalışan bilgisi bulunamadı.\n"
+	}
+	for _, emp := range r.Employees {
+		summary += fmt.Sprintf("%s: %.2f saat\n", emp.Name, emp.Hours)
+	}
+
+	// Proje bazında saatler
+	summary += "\n🏢 **Proje Bazında Saatler:**\n"
+	if len(r.Projects) == 0 {
+		summary += "Proje bilgisi bulunamadı.\n"
+	}
+	for _, proj := range r.Projects {
+		summary += fmt.Sprintf("%s: %.2f saat\n", proj.Name, proj.Hours)
+	}
+
+	// Eksik gün sayısı, çalışan bazında
+	if len(r.Missing) > 0 {
+		summary += "\n⚠️ **Eksik Girilen İş Günleri:**\n"
+		var names []string
+		counts := make(map[string]int)
+		for _, day := range r.Missing {
+			if counts[day.Employee] == 0 {
+				names = append(names, day.Employee)
+			}
+			counts[day.Employee]++
+		}
+		for _, name := range names {
+			summary += fmt.Sprintf("%s: %d gün\n", name, counts[name])
+		}
+	}
+
+	summary += "\nDetaylı rapor için uygulamayı çalıştırın."
+	return summary
+}
+
+// Eksik günün açıklaması
+func missingText(day MissingDay) string {
+	if day.Empty() {
+		return "kayıt yok"
+	}
+	return fmt.Sprintf("%.2f / %.2f saat", day.Hours, day.Expected)
+}
+
+// E-posta için HTML rapor şablonu. E-posta istemcileri <style> etiketlerini
+// çoğunlukla yok saydığından stiller satır içi yazılır.
+var htmlReport = template.Must(template.New("report").Funcs(template.FuncMap{
+	"hours":   func(h float64) string { return fmt.Sprintf("%.2f", h) },
+	"short":   func(status string) bool { return status == StatusShort },
+	"missing": missingText,
+}).Parse(`<!DOCTYPE html>
+<html lang="tr">
+<head><meta charset="UTF-8"><title>Çalışma Raporu {{.Period}}</title></head>
+<body style="font-family: Arial, sans-serif; font-size: 14px; color: #222;">
+<h2>📊 Çalışma Raporu</h2>
+<p>
+  <strong>Tarih Aralığı:</strong> {{.Period}}<br>
+  {{- if .Team}}<strong>Ekip:</strong> {{.Team}}<br>{{end}}
+  {{- if .Employee}}<strong>Çalışan:</strong> {{.Employee}}<br>{{end}}
+  <strong>Toplam Çalışma Saati:</strong> {{hours .TotalHours}}
+</p>
+{{- if .Warnings}}
+<div style="background: #fde2e2; color: #b00020; padding: 8px 12px; border-radius: 4px;">
+  {{- range .Warnings}}
+  <p style="margin: 4px 0;">⚠️ {{.}}</p>
+  {{- end}}
+</div>
+{{- end}}
+
+<h3>👥 Çalışan Bazında Saatler</h3>
+{{- if .Roster}}
+<table cellpadding="6" cellspacing="0" border="1" style="border-collapse: collapse; border-color: #ccc;">
+  <tr style="background: #f0f0f0;"><th align="left">Çalışan</th><th align="right">Saat</th></tr>
+  {{- range .Roster}}
+  <tr><td>{{.Name}}</td><td align="right">{{hours .Hours}}</td></tr>
+  {{- end}}
+</table>
+{{- else}}
+<p>Çalışan bilgisi bulunamadı.</p>
+{{- end}}
+
+<h3>🏢 Proje Bazında Saatler</h3>
+{{- if .Projects}}
+<table cellpadding="6" cellspacing="0" border="1" style="border-collapse: collapse; border-color: #ccc;">
+  <tr style="background: #f0f0f0;"><th align="left">Proje</th><th align="right">Saat</th></tr>
+  {{- range .Projects}}
+  <tr><td>{{.Name}}</td><td align="right">{{hours .Hours}}</td></tr>
+  {{- end}}
+</table>
+{{- else}}
+<p>Proje bilgisi bulunamadı.</p>
+{{- end}}
+
+<h3>📅 Günlük Çalışma Saatleri</h3>
+{{- if .Days}}
+<table cellpadding="6" cellspacing="0" border="1" style="border-collapse: collapse; border-color: #ccc;">
+  <tr style="background: #f0f0f0;"><th align="left">Tarih</th><th align="left">Çalışan</th><th align="right">Saat</th><th align="left">Durum</th></tr>
+  {{- range .Days}}{{$date := .Date}}
+  {{- range .Employees}}
+  <tr{{if short .Status}} style="background: #fde2e2; color: #b00020;"{{end}}><td>{{$date}}</td><td>{{.Employee}}</td><td align="right">{{hours .Hours}}</td><td>{{.Status}}</td></tr>
+  {{- end}}
+  {{- end}}
+</table>
+{{- else}}
+<p>Bu dönemde kayıt bulunamadı.</p>
+{{- end}}
+
+{{- if .Missing}}
+<h3>⚠️ Eksik Girilen İş Günleri</h3>
+<table cellpadding="6" cellspacing="0" border="1" style="border-collapse: collapse; border-color: #ccc;">
+  <tr style="background: #f0f0f0;"><th align="left">Çalışan</th><th align="left">Tarih</th><th align="left">Durum</th></tr>
+  {{- range .Missing}}
+  <tr style="background: #fde2e2; color: #b00020;"><td>{{.Employee}}</td><td>{{.Date}}</td><td>{{missing .}}</td></tr>
+  {{- end}}
+</table>
+{{- end}}
+
+{{- if .Attachments}}
+<p style="color: #888; font-size: 12px;">Kayıtların tamamı ekteki {{range $i, $a := .Attachments}}{{if $i}}, {{end}}{{$a.Name}}{{end}} dosyalarındadır.</p>
+{{- end}}
+</body>
+</html>
+`))
+
+// E-posta gövdesi için HTML rapor yaz; beklenen saatin altındaki günler
+// kırmızı ile vurgulanır. Ekler verilmişse altbilgide listelenir.
+func renderHTML(w io.Writer, r *Report, attachments []Attachment) error {
+	return htmlReport.Execute(w, struct {
+		*Report
+		Attachments []Attachment
+	}{r, attachments})
+}
@@ -0,0 +1,292 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"odoo-efor-tracker/odoo"
+
+	"github.com/schollz/progressbar/v3"
+)
+
+// Günlük çalışma durumu etiketleri
+const (
+	StatusShort = "Az Çalışmış"
+	StatusFull  = "Tam Çalışmış"
+	StatusTop   = "En ÇOK Çalışmış"
+)
+
+// Bir çalışanın ya da projenin toplam saati
+type HoursTotal struct {
+	Name  string  `json:"name"`
+	Hours float64 `json:"hours"`
+}
+
+// Bir çalışanın belirli bir gündeki toplam saati, beklenen saati ve durumu
+type DailyHours struct {
+	Employee string  `json:"employee"`
+	Hours    float64 `json:"hours"`
+	Expected float64 `json:"expected"`
+	Status   string  `json:"status"`
+}
+
+// Bir günün çalışan bazında saatleri
+type DayTotal struct {
+	Date      string       `json:"date"`
+	Hours     float64      `json:"hours"`
+	Employees []DailyHours `json:"employees"`
+}
+
+// Report, bir dönemin toplanmış zaman çizelgesi verisi. Metin, e-posta,
+// Telegram ve dışa aktarma (JSON/CSV/XLSX) çıktıları bu yapıdan üretilir.
+type Report struct {
+	Period      Period    `json:"period"`
+	Team        string    `json:"team,omitempty"`
+	Employee    string    `json:"employee,omitempty"` // Tek çalışan için filtrelendiyse çalışanın adı
+	GeneratedAt time.Time `json:"generated_at"`
+
+	Entries    []odoo.TimesheetLine `json:"entries"`
+	TotalHours float64              `json:"total_hours"`
+	Employees  []HoursTotal         `json:"employees"` // Kaydı olan çalışanlar, saate göre azalan
+	Projects   []HoursTotal         `json:"projects"`  // Projeler, saate göre azalan
+	Days       []DayTotal           `json:"days"`      // Tarihe göre artan
+	Roster     []HoursTotal         `json:"roster"`    // Rapor kapsamındaki tüm çalışanlar (kaydı olmayanlar dahil), ada göre
+	Missing    []MissingDay         `json:"missing"`   // Beklenen iş günlerinde eksik/boş günler
+	Warnings   []string             `json:"warnings,omitempty"`
+
+	// Ekip ya da çalışan filtresiyle belirlenen çalışanlar; filtre yoksa nil
+	Members []odoo.Employee `json:"-"`
+
+	// Beklenen saatlerin hesaplandığı çalışma takvimi
+	calendar *WorkCalendar
+}
+
+// Günlük saate göre durum etiketini belirle
+func dailyStatus(hours, expected, dayMax float64) string {
+	if hours < expected {
+		return StatusShort
+	}
+	if hours == dayMax {
+		return StatusTop
+	}
+	return StatusFull
+}
+
+// Günlük beklenen saatleri ve durum etiketlerini çalışanların çalışma
+// takvimine göre yeniden hesapla (yarı zamanlı, kısa cuma, yarım gün izin)
+func (r *Report) applyCalendar(cal *WorkCalendar) {
+	ids := make(map[string]int64)
+	for _, entry := range r.Entries {
+		ids[entry.Employee.Name] = entry.Employee.ID
+	}
+
+	for i := range r.Days {
+		day := &r.Days[i]
+		date, err := time.ParseInLocation(dateLayout, day.Date, r.Period.Start.Location())
+		if err != nil {
+			continue
+		}
+		dayMax := 0.0
+		for _, emp := range day.Employees {
+			if emp.Hours > dayMax {
+				dayMax = emp.Hours
+			}
+		}
+		for j := range day.Employees {
+			emp := &day.Employees[j]
+			emp.Expected = cal.Expected(ids[emp.Employee], date)
+			emp.Status = dailyStatus(emp.Hours, emp.Expected, dayMax)
+		}
+	}
+}
+
+// Kayıtlardan raporu oluştur. roster nil ise kapsam, kaydı olan çalışanlardır.
+// Günlük beklenen saat ve durum çalışma takvimine bağlıdır; applyCalendar
+// çağrılana kadar boş kalır.
+func buildReport(period Period, entries []odoo.TimesheetLine, roster []odoo.Employee) *Report {
+	report := &Report{Period: period, GeneratedAt: time.Now()}
+
+	employeeHours := make(map[string]float64)
+	projectHours := make(map[string]float64)
+	dailyHours := make(map[string]map[string]float64)
+
+	report.Entries = append([]odoo.TimesheetLine(nil), entries...)
+	sort.SliceStable(report.Entries, func(i, j int) bool {
+		if report.Entries[i].Date != report.Entries[j].Date {
+			return report.Entries[i].Date < report.Entries[j].Date
+		}
+		return report.Entries[i].ID < report.Entries[j].ID
+	})
+
+	for _, entry := range report.Entries {
+		employeeName := entry.Employee.Name
+		report.TotalHours += entry.UnitAmount
+		employeeHours[employeeName] += entry.UnitAmount
+		projectHours[entry.Project.Name] += entry.UnitAmount
+
+		if dailyHours[entry.Date] == nil {
+			dailyHours[entry.Date] = make(map[string]float64)
+		}
+		dailyHours[entry.Date][employeeName] += entry.UnitAmount
+	}
+
+	report.Employees = sortedTotals(employeeHours)
+	report.Projects = sortedTotals(projectHours)
+
+	var dates []string
+	for date := range dailyHours {
+		dates = append(dates, date)
+	}
+	sort.Strings(dates)
+
+	for _, date := range dates {
+		day := DayTotal{Date: date}
+		for emp, hours := range dailyHours[date] {
+			day.Hours += hours
+			day.Employees = append(day.Employees, DailyHours{Employee: emp, Hours: hours})
+		}
+		sort.Slice(day.Employees, func(i, j int) bool {
+			return day.Employees[i].Employee < day.Employees[j].Employee
+		})
+		report.Days = append(report.Days, day)
+	}
+
+	rosterHours := make(map[string]float64)
+	for _, emp := range roster {
+		rosterHours[emp.Name] = 0.0
+	}
+	for emp, hours := range employeeHours {
+		rosterHours[emp] = hours
+	}
+	for name, hours := range rosterHours {
+		report.Roster = append(report.Roster, HoursTotal{Name: name, Hours: hours})
+	}
+	sort.Slice(report.Roster, func(i, j int) bool {
+		return report.Roster[i].Name < report.Roster[j].Name
+	})
+
+	return report
+}
+
+// Saat eşlemesini saate göre azalan, eşitlikte ada göre sıralı listeye çevir
+func sortedTotals(hours map[string]float64) []HoursTotal {
+	totals := make([]HoursTotal, 0, len(hours))
+	for name, h := range hours {
+		totals = append(totals, HoursTotal{Name: name, Hours: h})
+	}
+	sort.Slice(totals, func(i, j int) bool {
+		if totals[i].Hours != totals[j].Hours {
+			return totals[i].Hours > totals[j].Hours
+		}
+		return totals[i].Name < totals[j].Name
+	})
+	return totals
+}
+
+// Sayfalama için yardımcı fonksiyon
+func fetchTimeSheetPage(client odoo.API, domain odoo.Domain, offset int) ([]odoo.TimesheetLine, error) {
+	var records []odoo.TimesheetLine
+	err := client.SearchRead(odoo.ModelTimesheet, domain, &odoo.SearchOptions{
+		Offset: offset,
+		Limit:  PageSize,
+		Order:  "date, id",
+	}, &records)
+	return records, err
+}
+
+// Domain'e uyan tüm kayıtları sayfalar halinde, en fazla MaxWorkers
+// paralel istekle indir
+func fetchTimesheets(client odoo.API, domain odoo.Domain) ([]odoo.TimesheetLine, error) {
+	// Toplam kayıt sayısını al
+	count, err := client.SearchCount(odoo.ModelTimesheet, domain)
+	if err != nil {
+		return nil, fmt.Errorf("error getting record count: %v", err)
+	}
+
+	fmt.Printf("Toplam kayıt sayısı: %d\n", count)
+
+	// Progress bar oluştur
+	bar := progressbar.NewOptions(count,
+		progressbar.OptionEnableColorCodes(true),
+		progressbar.OptionShowBytes(false),
+		progressbar.OptionSetWidth(15),
+		progressbar.OptionSetDescription("[cyan]Veriler işleniyor...[reset]"),
+		progressbar.OptionSetTheme(progressbar.Theme{
+			Saucer:        "[green]=[reset]",
+			SaucerHead:    "[green]>[reset]",
+			SaucerPadding: " ",
+			BarStart:      "[",
+			BarEnd:        "]",
+		}))
+	defer fmt.Println() // Progress bar'dan sonra yeni satır
+
+	pages := make([][]odoo.TimesheetLine, (count+PageSize-1)/PageSize)
+	errs := make([]error, len(pages))
+	sem := make(chan struct{}, MaxWorkers)
+	var wg sync.WaitGroup
+
+	// Sayfaları paralel indir
+	for i := range pages {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			pages[i], errs[i] = fetchTimeSheetPage(client, domain, i*PageSize)
+			bar.Add(len(pages[i]))
+		}(i)
+	}
+	wg.Wait()
+
+	var records []odoo.TimesheetLine
+	for i, page := range pages {
+		if errs[i] != nil {
+			return nil, fmt.Errorf("error fetching page at offset %d: %v", i*PageSize, errs[i])
+		}
+		records = append(records, page...)
+	}
+	return records, nil
+}
+
+// Dönem ve filtrelere göre kayıtları Odoo'dan çekip raporu oluştur. now'dan
+// sonraki günler eksik sayılmaz.
+func loadReport(client odoo.API, period Period, employeeFilter, teamFilter string, now time.Time) (*Report, error) {
+	domain := odoo.NewDomain().
+		Where("date", ">=", period.Start.Format(dateLayout)).
+		Where("date", "<=", period.End.Format(dateLayout))
+
+	// Çalışan/ekip filtresini hr.employee ID'lerine çözümleyip sorguya ekle;
+	// böylece yalnızca ilgili çalışanların kayıtları indirilir
+	employees, err := reportEmployees(client, employeeFilter, teamFilter)
+	if err != nil {
+		return nil, err
+	}
+	if employees != nil {
+		ids := make([]int64, 0, len(employees))
+		for _, emp := range employees {
+			ids = append(ids, emp.ID)
+		}
+		domain = domain.Where("employee_id", "in", ids)
+	}
+
+	entries, err := fetchTimesheets(client, domain)
+	if err != nil {
+		return nil, err
+	}
+
+	report := buildReport(period, entries, employees)
+	report.Team = teamFilter
+	report.Members = employees
+	if employeeFilter != "" {
+		report.Employee = employees[0].Name
+	}
+
+	// Kayıt girilmemiş ya da eksik girilmiş iş günlerini bul
+	if err := applyWorkCalendar(client, report, now); err != nil {
+		return nil, err
+	}
+	return report, nil
+}
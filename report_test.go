@@ -0,0 +1,104 @@
+package main
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"odoo-efor-tracker/odoo"
+)
+
+func line(id int64, date, employee, project string, hours float64) odoo.TimesheetLine {
+	return odoo.TimesheetLine{
+		ID:         id,
+		Date:       date,
+		Employee:   odoo.Many2One{ID: id, Name: employee},
+		Project:    odoo.Many2One{ID: 1, Name: project},
+		UnitAmount: hours,
+	}
+}
+
+func TestBuildReport(t *testing.T) {
+	period := Period{
+		Start: time.Date(2025, 2, 3, 0, 0, 0, 0, time.UTC),
+		End:   time.Date(2025, 2, 4, 0, 0, 0, 0, time.UTC),
+	}
+	entries := []odoo.TimesheetLine{
+		line(3, "2025-02-04", "Ali", "CX", 9),
+		line(1, "2025-02-03", "Ali", "CX", 6),
+		line(2, "2025-02-03", "Ali", "İç", 2),
+		line(4, "2025-02-03", "Veli", "CX", 5),
+		line(5, "2025-02-04", "Veli", "CX", 8),
+	}
+	roster := []odoo.Employee{{ID: 9, Name: "Zeynep"}, {ID: 4, Name: "Veli"}}
+
+	r := buildReport(period, entries, roster)
+
+	if r.TotalHours != 30 {
+		t.Errorf("TotalHours = %.2f, beklenen 30", r.TotalHours)
+	}
+	if r.Entries[0].ID != 1 || r.Entries[4].ID != 5 {
+		t.Errorf("kayıtlar tarihe göre sıralanmadı: %v", r.Entries)
+	}
+	if want := []HoursTotal{{"Ali", 17}, {"Veli", 13}}; !reflect.DeepEqual(r.Employees, want) {
+		t.Errorf("Employees = %v, beklenen %v", r.Employees, want)
+	}
+	if want := []HoursTotal{{"CX", 28}, {"İç", 2}}; !reflect.DeepEqual(r.Projects, want) {
+		t.Errorf("Projects = %v, beklenen %v", r.Projects, want)
+	}
+
+	// Beklenen saat ve durum takvim uygulanana kadar boştur
+	wantDays := []DayTotal{
+		{Date: "2025-02-03", Hours: 13, Employees: []DailyHours{{"Ali", 8, 0, ""}, {"Veli", 5, 0, ""}}},
+		{Date: "2025-02-04", Hours: 17, Employees: []DailyHours{{"Ali", 9, 0, ""}, {"Veli", 8, 0, ""}}},
+	}
+	if !reflect.DeepEqual(r.Days, wantDays) {
+		t.Errorf("Days = %+v, beklenen %+v", r.Days, wantDays)
+	}
+
+	r.applyCalendar(newWorkCalendar())
+	wantDays = []DayTotal{
+		{Date: "2025-02-03", Hours: 13, Employees: []DailyHours{
+			{"Ali", 8, MinWorkHours, StatusTop},
+			{"Veli", 5, MinWorkHours, StatusShort},
+		}},
+		{Date: "2025-02-04", Hours: 17, Employees: []DailyHours{
+			{"Ali", 9, MinWorkHours, StatusTop},
+			{"Veli", 8, MinWorkHours, StatusFull},
+		}},
+	}
+	if !reflect.DeepEqual(r.Days, wantDays) {
+		t.Errorf("takvimle Days = %+v, beklenen %+v", r.Days, wantDays)
+	}
+
+	// Kaydı olmayan ekip üyeleri de kapsamda yer alır
+	if want := []HoursTotal{{"Ali", 17}, {"Veli", 13}, {"Zeynep", 0}}; !reflect.DeepEqual(r.Roster, want) {
+		t.Errorf("Roster = %v, beklenen %v", r.Roster, want)
+	}
+}
+
+func TestRenderSummary(t *testing.T) {
+	period := Period{
+		Start: time.Date(2025, 2, 3, 0, 0, 0, 0, time.UTC),
+		End:   time.Date(2025, 2, 3, 0, 0, 0, 0, time.UTC),
+	}
+	r := buildReport(period, []odoo.TimesheetLine{line(1, "2025-02-03", "Ali", "CX", 6)}, nil)
+
+	summary := renderSummary(r)
+	for _, want := range []string{
+		"Tarih Aralığı: 2025-02-03 - 2025-02-03",
+		"Toplam Çalışma Saati: 6.00",
+		"Ali: 6.00 saat",
+		"CX: 6.00 saat",
+	} {
+		if !strings.Contains(summary, want) {
+			t.Errorf("özette %q bulunamadı:\n%s", want, summary)
+		}
+	}
+
+	empty := renderSummary(buildReport(period, nil, nil))
+	if !strings.Contains(empty, "Çalışan bilgisi bulunamadı.") {
+		t.Errorf("boş rapor özeti beklenen mesajı içermiyor:\n%s", empty)
+	}
+}
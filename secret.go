@@ -0,0 +1,70 @@
+package main
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// Şifreli değerlerin biçim öneki; anahtar ya da algoritma değişirse artırılır
+const secretPrefix = "v1:"
+
+// BOT_SECRET_KEY'den (base64, 32 bayt) AES-256-GCM şifreleyicisi oluştur.
+// Anahtar üretmek için: openssl rand -base64 32
+func secretCipher() (cipher.AEAD, error) {
+	encoded := os.Getenv("BOT_SECRET_KEY")
+	if encoded == "" {
+		return nil, errors.New("BOT_SECRET_KEY çevre değişkeni ayarlanmamış")
+	}
+	key, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil || len(key) != 32 {
+		return nil, errors.New("BOT_SECRET_KEY 32 baytlık base64 değer olmalı (openssl rand -base64 32)")
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// Değeri şifrele. context (ör. Telegram kullanıcı ID'si) şifreli değere
+// bağlanır; başka bir bağlamda çözülemez.
+func encryptSecret(plain, context string) (string, error) {
+	aead, err := secretCipher()
+	if err != nil {
+		return "", err
+	}
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+	sealed := aead.Seal(nonce, nonce, []byte(plain), []byte(context))
+	return secretPrefix + base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+// encryptSecret ile şifrelenmiş değeri çöz
+func decryptSecret(secret, context string) (string, error) {
+	aead, err := secretCipher()
+	if err != nil {
+		return "", err
+	}
+	encoded, ok := strings.CutPrefix(secret, secretPrefix)
+	if !ok {
+		return "", errors.New("bilinmeyen şifreli değer biçimi")
+	}
+	sealed, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil || len(sealed) < aead.NonceSize() {
+		return "", errors.New("şifreli değer bozuk")
+	}
+	nonce, ciphertext := sealed[:aead.NonceSize()], sealed[aead.NonceSize():]
+	plain, err := aead.Open(nil, nonce, ciphertext, []byte(context))
+	if err != nil {
+		return "", fmt.Errorf("şifreli değer çözülemedi (BOT_SECRET_KEY değişmiş olabilir)")
+	}
+	return string(plain), nil
+}
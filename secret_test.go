@@ -0,0 +1,37 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+const testSecretKey = "MDEyMzQ1Njc4OWFiY2RlZjAxMjM0NTY3ODlhYmNkZWY="
+
+func TestSecretRoundTrip(t *testing.T) {
+	t.Setenv("BOT_SECRET_KEY", testSecretKey)
+
+	secret, err := encryptSecret("gizli-anahtar", "2002")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(secret, secretPrefix) || strings.Contains(secret, "gizli-anahtar") {
+		t.Fatalf("şifreli değer = %q", secret)
+	}
+	if plain, err := decryptSecret(secret, "2002"); err != nil || plain != "gizli-anahtar" {
+		t.Errorf("decryptSecret = %q, %v", plain, err)
+	}
+
+	// Başka kullanıcının bağlamında ya da değiştirilmiş değerle çözülemez
+	if _, err := decryptSecret(secret, "3003"); err == nil {
+		t.Error("farklı bağlamla çözüldü")
+	}
+	tampered := secret[:len(secret)-2] + "AA"
+	if _, err := decryptSecret(tampered, "2002"); err == nil {
+		t.Error("değiştirilmiş değer çözüldü")
+	}
+
+	t.Setenv("BOT_SECRET_KEY", "")
+	if _, err := encryptSecret("gizli-anahtar", "2002"); err == nil {
+		t.Error("BOT_SECRET_KEY olmadan şifreleme yapıldı")
+	}
+}
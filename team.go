@@ -0,0 +1,201 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+	"strings"
+
+	"odoo-efor-tracker/odoo"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Ekip yapılandırma dosyasının varsayılan yolu
+const DefaultTeamsFile = "teams.yaml"
+
+// Ekip tanımı. Üyeler adla ya da hr.employee ID'siyle listelenebilir veya
+// Odoo'daki departman/etiket bilgisinden dinamik olarak çekilebilir.
+type TeamConfig struct {
+	Name        string   `yaml:"name"`
+	Members     []string `yaml:"members"`
+	EmployeeIDs []int64  `yaml:"employee_ids"`
+	Department  string   `yaml:"department"`
+	Tags        []string `yaml:"tags"`
+
+	// Ekip raporunun gönderileceği adresler; boşsa genel alıcılar kullanılır
+	Recipients Recipients `yaml:"recipients"`
+
+	// Ardışık eksik gün bildirimlerinin gönderileceği ekip lideri
+	Lead Contact `yaml:"lead"`
+}
+
+// Tüm ekiplerin tanımlandığı yapılandırma
+type RosterConfig struct {
+	DefaultTeam string       `yaml:"default_team"`
+	Teams       []TeamConfig `yaml:"teams"`
+
+	// Ekibe özel alıcı tanımlanmamışsa kullanılan genel alıcılar
+	Recipients Recipients `yaml:"recipients"`
+
+	// Eksik kayıt hatırlatmaları
+	Reminders ReminderConfig `yaml:"reminders"`
+}
+
+// Ekip yapılandırmasını TEAMS_FILE (varsayılan teams.yaml) dosyasından oku.
+// YAML bir JSON üst kümesi olduğu için .json dosyaları da okunabilir.
+// Dosya yoksa boş yapılandırma döner ve raporlar tüm çalışanları kapsar.
+func loadRosterConfig() (*RosterConfig, error) {
+	path := os.Getenv("TEAMS_FILE")
+	if path == "" {
+		path = DefaultTeamsFile
+	}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return &RosterConfig{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("ekip dosyası okunamadı: %v", err)
+	}
+
+	var cfg RosterConfig
+	if err := yaml.Unmarshal(data, &cfg); err != nil {
+		return nil, fmt.Errorf("ekip dosyası çözümlenemedi (%s): %v", path, err)
+	}
+	return &cfg, nil
+}
+
+// Adı verilen ekibi bul. Ad boşsa varsayılan ekip döner; hiç ekip
+// tanımlanmamışsa nil döner (filtre uygulanmaz).
+func (c *RosterConfig) Team(name string) (*TeamConfig, error) {
+	if name == "" {
+		name = c.DefaultTeam
+	}
+	if name == "" {
+		if len(c.Teams) == 1 {
+			return &c.Teams[0], nil
+		}
+		return nil, nil
+	}
+
+	for i := range c.Teams {
+		if strings.EqualFold(c.Teams[i].Name, name) {
+			return &c.Teams[i], nil
+		}
+	}
+	return nil, fmt.Errorf("ekip bulunamadı: %s (tanımlı ekipler: %s)", name, strings.Join(c.TeamNames(), ", "))
+}
+
+// Tanımlı ekiplerin adları
+func (c *RosterConfig) TeamNames() []string {
+	names := make([]string, 0, len(c.Teams))
+	for _, team := range c.Teams {
+		names = append(names, team.Name)
+	}
+	return names
+}
+
+// Ekip üyelerini hr.employee kayıtları olarak döndür. İsimle ya da ID ile
+// listelenen üyeler ile departman/etiket eşleşen çalışanlar tek bir sorguda
+// birleştirilir. Odoo'da bulunamayan isimler için uyarı loglanır.
+func (t *TeamConfig) Employees(client odoo.API) ([]odoo.Employee, error) {
+	var domains []odoo.Domain
+	if len(t.Members) > 0 {
+		domains = append(domains, odoo.NewDomain().Where("name", "in", t.Members))
+	}
+	if len(t.EmployeeIDs) > 0 {
+		domains = append(domains, odoo.NewDomain().Where("id", "in", t.EmployeeIDs))
+	}
+	if t.Department != "" || len(t.Tags) > 0 {
+		dynamic := odoo.NewDomain()
+		if t.Department != "" {
+			dynamic = dynamic.Where("department_id", "ilike", t.Department)
+		}
+		if len(t.Tags) > 0 {
+			var tagDomains []odoo.Domain
+			for _, tag := range t.Tags {
+				tagDomains = append(tagDomains, odoo.NewDomain().Where("category_ids", "=", tag))
+			}
+			dynamic = append(dynamic, odoo.Or(tagDomains...)...)
+		}
+		domains = append(domains, dynamic)
+	}
+	if len(domains) == 0 {
+		return []odoo.Employee{}, nil
+	}
+
+	var employees []odoo.Employee
+	err := client.SearchRead(odoo.ModelEmployee, odoo.Or(domains...), &odoo.SearchOptions{Order: "name"}, &employees)
+	if err != nil {
+		return nil, fmt.Errorf("%s ekibinin çalışanları alınamadı: %v", t.Name, err)
+	}
+
+	found := make(map[string]bool, len(employees))
+	for _, emp := range employees {
+		found[emp.Name] = true
+	}
+	for _, name := range t.Members {
+		if !found[name] {
+			log.Printf("Uyarı: %s ekibindeki %q Odoo'da bulunamadı", t.Name, name)
+		}
+	}
+	return employees, nil
+}
+
+// Çalışan filtresini (ad ya da ID) tek bir hr.employee kaydına çözümle.
+// Önce birebir ad eşleşmesi aranır; bulunamazsa ilike ile aranır ve
+// birden fazla sonuç çıkarsa hata döner.
+func resolveEmployee(client odoo.API, filter string) (*odoo.Employee, error) {
+	var employees []odoo.Employee
+	if id, err := strconv.ParseInt(filter, 10, 64); err == nil {
+		if err := client.SearchRead(odoo.ModelEmployee, odoo.NewDomain().Where("id", "=", id), nil, &employees); err != nil {
+			return nil, err
+		}
+	} else {
+		if err := client.SearchRead(odoo.ModelEmployee, odoo.NewDomain().Where("name", "=", filter), nil, &employees); err != nil {
+			return nil, err
+		}
+		if len(employees) == 0 {
+			if err := client.SearchRead(odoo.ModelEmployee, odoo.NewDomain().Where("name", "ilike", filter), nil, &employees); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	switch len(employees) {
+	case 0:
+		return nil, fmt.Errorf("çalışan bulunamadı: %s", filter)
+	case 1:
+		return &employees[0], nil
+	}
+	names := make([]string, 0, len(employees))
+	for _, emp := range employees {
+		names = append(names, fmt.Sprintf("%s (%d)", emp.Name, emp.ID))
+	}
+	return nil, fmt.Errorf("%q birden fazla çalışanla eşleşti: %s", filter, strings.Join(names, ", "))
+}
+
+// Raporun kapsadığı çalışanları belirle. Çalışan filtresi ekipten önceliklidir.
+// Ne çalışan ne de ekip tanımlıysa nil döner ve tüm çalışanlar rapora girer.
+func reportEmployees(client odoo.API, employeeFilter, teamName string) ([]odoo.Employee, error) {
+	if employeeFilter != "" {
+		emp, err := resolveEmployee(client, employeeFilter)
+		if err != nil {
+			return nil, err
+		}
+		return []odoo.Employee{*emp}, nil
+	}
+
+	cfg, err := loadRosterConfig()
+	if err != nil {
+		return nil, err
+	}
+	team, err := cfg.Team(teamName)
+	if err != nil || team == nil {
+		return nil, err
+	}
+	return team.Employees(client)
+}
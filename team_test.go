@@ -0,0 +1,118 @@
+package main
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"odoo-efor-tracker/odoo"
+)
+
+func TestTeamEmployees(t *testing.T) {
+	srv := newFakeOdoo(t)
+	client, err := odoo.Dial(srv.Config(""))
+	if err != nil {
+		t.Fatalf("Dial: %v", err)
+	}
+	defer client.Close()
+
+	cfg, err := loadRosterConfig()
+	if err != nil {
+		t.Fatalf("loadRosterConfig: %v", err)
+	}
+
+	tests := []struct {
+		team string
+		want []string
+	}{
+		{"", []string{"Ayşegül Şahin", "Fatih Delice", "Osman Çağrı GENÇ"}},
+		{"backend", []string{"Fatih Delice", "Osman Çağrı GENÇ"}},
+		{"QA", []string{"Ayşegül Şahin", "Harici Danışman"}},
+	}
+	for _, tt := range tests {
+		team, err := cfg.Team(tt.team)
+		if err != nil {
+			t.Fatalf("Team(%q): %v", tt.team, err)
+		}
+		employees, err := team.Employees(client)
+		if err != nil {
+			t.Fatalf("Employees(%q): %v", tt.team, err)
+		}
+		var got []string
+		for _, emp := range employees {
+			got = append(got, emp.Name)
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("Employees(%q) = %v, beklenen %v", tt.team, got, tt.want)
+		}
+	}
+
+	if _, err := cfg.Team("olmayan"); err == nil {
+		t.Error("tanımsız ekip için hata bekleniyordu")
+	}
+}
+
+func TestGenerateReportTeam(t *testing.T) {
+	newFakeOdoo(t)
+	chdirTemp(t)
+
+	report := runReport(t, "2025-02-03", "", "qa")
+	if !strings.Contains(report, "Harici Danışman: 4.00 saat") || strings.Contains(report, "Osman Çağrı GENÇ: 8.00") {
+		t.Errorf("qa ekibi raporu beklenen çalışanları içermiyor:\n%s", report)
+	}
+}
+
+func TestResolveEmployee(t *testing.T) {
+	srv := newFakeOdoo(t)
+	client, err := odoo.Dial(srv.Config(""))
+	if err != nil {
+		t.Fatalf("Dial: %v", err)
+	}
+	defer client.Close()
+
+	for filter, want := range map[string]int64{
+		"Fatih Delice": 3,
+		"ayşegül":      2,
+		"4":            4,
+	} {
+		emp, err := resolveEmployee(client, filter)
+		if err != nil {
+			t.Errorf("resolveEmployee(%q): %v", filter, err)
+			continue
+		}
+		if emp.ID != want {
+			t.Errorf("resolveEmployee(%q) = %d, beklenen %d", filter, emp.ID, want)
+		}
+	}
+
+	if _, err := resolveEmployee(client, "Osman"); err != nil {
+		t.Errorf("tek eşleşme için hata: %v", err)
+	}
+	if _, err := resolveEmployee(client, "i"); err == nil || !strings.Contains(err.Error(), "birden fazla") {
+		t.Errorf("belirsiz filtre için hata bekleniyordu, gelen: %v", err)
+	}
+}
+
+func TestGenerateReportFiltersOnServer(t *testing.T) {
+	srv := newFakeOdoo(t)
+	chdirTemp(t)
+
+	// Çalışan Odoo'da yeniden adlandırılsa da ID ile eşleştiği için rapora girer
+	runReport(t, "2025-02-04", "3", "")
+
+	for _, call := range srv.Calls() {
+		if call.Model != odoo.ModelTimesheet || call.Method != "search_read" {
+			continue
+		}
+		domain, _ := call.Args[0].([]interface{})
+		found := false
+		for _, term := range domain {
+			if leaf, ok := term.([]interface{}); ok && leaf[0] == "employee_id" && leaf[1] == "in" {
+				found = reflect.DeepEqual(leaf[2], []interface{}{int64(3)})
+			}
+		}
+		if !found {
+			t.Errorf("zaman kaydı sorgusu çalışan ID filtresi içermiyor: %v", domain)
+		}
+	}
+}
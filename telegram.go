@@ -1,22 +1,26 @@
 package main
 
 import (
+	"errors"
 	"fmt"
 	"log"
 	"os"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
+	"odoo-efor-tracker/odoo"
+
 	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
-	"github.com/kolo/xmlrpc"
 	"github.com/robfig/cron/v3"
 )
 
 var (
 	bot      *tgbotapi.BotAPI
-	chatID   int64
+	chatID   int64 // Zamanlanmış raporların gönderildiği sohbet
 	cronJobs *cron.Cron
+	users    *UserRegistry
 )
 
 // Telegram botunu başlat
@@ -37,6 +41,16 @@ func InitTelegramBot() error {
 		return fmt.Errorf("geçersiz TELEGRAM_CHAT_ID: %v", err)
 	}
 
+	users, err = loadUserRegistry()
+	if err != nil {
+		return err
+	}
+
+	// Zamanlayıcı ayarları hatalıysa /stop hiçbir kaydı oluşturamaz
+	if _, _, err := timerSettings(); err != nil {
+		return err
+	}
+
 	bot, err = tgbotapi.NewBotAPI(token)
 	if err != nil {
 		return fmt.Errorf("bot başlatılamadı: %v", err)
@@ -60,6 +74,18 @@ func StartScheduledJobs() {
 		sendDailyReport("evening")
 	})
 
+	// Eksik kayıt hatırlatmaları (teams.yaml "reminders")
+	if cfg, err := loadRosterConfig(); err != nil {
+		log.Printf("Hatırlatma ayarları okunamadı: %v", err)
+	} else if cfg.Reminders.Time != "" {
+		spec, err := cfg.Reminders.cronSpec()
+		if err != nil {
+			log.Printf("Hatırlatmalar başlatılamadı: %v", err)
+		} else {
+			cronJobs.AddFunc(spec, sendReminders)
+		}
+	}
+
 	cronJobs.Start()
 	log.Println("Zamanlanmış görevler başlatıldı")
 }
@@ -69,6 +95,15 @@ func sendDailyReport(timeOfDay string) {
 	now := time.Now()
 	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
 
+	// Hafta sonu ve tatil günlerinde rapor gönderilmez
+	holidays, err := loadHolidayCalendar()
+	if err != nil {
+		log.Printf("Tatil takvimi yüklenemedi: %v", err)
+	} else if !holidays.IsWorkday(today) {
+		log.Printf("%s iş günü değil, %s raporu atlandı", today.Format(dateLayout), timeOfDay)
+		return
+	}
+
 	var message string
 	if timeOfDay == "morning" {
 		message = fmt.Sprintf("🌞 Günaydın! %s tarihli günlük rapor hazırlanıyor...", today.Format("2006-01-02"))
@@ -77,111 +112,76 @@ func sendDailyReport(timeOfDay string) {
 	}
 
 	// Önce bildirim mesajı gönder
-	sendTelegramMessage(message)
+	sendTelegramMessage(chatID, message)
 
 	// Raporu oluştur ve gönder
-	outputFileName, err := generateReport("daily", "", true)
+	period, _ := parsePeriod("today", now)
+	sendPeriodReport(chatID, period, "", "", FormatText, MailModeTeam)
+}
+
+// Zamanlanmış hatırlatma görevi; hafta sonu ve tatillerde çalışmaz
+func sendReminders() {
+	now := time.Now()
+	holidays, err := loadHolidayCalendar()
 	if err != nil {
-		sendTelegramMessage(fmt.Sprintf("❌ Rapor oluşturulurken hata oluştu: %v", err))
+		log.Printf("Tatil takvimi yüklenemedi: %v", err)
+	} else if !holidays.IsWorkday(now) {
+		log.Printf("%s iş günü değil, hatırlatmalar atlandı", now.Format(dateLayout))
 		return
 	}
 
-	// Rapor dosyasını oku ve gönder
-	reportContent, err := os.ReadFile(outputFileName)
+	client, err := authenticateOdoo()
 	if err != nil {
-		sendTelegramMessage(fmt.Sprintf("❌ Rapor dosyası okunamadı: %v", err))
+		log.Printf("Odoo kimlik doğrulama hatası: %v", err)
 		return
 	}
+	defer client.Close()
 
-	// Raporu özetle ve gönder
-	summary := generateSummary(string(reportContent))
-	sendTelegramMessage(summary)
-}
-
-// Rapor özetini oluştur
-func generateSummary(content string) string {
-	lines := strings.Split(content, "\n")
-	summary := "📊 **Günlük Çalışma Raporu Özeti**\n\n"
-
-	// Tarih aralığını bul
-	for _, line := range lines {
-		if strings.HasPrefix(line, "Tarih Aralığı:") {
-			summary += line + "\n\n"
-			break
-		}
-	}
-
-	// Toplam çalışma saatini bul
-	totalHoursFound := false
-	for _, line := range lines {
-		if strings.HasPrefix(line, "Toplam Çalışma Saati:") {
-			summary += line + "\n\n"
-			totalHoursFound = true
-			break
-		}
+	if err := runReminders(client, now); err != nil {
+		log.Printf("Hatırlatmalar gönderilemedi: %v", err)
 	}
+}
 
-	if !totalHoursFound {
-		summary += "Toplam çalışma saati bulunamadı.\n\n"
+// Verilen dönem için rapor oluştur ve özetini sohbete gönder. Biçim metin
+// dışındaysa rapor dosyası da belge olarak gönderilir.
+func sendPeriodReport(chat int64, period Period, employee, team, format, mailMode string) {
+	report, outputFileName, err := generateReport(period, employee, team, format, mailMode)
+	if err != nil {
+		sendTelegramMessage(chat, fmt.Sprintf("❌ Rapor oluşturulurken hata oluştu: %v", err))
+		return
 	}
 
-	// Çalışan bazında saatleri bul
-	summary += "👥 **Çalışan Bazında Saatler:**\n"
-	employeeSection := false
-	employeeCount := 0
-
-	for _, line := range lines {
-		if strings.Contains(line, "Çalışan Bazında Toplam Saatler:") {
-			employeeSection = true
-			continue
-		}
-
-		if employeeSection && strings.Contains(line, ":") && !strings.Contains(line, "Proje Bazında") {
-			summary += line + "\n"
-			employeeCount++
-		}
-
-		if employeeSection && strings.Contains(line, "Proje Bazında") {
-			break
-		}
+	// Raporu özetle ve gönder
+	sendTelegramMessage(chat, renderSummary(report))
+	if format != FormatText {
+		sendTelegramDocument(chat, outputFileName)
 	}
+}
 
-	if employeeCount == 0 {
-		summary += "Çalışan bilgisi bulunamadı.\n"
+// Telegram'da kullanıcıya doğrudan mesaj gönder
+func sendTelegramTo(chat int64, message string) error {
+	if bot == nil {
+		return fmt.Errorf("Telegram botu başlatılmamış")
 	}
+	_, err := bot.Send(tgbotapi.NewMessage(chat, message))
+	return err
+}
 
-	// Proje bazında saatleri bul
-	summary += "\n🏢 **Proje Bazında Saatler:**\n"
-	projectSection := false
-	projectCount := 0
-
-	for _, line := range lines {
-		if strings.Contains(line, "Proje Bazında Saatler:") {
-			projectSection = true
-			continue
-		}
-
-		if projectSection && strings.Contains(line, ":") && !strings.Contains(line, "Günlük Çalışma") {
-			summary += line + "\n"
-			projectCount++
-		}
-
-		if projectSection && strings.Contains(line, "Günlük Çalışma") {
-			break
-		}
-	}
+// Testlerde gönderimi yakalamak için değiştirilebilir
+var deliverTelegram = sendTelegramTo
 
-	if projectCount == 0 {
-		summary += "Proje bilgisi bulunamadı.\n"
+// Sohbete satır içi klavyeli düz metin mesaj gönder
+func sendTelegramKeyboard(chat int64, message string, keyboard tgbotapi.InlineKeyboardMarkup) {
+	msg := tgbotapi.NewMessage(chat, message)
+	msg.ReplyMarkup = keyboard
+	if _, err := bot.Send(msg); err != nil {
+		log.Printf("Telegram mesajı gönderilemedi: %v", err)
 	}
-
-	summary += "\nDetaylı rapor için uygulamayı çalıştırın."
-	return summary
 }
 
-// Telegram mesajı gönder
-func sendTelegramMessage(message string) {
-	msg := tgbotapi.NewMessage(chatID, message)
+// Sohbete Markdown biçimli Telegram mesajı gönder
+func sendTelegramMessage(chat int64, message string) {
+	msg := tgbotapi.NewMessage(chat, message)
 	msg.ParseMode = "Markdown"
 	_, err := bot.Send(msg)
 	if err != nil {
@@ -189,142 +189,60 @@ func sendTelegramMessage(message string) {
 	}
 }
 
-// Odoo'ya yeni zaman kaydı ekle
-func addTimeEntry(date, project, task, description string, hours float64) error {
-	client, uid, err := authenticateOdoo()
-	if err != nil {
-		return fmt.Errorf("Odoo kimlik doğrulama hatası: %v", err)
-	}
-
-	// Proje ID'sini bul
-	projectID, err := findProjectID(client, uid, project)
-	if err != nil {
-		return fmt.Errorf("proje bulunamadı: %v", err)
-	}
-
-	// Görev ID'sini bul (opsiyonel)
-	var taskID int64 = 0
-	if task != "" {
-		taskID, err = findTaskID(client, uid, projectID, task)
-		if err != nil {
-			return fmt.Errorf("görev bulunamadı: %v", err)
-		}
-	}
-
-	// Zaman kaydı oluştur
-	values := map[string]interface{}{
-		"date":        date,
-		"name":        description,
-		"unit_amount": hours,
-		"project_id":  projectID,
-	}
-
-	if taskID > 0 {
-		values["task_id"] = taskID
-	}
-
-	var result int64
-	err = client.Call("execute_kw", []interface{}{
-		os.Getenv("ODOO_DB"),
-		uid,
-		os.Getenv("ODOO_PASSWORD"),
-		"account.analytic.line",
-		"create",
-		[]interface{}{values},
-	}, &result)
-
+// Dosyayı Telegram belgesi olarak gönder
+func sendTelegramDocument(chat int64, path string) {
+	_, err := bot.Send(tgbotapi.NewDocument(chat, tgbotapi.FilePath(path)))
 	if err != nil {
-		return fmt.Errorf("zaman kaydı oluşturma hatası: %v", err)
+		log.Printf("Telegram belgesi gönderilemedi: %v", err)
 	}
-
-	return nil
 }
 
-// Proje ID'sini bul
-func findProjectID(client *xmlrpc.Client, uid int, projectName string) (int64, error) {
-	var records []interface{}
-	err := client.Call("execute_kw", []interface{}{
-		os.Getenv("ODOO_DB"),
-		uid,
-		os.Getenv("ODOO_PASSWORD"),
-		"project.project",
-		"search_read",
-		[]interface{}{
-			[]interface{}{
-				[]interface{}{"name", "ilike", projectName},
-			},
-		},
-		map[string]interface{}{
-			"fields": []string{"id", "name"},
-			"limit":  1,
-		},
-	}, &records)
+// NewTimeEntry, proje ve görevi çözümlenmiş, oluşturulmaya hazır zaman kaydı
+type NewTimeEntry struct {
+	Date        string
+	ProjectID   int64
+	TaskID      int64 // 0 ise görevsiz
+	Description string
+	Hours       float64
+}
 
+// Zaman kaydını doğrulayıp kullanıcının bağlı olduğu çalışan adına oluştur.
+// Tüm yazma yolları kayıtları bu fonksiyonla oluşturur.
+func createTimeEntry(client *odoo.Client, user *BotUser, entry NewTimeEntry) (int64, error) {
+	employeeID, err := userEmployeeID(client, user)
 	if err != nil {
 		return 0, err
 	}
-
-	if len(records) == 0 {
-		return 0, fmt.Errorf("proje bulunamadı: %s", projectName)
-	}
-
-	record := records[0].(map[string]interface{})
-
-	// ID'yi doğru tipte almaya çalış
-	if id, ok := record["id"].(int64); ok {
-		return id, nil
-	}
-
-	// Eğer float64 ise
-	if fid, ok := record["id"].(float64); ok {
-		return int64(fid), nil
-	}
-
-	return 0, fmt.Errorf("geçersiz ID tipi: %T", record["id"])
-}
-
-// Görev ID'sini bul
-func findTaskID(client *xmlrpc.Client, uid int, projectID int64, taskName string) (int64, error) {
-	var records []interface{}
-	err := client.Call("execute_kw", []interface{}{
-		os.Getenv("ODOO_DB"),
-		uid,
-		os.Getenv("ODOO_PASSWORD"),
-		"project.task",
-		"search_read",
-		[]interface{}{
-			[]interface{}{
-				[]interface{}{"name", "ilike", taskName},
-				[]interface{}{"project_id", "=", projectID},
-			},
-		},
-		map[string]interface{}{
-			"fields": []string{"id", "name"},
-			"limit":  1,
-		},
-	}, &records)
-
+	rules, err := validationRulesFromEnv()
 	if err != nil {
 		return 0, err
 	}
+	if err := validateEntry(client, rules, employeeID, entry, 0, time.Now()); err != nil {
+		return 0, err
+	}
 
-	if len(records) == 0 {
-		return 0, fmt.Errorf("görev bulunamadı: %s", taskName)
+	id, err := client.Create(odoo.ModelTimesheet, timesheetValues(employeeID, entry))
+	if err != nil {
+		return 0, fmt.Errorf("zaman kaydı oluşturma hatası: %v", err)
 	}
 
-	record := records[0].(map[string]interface{})
+	return id, nil
+}
 
-	// ID'yi doğru tipte almaya çalış
-	if id, ok := record["id"].(int64); ok {
-		return id, nil
+// Zaman kaydının Odoo'ya yazılacak alanları
+func timesheetValues(employeeID int64, entry NewTimeEntry) odoo.Values {
+	values := odoo.Values{
+		"employee_id": employeeID,
+		"date":        entry.Date,
+		"name":        entry.Description,
+		"unit_amount": entry.Hours,
+		"project_id":  entry.ProjectID,
 	}
 
-	// Eğer float64 ise
-	if fid, ok := record["id"].(float64); ok {
-		return int64(fid), nil
+	if entry.TaskID > 0 {
+		values["task_id"] = entry.TaskID
 	}
-
-	return 0, fmt.Errorf("geçersiz ID tipi: %T", record["id"])
+	return values
 }
 
 // Telegram bot mesajlarını dinle
@@ -335,27 +253,74 @@ func ListenForMessages() {
 	updates := bot.GetUpdatesChan(u)
 
 	for update := range updates {
+		if query := update.CallbackQuery; query != nil {
+			if query.Message == nil {
+				continue
+			}
+			user := authorizeFrom(query.Message.Chat.ID, query.From)
+			if user == nil {
+				log.Printf("Yetkisiz erişim denemesi: sohbet %d, kullanıcı %d", query.Message.Chat.ID, query.From.ID)
+				continue
+			}
+			go handleCallback(query, user)
+			continue
+		}
 		if update.Message == nil {
 			continue
 		}
 
-		// Sadece belirli chat ID'den gelen mesajları işle
-		if update.Message.Chat.ID != chatID {
-			log.Printf("Yetkisiz erişim denemesi: %d", update.Message.Chat.ID)
+		// Sadece kayıtlı kullanıcılardan gelen mesajları işle
+		user := authorize(update.Message)
+		if user == nil {
+			from := int64(0)
+			if update.Message.From != nil {
+				from = update.Message.From.ID
+			}
+			log.Printf("Yetkisiz erişim denemesi: sohbet %d, kullanıcı %d", update.Message.Chat.ID, from)
 			continue
 		}
 
-		go handleMessage(update.Message)
+		go handleMessage(update.Message, user)
 	}
 }
 
+// Mesajı gönderen kullanıcıyı bul. Kullanıcı kaydı boşsa yalnızca
+// TELEGRAM_CHAT_ID sohbeti yönetici olarak yetkilidir.
+func authorize(message *tgbotapi.Message) *BotUser {
+	return authorizeFrom(message.Chat.ID, message.From)
+}
+
+// Sohbet ve gönderen kullanıcıya göre yetkilendir
+func authorizeFrom(chat int64, from *tgbotapi.User) *BotUser {
+	if users == nil || users.Empty() {
+		if chat == chatID {
+			return &BotUser{Telegram: chatID, Role: RoleAdmin}
+		}
+		return nil
+	}
+	if from == nil {
+		return nil
+	}
+	user, ok := users.Lookup(from.ID)
+	if !ok {
+		return nil
+	}
+	return user
+}
+
 // Gelen mesajları işle
-func handleMessage(message *tgbotapi.Message) {
+func handleMessage(message *tgbotapi.Message, user *BotUser) {
 	text := message.Text
+	chat := message.Chat.ID
 
 	// Komut kontrolü
 	if strings.HasPrefix(text, "/") {
-		handleCommand(message)
+		handleCommand(message, user)
+		return
+	}
+
+	// Açık bir zaman kaydı formu varsa mesaj formun yanıtıdır
+	if handleWizardText(message, user) {
 		return
 	}
 
@@ -371,95 +336,681 @@ func handleMessage(message *tgbotapi.Message) {
 
 		hours, err := strconv.ParseFloat(hoursStr, 64)
 		if err != nil {
-			sendTelegramMessage("❌ Geçersiz saat formatı. Lütfen sayısal bir değer girin.")
+			sendTelegramMessage(chat, "❌ Geçersiz saat formatı. Lütfen sayısal bir değer girin.")
 			return
 		}
 
-		err = addTimeEntry(date, project, task, description, hours)
-		if err != nil {
-			sendTelegramMessage(fmt.Sprintf("❌ Zaman kaydı eklenemedi: %v", err))
-			return
+		addTimeEntry(chat, user, date, project, task, description, hours)
+	} else {
+		sendTelegramMessage(chat, "❓ Anlaşılamayan mesaj formatı. Zaman kaydı eklemek için şu formatı kullanın:\n\n`YYYY-MM-DD|Proje|Görev|Açıklama|Saat`\n\nGörev alanı boş bırakılabilir:\n`YYYY-MM-DD|Proje||Açıklama|Saat`")
+	}
+}
+
+// Tek mesajla girilen kaydı Odoo'ya ekle. Kayıt kullanıcının bağlı olduğu
+// çalışan adına, kullanıcının Odoo API anahtarı varsa onun kimliğiyle
+// oluşturulur. Proje ya da görev adı kesin eşleşmezse çözümlenen adlar
+// kullanıcıya onaylatılır; birden fazla aday varsa kayıt yapılmaz.
+func addTimeEntry(chat int64, user *BotUser, date, project, task, description string, hours float64) {
+	client, err := dialAs(user)
+	if err != nil {
+		sendTelegramTo(chat, fmt.Sprintf("❌ Odoo kimlik doğrulama hatası: %v", err))
+		return
+	}
+	defer client.Close()
+
+	entry, err := resolveTimeEntry(client, date, project, task, description, hours)
+	var ambiguous *AmbiguousError
+	if errors.As(err, &ambiguous) {
+		text := fmt.Sprintf("❓ %q birden fazla %s ile eşleşiyor:\n", ambiguous.Query, ambiguous.Kind)
+		for _, c := range ambiguous.Candidates {
+			text += fmt.Sprintf("• %s (#%d)\n", c.Name, c.ID)
 		}
+		sendTelegramTo(chat, text+"\nTam adı ya da #ID yazarak tekrar gönderin.")
+		return
+	}
+	if err != nil {
+		sendTelegramTo(chat, fmt.Sprintf("❌ Zaman kaydı eklenemedi: %v", err))
+		return
+	}
+	if !entry.Exact {
+		confirmEntry(chat, user, entry)
+		return
+	}
 
-		sendTelegramMessage(fmt.Sprintf("✅ Zaman kaydı başarıyla eklendi!\n\n📅 Tarih: %s\n🏢 Proje: %s\n📝 Görev: %s\n📋 Açıklama: %s\n⏱️ Saat: %.2f", date, project, task, description, hours))
-	} else {
-		sendTelegramMessage("❓ Anlaşılamayan mesaj formatı. Zaman kaydı eklemek için şu formatı kullanın:\n\n`YYYY-MM-DD|Proje|Görev|Açıklama|Saat`\n\nGörev alanı boş bırakılabilir:\n`YYYY-MM-DD|Proje||Açıklama|Saat`")
+	if _, err := createTimeEntry(client, user, entry.NewTimeEntry); err != nil {
+		sendTelegramTo(chat, fmt.Sprintf("❌ Zaman kaydı eklenemedi: %v", err))
+		return
 	}
+	sendTelegramTo(chat, "✅ Zaman kaydı başarıyla eklendi!\n\n"+entry.Summary())
+}
+
+// Kullanıcının rolüne göre kullanabileceği komutlar
+func commandList(user *BotUser) string {
+	lines := []string{
+		"/help - Yardım menüsünü gösterir",
+		"/me - Hesap bilgilerinizi gösterir",
+		"/setkey <kullanıcı> <api_anahtarı> - Kayıtları kendi Odoo hesabınızla oluşturur",
+	}
+	scope := "[ekip]"
+	if !user.Can(RoleLead) {
+		scope = ""
+	}
+	lines = append(lines,
+		strings.TrimSpace("/today "+scope)+" - Bugünün raporunu gösterir",
+		strings.TrimSpace("/yesterday "+scope)+" - Dünün raporunu gösterir",
+		strings.TrimSpace("/week "+scope)+" - Bu haftanın raporunu gösterir",
+		strings.TrimSpace("/month "+scope)+" - Bu ayın raporunu gösterir",
+		strings.TrimSpace("/report <dönem> "+scope)+" - İstenen dönemin raporunu gösterir",
+	)
+	if user.Can(RoleLead) {
+		lines = append(lines, "/teams - Tanımlı ekipleri listeler")
+	}
+	if user.Can(RoleAdmin) {
+		lines = append(lines,
+			"/export <biçim> <dönem> [ekip] - Raporu JSON/CSV/XLSX dosyası olarak gönderir",
+			"/users - Bot kullanıcılarını listeler",
+			"/adduser <telegram_id> <rol> <çalışan> - Kullanıcı ekler ya da günceller",
+			"/removeuser <telegram_id> - Kullanıcıyı siler",
+		)
+	}
+	lines = append(lines,
+		"/add - Adım adım zaman kaydı ekler",
+		"/format - Tek mesajla kayıt ekleme formatını gösterir",
+		"/cancel - Açık formu iptal eder",
+		"/entries [gün] - Son kayıtlarınızı ID'leriyle listeler",
+		"/edit <id> <saat|açıklama|görev> <değer> - Kaydı düzenler",
+		"/delete <id> - Kaydı siler",
+		"/start <proje> [görev] - Zamanlayıcıyı başlatır",
+		"/stop [açıklama] - Zamanlayıcıyı durdurup kaydı ekler",
+		"/timer [iptal] - Çalışan zamanlayıcıyı gösterir ya da iptal eder",
+	)
+	return strings.Join(lines, "\n")
 }
 
 // Komutları işle
-func handleCommand(message *tgbotapi.Message) {
+func handleCommand(message *tgbotapi.Message, user *BotUser) {
 	command := message.Command()
+	chat := message.Chat.ID
+	team := strings.TrimSpace(message.CommandArguments())
 
 	switch command {
 	case "start":
-		sendTelegramMessage("👋 Merhaba! Odoo Efor Takip botuna hoş geldiniz.\n\n" +
-			"Komutlar:\n" +
-			"/help - Yardım menüsünü gösterir\n" +
-			"/today - Bugünün raporunu gösterir\n" +
-			"/month - Bu ayın raporunu gösterir\n" +
-			"/add - Zaman kaydı ekleme formatını gösterir")
+		if args := strings.TrimSpace(message.CommandArguments()); args != "" {
+			handleTimerCommand(chat, user, command, args)
+			return
+		}
+		sendTelegramMessage(chat, "👋 Merhaba! Odoo Efor Takip botuna hoş geldiniz.\n\n"+
+			"Komutlar:\n"+commandList(user))
 
 	case "help":
-		sendTelegramMessage("📚 *Yardım Menüsü*\n\n" +
-			"*Komutlar:*\n" +
-			"/today - Bugünün raporunu gösterir\n" +
-			"/month - Bu ayın raporunu gösterir\n" +
-			"/add - Zaman kaydı ekleme formatını gösterir\n\n" +
-			"*Zaman Kaydı Ekleme:*\n" +
-			"Yeni bir zaman kaydı eklemek için şu formatı kullanın:\n" +
-			"`YYYY-MM-DD|Proje|Görev|Açıklama|Saat`\n\n" +
-			"Görev alanı opsiyoneldir, boş bırakabilirsiniz:\n" +
+		sendTelegramMessage(chat, "📚 *Yardım Menüsü*\n\n"+
+			"*Komutlar:*\n"+commandList(user)+"\n\n"+
+			"*Zaman Kaydı Ekleme:*\n"+
+			"Yeni bir zaman kaydı eklemek için şu formatı kullanın:\n"+
+			"`YYYY-MM-DD|Proje|Görev|Açıklama|Saat`\n\n"+
+			"Görev alanı opsiyoneldir, boş bırakabilirsiniz:\n"+
 			"`YYYY-MM-DD|Proje||Açıklama|Saat`")
 
-	case "today":
-		sendTelegramMessage("🔍 Bugünün raporu hazırlanıyor...")
-		go func() {
-			outputFileName, err := generateReport("daily", "", false)
-			if err != nil {
-				sendTelegramMessage(fmt.Sprintf("❌ Rapor oluşturulurken hata oluştu: %v", err))
-				return
+	case "me":
+		text := fmt.Sprintf("👤 *Hesap Bilgileri*\n\nTelegram ID: %d\nRol: %s", user.Telegram, user.Role)
+		if user.Employee != "" || user.EmployeeID > 0 {
+			text += fmt.Sprintf("\nÇalışan: %s", user.Employee)
+			if user.EmployeeID > 0 {
+				text += fmt.Sprintf(" (%d)", user.EmployeeID)
 			}
+		}
+		if len(user.Teams) > 0 {
+			text += "\nEkipler: " + strings.Join(user.Teams, ", ")
+		}
+		if user.HasOdooKey() {
+			text += fmt.Sprintf("\nOdoo hesabı: %s (API anahtarı kayıtlı)", user.OdooLogin)
+		} else {
+			text += "\nOdoo hesabı: servis hesabı (/setkey ile kendi anahtarınızı ekleyin)"
+		}
+		sendTelegramMessage(chat, text)
+
+	case "setkey", "removekey":
+		handleKeyCommand(message, user)
+
+	case "entries", "edit", "delete":
+		handleEntryCommand(chat, user, command, strings.Fields(message.CommandArguments()))
+
+	case "today", "yesterday", "week", "month":
+		spec := map[string]string{
+			"today":     "today",
+			"yesterday": "yesterday",
+			"week":      "this-week",
+			"month":     "this-month",
+		}[command]
+		title := map[string]string{
+			"today":     "Bugünün",
+			"yesterday": "Dünün",
+			"week":      "Bu haftanın",
+			"month":     "Bu ayın",
+		}[command]
+		employee, scopeTeam, err := user.reportScope(team)
+		if err != nil {
+			sendTelegramMessage(chat, fmt.Sprintf("⛔ %v", err))
+			return
+		}
+		period, _ := parsePeriod(spec, time.Now())
+		sendTelegramMessage(chat, fmt.Sprintf("🔍 %s raporu hazırlanıyor...", title))
+		go sendPeriodReport(chat, period, employee, scopeTeam, FormatText, "")
+
+	case "report":
+		args := strings.Fields(message.CommandArguments())
+		if len(args) == 0 {
+			sendTelegramMessage(chat, "📅 *Dönem Raporu*\n\n"+
+				"`/report <dönem> [ekip]`\n\n"+
+				"*Örnekler:*\n"+
+				"`/report last-week`\n"+
+				"`/report 2025-W06 backend`\n"+
+				"`/report 2025-02-01..2025-02-14`\n\n"+
+				"Desteklenen dönemler: "+periodHelp)
+			return
+		}
+		period, err := parsePeriod(args[0], time.Now())
+		if err != nil {
+			sendTelegramMessage(chat, fmt.Sprintf("❌ %v", err))
+			return
+		}
+		employee, scopeTeam, err := user.reportScope(strings.Join(args[1:], " "))
+		if err != nil {
+			sendTelegramMessage(chat, fmt.Sprintf("⛔ %v", err))
+			return
+		}
+		sendTelegramMessage(chat, fmt.Sprintf("🔍 %s dönemi için rapor hazırlanıyor...", period))
+		go sendPeriodReport(chat, period, employee, scopeTeam, FormatText, "")
 
-			reportContent, err := os.ReadFile(outputFileName)
-			if err != nil {
-				sendTelegramMessage(fmt.Sprintf("❌ Rapor dosyası okunamadı: %v", err))
-				return
+	case "export":
+		if !user.Can(RoleAdmin) {
+			sendTelegramMessage(chat, "⛔ Bu komut yalnızca yöneticiler içindir.")
+			return
+		}
+		args := strings.Fields(message.CommandArguments())
+		if len(args) < 2 {
+			sendTelegramMessage(chat, "📎 *Rapor Dışa Aktarma*\n\n"+
+				"`/export <biçim> <dönem> [ekip]`\n\n"+
+				"*Örnekler:*\n"+
+				"`/export xlsx last-month`\n"+
+				"`/export csv 2025-W06 backend`\n\n"+
+				"Desteklenen biçimler: "+strings.Join(exportFormats, ", "))
+			return
+		}
+		format, err := parseFormat(args[0])
+		if err != nil {
+			sendTelegramMessage(chat, fmt.Sprintf("❌ %v", err))
+			return
+		}
+		period, err := parsePeriod(args[1], time.Now())
+		if err != nil {
+			sendTelegramMessage(chat, fmt.Sprintf("❌ %v", err))
+			return
+		}
+		sendTelegramMessage(chat, fmt.Sprintf("🔍 %s dönemi için %s raporu hazırlanıyor...", period, format))
+		go sendPeriodReport(chat, period, "", strings.Join(args[2:], " "), format, "")
+
+	case "teams":
+		if !user.Can(RoleLead) {
+			sendTelegramMessage(chat, "⛔ Bu komut yalnızca ekip liderleri ve yöneticiler içindir.")
+			return
+		}
+		cfg, err := loadRosterConfig()
+		if err != nil {
+			sendTelegramMessage(chat, fmt.Sprintf("❌ Ekipler okunamadı: %v", err))
+			return
+		}
+		if len(cfg.Teams) == 0 {
+			sendTelegramMessage(chat, "ℹ️ Tanımlı ekip yok, raporlar tüm çalışanları kapsar.")
+			return
+		}
+		text := "👥 *Ekipler*\n\n"
+		for _, name := range cfg.TeamNames() {
+			if strings.EqualFold(name, cfg.DefaultTeam) {
+				text += fmt.Sprintf("• %s (varsayılan)\n", name)
+			} else {
+				text += fmt.Sprintf("• %s\n", name)
 			}
+		}
+		sendTelegramMessage(chat, text)
+
+	case "users", "adduser", "removeuser":
+		if !user.Can(RoleAdmin) {
+			sendTelegramMessage(chat, "⛔ Bu komut yalnızca yöneticiler içindir.")
+			return
+		}
+		handleUserCommand(chat, command, strings.Fields(message.CommandArguments()))
+
+	case "stop", "timer":
+		handleTimerCommand(chat, user, command, strings.TrimSpace(message.CommandArguments()))
+
+	case "add":
+		startWizard(chat, user)
+
+	case "format":
+		sendTelegramMessage(chat, "➕ *Zaman Kaydı Ekleme*\n\n"+
+			"Yeni bir zaman kaydı eklemek için şu formatı kullanın:\n"+
+			"`YYYY-MM-DD|Proje|Görev|Açıklama|Saat`\n\n"+
+			"*Örnek:*\n"+
+			"`2025-02-07|TEKNOSA|CX-7006|Geliştirme yapıldı|3.5`\n\n"+
+			"Görev alanı opsiyoneldir, boş bırakabilirsiniz:\n"+
+			"`2025-02-07|TEKNOSA||Geliştirme yapıldı|3.5`")
+
+	case "cancel":
+		if endWizard(wizardKey{chat, user.Telegram}) {
+			sendTelegramMessage(chat, "🚫 Zaman kaydı iptal edildi.")
+		} else {
+			sendTelegramMessage(chat, "ℹ️ İptal edilecek açık bir işlem yok.")
+		}
+
+	default:
+		sendTelegramMessage(chat, "❓ Bilinmeyen komut. Yardım için /help yazın.")
+	}
+}
 
-			summary := generateSummary(string(reportContent))
-			sendTelegramMessage(summary)
-		}()
+// Zamanlayıcı komutları. Zamanlayıcı kullanıcıya özeldir ve dosyada
+// saklandığından bot yeniden başlatılsa da çalışmaya devam eder; durdurulunca
+// kayıt diğer kayıtlarla aynı doğrulama ve oluşturma yolundan geçer.
+func handleTimerCommand(chat int64, user *BotUser, command, args string) {
+	owner := telegramTimerOwner(user)
 
-	case "month":
-		sendTelegramMessage("🔍 Bu ayın raporu hazırlanıyor...")
-		go func() {
-			outputFileName, err := generateReport("", "", false)
+	switch command {
+	case "start":
+		client, err := dialAs(user)
+		if err != nil {
+			sendTelegramTo(chat, fmt.Sprintf("❌ Odoo kimlik doğrulama hatası: %v", err))
+			return
+		}
+		defer client.Close()
+
+		if _, _, err := timerSettings(); err != nil {
+			sendTelegramTo(chat, fmt.Sprintf("❌ Zamanlayıcı başlatılamadı: %v", err))
+			return
+		}
+		timer, err := parseTimerArgs(client, args)
+		if err != nil {
+			sendTelegramTo(chat, fmt.Sprintf("❌ Zamanlayıcı başlatılamadı: %v", err))
+			return
+		}
+		if err := startTimer(owner, *timer); err != nil {
+			sendTelegramTo(chat, fmt.Sprintf("❌ %v\n\n/stop ile durdurun ya da /timer iptal ile iptal edin.", err))
+			return
+		}
+		sendTelegramTo(chat, fmt.Sprintf("▶️ Zamanlayıcı başladı: %s\n\nBitirince /stop [açıklama] yazın.", timer.label()))
+
+	case "stop":
+		running, err := runningTimer(owner)
+		if err != nil {
+			sendTelegramTo(chat, fmt.Sprintf("❌ %v", err))
+			return
+		}
+		if running == nil {
+			sendTelegramTo(chat, "ℹ️ Çalışan bir zamanlayıcı yok. /start <proje> [görev] ile başlatın.")
+			return
+		}
+		client, err := dialAs(user)
+		if err != nil {
+			sendTelegramTo(chat, fmt.Sprintf("❌ Odoo kimlik doğrulama hatası: %v", err))
+			return
+		}
+		defer client.Close()
+
+		entry, capped, err := stopTimer(client, user, owner, args)
+		if err != nil {
+			sendTelegramTo(chat, fmt.Sprintf("❌ Zaman kaydı eklenemedi, zamanlayıcı çalışmaya devam ediyor: %v\n\n"+
+				"Açıklama hatalıysa /stop <açıklama> ile tekrar deneyin. Kayıt eklemeden durdurmak için /timer iptal yazın, "+
+				"sonra kaydı /add ile girin.", err))
+			return
+		}
+		text := "⏹️ Zamanlayıcı durdu, zaman kaydı eklendi!\n\n" + entry.Summary()
+		if capped {
+			text += fmt.Sprintf("\n\n⚠️ Süre kayıt başına en fazla saate indirildi (%.2f saat); gerekirse /edit ile düzeltin.", entry.Hours)
+		}
+		sendTelegramTo(chat, text)
+
+	case "timer":
+		if strings.EqualFold(args, "iptal") || strings.EqualFold(args, "cancel") {
+			timer, err := takeTimer(owner)
 			if err != nil {
-				sendTelegramMessage(fmt.Sprintf("❌ Rapor oluşturulurken hata oluştu: %v", err))
-				return
+				sendTelegramTo(chat, fmt.Sprintf("❌ %v", err))
+			} else if timer == nil {
+				sendTelegramTo(chat, "ℹ️ Çalışan bir zamanlayıcı yok.")
+			} else {
+				sendTelegramTo(chat, fmt.Sprintf("🚫 Zamanlayıcı kayıt eklenmeden iptal edildi: %s", timer.label()))
 			}
+			return
+		}
+		timer, err := runningTimer(owner)
+		if err != nil {
+			sendTelegramTo(chat, fmt.Sprintf("❌ %v", err))
+		} else if timer == nil {
+			sendTelegramTo(chat, "ℹ️ Çalışan bir zamanlayıcı yok. /start <proje> [görev] ile başlatın.")
+		} else {
+			sendTelegramTo(chat, timer.Status(timerNow()))
+		}
+	}
+}
 
-			reportContent, err := os.ReadFile(outputFileName)
-			if err != nil {
-				sendTelegramMessage(fmt.Sprintf("❌ Rapor dosyası okunamadı: %v", err))
+// Kullanıcı yönetimi komutları (yalnızca yöneticiler)
+func handleUserCommand(chat int64, command string, args []string) {
+	if users == nil {
+		sendTelegramMessage(chat, "❌ Kullanıcı kaydı yüklenmemiş.")
+		return
+	}
+
+	switch command {
+	case "users":
+		list := users.List()
+		if len(list) == 0 {
+			sendTelegramMessage(chat, "ℹ️ Kayıtlı kullanıcı yok.")
+			return
+		}
+		text := "👥 *Kullanıcılar*\n\n"
+		for _, u := range list {
+			text += fmt.Sprintf("• %d - %s (%s)", u.Telegram, u.Employee, u.Role)
+			if len(u.Teams) > 0 {
+				text += " - " + strings.Join(u.Teams, ", ")
+			}
+			text += "\n"
+		}
+		sendTelegramMessage(chat, text)
+
+	case "adduser":
+		if len(args) < 3 {
+			sendTelegramMessage(chat, "👤 *Kullanıcı Ekleme*\n\n"+
+				"`/adduser <telegram_id> <rol> <çalışan>`\n\n"+
+				"Roller: member, lead, admin. Liderler için ekipler `ekip=backend,qa` ile verilir:\n"+
+				"`/adduser 123456789 lead Ayşe Yılmaz ekip=backend`")
+			return
+		}
+		telegramID, err := strconv.ParseInt(args[0], 10, 64)
+		if err != nil {
+			sendTelegramMessage(chat, "❌ Geçersiz Telegram kullanıcı ID'si.")
+			return
+		}
+		u := BotUser{Telegram: telegramID, Role: strings.ToLower(args[1])}
+		var name []string
+		for _, arg := range args[2:] {
+			if teams, ok := strings.CutPrefix(arg, "ekip="); ok {
+				u.Teams = strings.Split(teams, ",")
+				continue
+			}
+			name = append(name, arg)
+		}
+
+		client, err := authenticateOdoo()
+		if err != nil {
+			sendTelegramMessage(chat, fmt.Sprintf("❌ Odoo kimlik doğrulama hatası: %v", err))
+			return
+		}
+		defer client.Close()
+		emp, err := resolveEmployee(client, strings.Join(name, " "))
+		if err != nil {
+			sendTelegramMessage(chat, fmt.Sprintf("❌ %v", err))
+			return
+		}
+		u.Employee, u.EmployeeID = emp.Name, emp.ID
+
+		if err := users.Put(u); err != nil {
+			sendTelegramMessage(chat, fmt.Sprintf("❌ Kullanıcı kaydedilemedi: %v", err))
+			return
+		}
+		sendTelegramMessage(chat, fmt.Sprintf("✅ %d, %s olarak %s rolüyle kaydedildi.", u.Telegram, u.Employee, u.Role))
+
+	case "removeuser":
+		if len(args) != 1 {
+			sendTelegramMessage(chat, "`/removeuser <telegram_id>`")
+			return
+		}
+		telegramID, err := strconv.ParseInt(args[0], 10, 64)
+		if err != nil {
+			sendTelegramMessage(chat, "❌ Geçersiz Telegram kullanıcı ID'si.")
+			return
+		}
+		if err := users.Remove(telegramID); err != nil {
+			sendTelegramMessage(chat, fmt.Sprintf("❌ %v", err))
+			return
+		}
+		sendTelegramMessage(chat, fmt.Sprintf("✅ %d silindi.", telegramID))
+	}
+}
+
+// Kullanıcının Odoo API anahtarını kaydet ya da sil. Anahtar yalnızca özel
+// sohbette kabul edilir, doğrulanır, şifrelenerek saklanır ve anahtarı
+// içeren mesaj sohbetten silinir.
+func handleKeyCommand(message *tgbotapi.Message, user *BotUser) {
+	chat := message.Chat.ID
+	if users == nil || users.Empty() {
+		sendTelegramMessage(chat, "❌ API anahtarı için önce bot kullanıcısı olarak kaydedilmelisiniz.")
+		return
+	}
+
+	if message.Command() == "removekey" {
+		user.OdooLogin, user.OdooKey = "", ""
+		if err := users.Put(*user); err != nil {
+			sendTelegramMessage(chat, fmt.Sprintf("❌ Kullanıcı kaydedilemedi: %v", err))
+			return
+		}
+		sendTelegramMessage(chat, "✅ Odoo API anahtarınız silindi, kayıtlar servis hesabıyla oluşturulacak.")
+		return
+	}
+
+	if !message.Chat.IsPrivate() {
+		bot.Request(tgbotapi.NewDeleteMessage(chat, message.MessageID))
+		sendTelegramMessage(chat, "⛔ API anahtarını yalnızca bota özel mesajla gönderin. Bu mesajdaki anahtarı Odoo'dan iptal etmeniz önerilir.")
+		return
+	}
+	args := strings.Fields(message.CommandArguments())
+	if len(args) != 2 {
+		sendTelegramMessage(chat, "🔑 *Odoo API Anahtarı*\n\n"+
+			"`/setkey <kullanıcı> <api_anahtarı>`\n\n"+
+			"Anahtarı Odoo'da Tercihler > Hesap Güvenliği > API Anahtarları bölümünden oluşturabilirsiniz. "+
+			"Anahtar şifrelenerek saklanır ve mesajınız silinir.")
+		return
+	}
+
+	// Anahtarı içeren mesajı sohbette bırakma
+	if _, err := bot.Request(tgbotapi.NewDeleteMessage(chat, message.MessageID)); err != nil {
+		log.Printf("API anahtarı mesajı silinemedi: %v", err)
+	}
+
+	cfg := odoo.ConfigFromEnv()
+	cfg.Username, cfg.Password = args[0], args[1]
+	client, err := odoo.Dial(cfg)
+	if err != nil {
+		sendTelegramMessage(chat, fmt.Sprintf("❌ Odoo'ya bu bilgilerle giriş yapılamadı: %v", err))
+		return
+	}
+	client.Close()
+
+	if err := user.SetOdooKey(args[0], args[1]); err != nil {
+		sendTelegramMessage(chat, fmt.Sprintf("❌ Anahtar şifrelenemedi: %v", err))
+		return
+	}
+	if err := users.Put(*user); err != nil {
+		sendTelegramMessage(chat, fmt.Sprintf("❌ Kullanıcı kaydedilemedi: %v", err))
+		return
+	}
+	sendTelegramMessage(chat, fmt.Sprintf("✅ Odoo API anahtarınız kaydedildi. Kayıtlar artık %s hesabıyla oluşturulacak.", args[0]))
+}
+
+// Satır içi klavye düğmelerini işle
+func handleCallback(query *tgbotapi.CallbackQuery, user *BotUser) {
+	if strings.HasPrefix(query.Data, wizardCallbackPrefix) {
+		handleWizardCallback(query, user)
+		return
+	}
+	if strings.HasPrefix(query.Data, entryCallbackPrefix) {
+		handleEntryCallback(query, user)
+		return
+	}
+	bot.Request(tgbotapi.NewCallback(query.ID, "Bilinmeyen işlem"))
+}
+
+// Geri çağrı verilerinin öneki ("entry:<ok|cancel>:<id>")
+const entryCallbackPrefix = "entry:"
+
+// Onay bekleyen düzenleme ya da silme işlemi
+type pendingEntryAction struct {
+	id      int64
+	changes *EntryChanges // nil ise kayıt silinir
+}
+
+var (
+	pendingMu      sync.Mutex
+	pendingActions = map[wizardKey]*pendingEntryAction{}
+)
+
+// Kayıt listeleme, düzenleme ve silme komutları. Değişiklikler onaydan
+// sonra uygulanır.
+func handleEntryCommand(chat int64, user *BotUser, command string, args []string) {
+	client, err := dialAs(user)
+	if err != nil {
+		sendTelegramTo(chat, fmt.Sprintf("❌ Odoo kimlik doğrulama hatası: %v", err))
+		return
+	}
+	defer client.Close()
+
+	switch command {
+	case "entries":
+		days := recentEntryDays
+		if len(args) > 0 {
+			if days, err = strconv.Atoi(args[0]); err != nil || days <= 0 {
+				sendTelegramTo(chat, "❌ Gün sayısı pozitif bir tam sayı olmalı.")
 				return
 			}
+		}
+		employeeID, err := userEmployeeID(client, user)
+		if err != nil {
+			sendTelegramTo(chat, fmt.Sprintf("❌ %v", err))
+			return
+		}
+		lines, err := recentEntries(client, employeeID, days, time.Now())
+		if err != nil {
+			sendTelegramTo(chat, fmt.Sprintf("❌ Kayıtlar okunamadı: %v", err))
+			return
+		}
+		if len(lines) == 0 {
+			sendTelegramTo(chat, fmt.Sprintf("ℹ️ Son %d günde kaydınız yok.", days))
+			return
+		}
+		text := fmt.Sprintf("🗂 Son %d gündeki kayıtlarınız:\n\n", days)
+		for _, line := range lines {
+			text += formatEntry(line) + "\n"
+		}
+		sendTelegramTo(chat, text+"\nDüzenlemek için /edit <id> <saat|açıklama|görev> <değer>, silmek için /delete <id>")
+		return
 
-			summary := generateSummary(string(reportContent))
-			sendTelegramMessage(summary)
-		}()
+	case "edit":
+		if len(args) < 3 {
+			sendTelegramTo(chat, "✏️ Kullanım: /edit <id> <saat|açıklama|görev> <değer>\n\nÖrnek: /edit 123 saat 2.5\nGörevi kaldırmak için: /edit 123 görev -")
+			return
+		}
+	case "delete":
+		if len(args) != 1 {
+			sendTelegramTo(chat, "🗑 Kullanım: /delete <id>")
+			return
+		}
+	}
 
-	case "add":
-		sendTelegramMessage("➕ *Zaman Kaydı Ekleme*\n\n" +
-			"Yeni bir zaman kaydı eklemek için şu formatı kullanın:\n" +
-			"`YYYY-MM-DD|Proje|Görev|Açıklama|Saat`\n\n" +
-			"*Örnek:*\n" +
-			"`2025-02-07|TEKNOSA|CX-7006|Geliştirme yapıldı|3.5`\n\n" +
-			"Görev alanı opsiyoneldir, boş bırakabilirsiniz:\n" +
-			"`2025-02-07|TEKNOSA||Geliştirme yapıldı|3.5`")
+	id, err := strconv.ParseInt(strings.TrimPrefix(args[0], "#"), 10, 64)
+	if err != nil {
+		sendTelegramTo(chat, fmt.Sprintf("❌ Geçersiz kayıt ID'si: %s", args[0]))
+		return
+	}
+	line, err := loadEditableEntry(client, user, id)
+	if err != nil {
+		sendTelegramTo(chat, fmt.Sprintf("⛔ %v", err))
+		return
+	}
 
-	default:
-		sendTelegramMessage("❓ Bilinmeyen komut. Yardım için /help yazın.")
+	action := &pendingEntryAction{id: id}
+	prompt := "🗑 Bu kayıt silinsin mi?\n\n" + formatEntry(*line)
+	if command == "edit" {
+		changes, err := parseEntryChange(args[1], strings.Join(args[2:], " "))
+		if err != nil {
+			sendTelegramTo(chat, fmt.Sprintf("❌ %v", err))
+			return
+		}
+		_, notes, err := entryChangeValues(client, line, changes)
+		if err != nil {
+			sendTelegramTo(chat, fmt.Sprintf("❌ %v", err))
+			return
+		}
+		action.changes = &changes
+		prompt = "✏️ Kayıt güncellensin mi?\n\n" + formatEntry(*line) + "\n\n" + strings.Join(notes, "\n")
+	}
+
+	pendingMu.Lock()
+	pendingActions[wizardKey{chat, user.Telegram}] = action
+	pendingMu.Unlock()
+	idStr := strconv.FormatInt(id, 10)
+	sendTelegramKeyboard(chat, prompt, tgbotapi.NewInlineKeyboardMarkup(
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData("✅ Onayla", entryCallbackPrefix+"ok:"+idStr),
+			tgbotapi.NewInlineKeyboardButtonData("❌ Vazgeç", entryCallbackPrefix+"cancel:"+idStr),
+		),
+	))
+}
+
+// Düzenleme ve silme onayını işle
+func handleEntryCallback(query *tgbotapi.CallbackQuery, user *BotUser) {
+	chat := query.Message.Chat.ID
+	bot.Request(tgbotapi.NewEditMessageReplyMarkup(chat, query.Message.MessageID,
+		tgbotapi.InlineKeyboardMarkup{InlineKeyboard: [][]tgbotapi.InlineKeyboardButton{}}))
+
+	decision, idStr, _ := strings.Cut(strings.TrimPrefix(query.Data, entryCallbackPrefix), ":")
+	key := wizardKey{chat, user.Telegram}
+	pendingMu.Lock()
+	action := pendingActions[key]
+	if action != nil && strconv.FormatInt(action.id, 10) == idStr {
+		delete(pendingActions, key)
+	} else {
+		action = nil
+	}
+	pendingMu.Unlock()
+	if action == nil {
+		bot.Request(tgbotapi.NewCallback(query.ID, "Bu işlem artık geçerli değil."))
+		return
+	}
+	bot.Request(tgbotapi.NewCallback(query.ID, ""))
+
+	if decision != "ok" {
+		sendTelegramTo(chat, "🚫 İşlem iptal edildi.")
+		return
+	}
+
+	client, err := dialAs(user)
+	if err != nil {
+		sendTelegramTo(chat, fmt.Sprintf("❌ Odoo kimlik doğrulama hatası: %v", err))
+		return
+	}
+	defer client.Close()
+
+	// Onay beklerken kayıt kilitlenmiş olabilir
+	line, err := loadEditableEntry(client, user, action.id)
+	if err != nil {
+		sendTelegramTo(chat, fmt.Sprintf("⛔ %v", err))
+		return
+	}
+	if action.changes == nil {
+		if err := client.Unlink(odoo.ModelTimesheet, []int64{action.id}); err != nil {
+			sendTelegramTo(chat, fmt.Sprintf("❌ Kayıt silinemedi: %v", err))
+			return
+		}
+		sendTelegramTo(chat, fmt.Sprintf("✅ #%d silindi.", action.id))
+		return
+	}
+	// Günlük toplam ya da proje/görev durumu onay beklerken değişmiş
+	// olabilir; değişiklik kaydın güncel haline göre yeniden doğrulanır
+	values, _, err := entryChangeValues(client, line, *action.changes)
+	if err != nil {
+		sendTelegramTo(chat, fmt.Sprintf("❌ %v", err))
+		return
+	}
+	if err := client.Write(odoo.ModelTimesheet, []int64{action.id}, values); err != nil {
+		sendTelegramTo(chat, fmt.Sprintf("❌ Kayıt güncellenemedi: %v", err))
+		return
 	}
+	sendTelegramTo(chat, fmt.Sprintf("✅ #%d güncellendi.", action.id))
 }
@@ -0,0 +1,551 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"odoo-efor-tracker/odoo"
+	"odoo-efor-tracker/odoo/odootest"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+const testChatID = 42
+
+// Sahte Telegram Bot API'si; gönderilen mesajları saklar
+type fakeTelegram struct {
+	mu       sync.Mutex
+	messages []string
+	chats    []int64
+	markups  []string // Mesajla gönderilen reply_markup (JSON)
+}
+
+func (f *fakeTelegram) sent() []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]string(nil), f.messages...)
+}
+
+// Verilen sohbete gönderilen mesajlar
+func (f *fakeTelegram) sentTo(chat int64) []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	var messages []string
+	for i, c := range f.chats {
+		if c == chat {
+			messages = append(messages, f.messages[i])
+		}
+	}
+	return messages
+}
+
+// Sohbete gönderilen son satır içi klavyenin düğmeleri (metin -> veri)
+func (f *fakeTelegram) lastKeyboard(chat int64) map[string]string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for i := len(f.chats) - 1; i >= 0; i-- {
+		if f.chats[i] != chat || f.markups[i] == "" {
+			continue
+		}
+		var markup tgbotapi.InlineKeyboardMarkup
+		if err := json.Unmarshal([]byte(f.markups[i]), &markup); err != nil {
+			return nil
+		}
+		buttons := make(map[string]string)
+		for _, row := range markup.InlineKeyboard {
+			for _, b := range row {
+				buttons[b.Text] = *b.CallbackData
+			}
+		}
+		return buttons
+	}
+	return nil
+}
+
+func newFakeTelegram(t *testing.T) *fakeTelegram {
+	t.Helper()
+	fake := &fakeTelegram{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch path.Base(r.URL.Path) {
+		case "getMe":
+			fmt.Fprint(w, `{"ok":true,"result":{"id":1,"is_bot":true,"first_name":"Test","username":"test_bot"}}`)
+		case "sendMessage":
+			r.ParseForm()
+			fake.mu.Lock()
+			fake.messages = append(fake.messages, r.PostForm.Get("text"))
+			chat, _ := strconv.ParseInt(r.PostForm.Get("chat_id"), 10, 64)
+			fake.chats = append(fake.chats, chat)
+			fake.markups = append(fake.markups, r.PostForm.Get("reply_markup"))
+			fake.mu.Unlock()
+			fmt.Fprintf(w, `{"ok":true,"result":{"message_id":1,"date":0,"chat":{"id":%d,"type":"private"}}}`, testChatID)
+		default:
+			fmt.Fprint(w, `{"ok":true,"result":true}`)
+		}
+	}))
+	t.Cleanup(srv.Close)
+
+	var err error
+	bot, err = tgbotapi.NewBotAPIWithAPIEndpoint("TEST", srv.URL+"/bot%s/%s")
+	if err != nil {
+		t.Fatalf("sahte Telegram botu başlatılamadı: %v", err)
+	}
+	chatID = testChatID
+	users = &UserRegistry{}
+	return fake
+}
+
+// testdata/users.yaml'ın geçici bir kopyasını kullanıcı kaydı olarak yükle
+func loadTestUsers(t *testing.T) {
+	t.Helper()
+	data, err := os.ReadFile("testdata/users.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(t.TempDir(), "users.yaml")
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("USERS_FILE", path)
+	if users, err = loadUserRegistry(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func textMessage(text string) *tgbotapi.Message {
+	return userMessage(testChatID, text)
+}
+
+// Kullanıcının botla özel sohbetinden gelen mesaj
+func userMessage(from int64, text string) *tgbotapi.Message {
+	msg := &tgbotapi.Message{
+		Text: text,
+		Chat: &tgbotapi.Chat{ID: from, Type: "private"},
+		From: &tgbotapi.User{ID: from},
+	}
+	if strings.HasPrefix(text, "/") {
+		command, _, _ := strings.Cut(text, " ")
+		msg.Entities = []tgbotapi.MessageEntity{{Type: "bot_command", Offset: 0, Length: len(command)}}
+	}
+	return msg
+}
+
+// Kullanıcı kaydı boşken TELEGRAM_CHAT_ID sohbeti yöneticidir
+var legacyAdmin = &BotUser{Telegram: testChatID, Role: RoleAdmin}
+
+func TestHandleMessageAddsTimeEntry(t *testing.T) {
+	srv := newFakeOdoo(t)
+	tg := newFakeTelegram(t)
+
+	handleMessage(textMessage("2025-02-07|TEKNOSA|CX-7006|Geliştirme yapıldı|3.5"), legacyAdmin)
+
+	records := srv.Records(odoo.ModelTimesheet)
+	created := records[len(records)-1]
+	if created["name"] != "Geliştirme yapıldı" || created["date"] != "2025-02-07" || created["unit_amount"] != 3.5 {
+		t.Fatalf("beklenmeyen kayıt: %v", created)
+	}
+	if task, _ := created["task_id"].([]interface{}); len(task) < 2 || task[1] != "CX-7006" {
+		t.Errorf("görev eşleşmedi: %v", created["task_id"])
+	}
+
+	sent := tg.sent()
+	if len(sent) != 1 || !strings.HasPrefix(sent[0], "✅") {
+		t.Errorf("başarı mesajı gönderilmedi: %q", sent)
+	}
+}
+
+func TestHandleMessageUnknownProject(t *testing.T) {
+	srv := newFakeOdoo(t)
+	tg := newFakeTelegram(t)
+	before := len(srv.Records(odoo.ModelTimesheet))
+
+	handleMessage(textMessage("2025-02-07|OLMAYAN PROJE||Açıklama|2"), legacyAdmin)
+
+	if after := len(srv.Records(odoo.ModelTimesheet)); after != before {
+		t.Errorf("bilinmeyen proje için kayıt oluşturuldu")
+	}
+	sent := tg.sent()
+	if len(sent) != 1 || !strings.Contains(sent[0], "proje bulunamadı") {
+		t.Errorf("hata mesajı gönderilmedi: %q", sent)
+	}
+}
+
+func TestAuthorize(t *testing.T) {
+	newFakeTelegram(t)
+
+	// Kayıt boşken yalnızca ana sohbet yetkilidir
+	if user := authorize(textMessage("/help")); user == nil || user.Role != RoleAdmin {
+		t.Errorf("ana sohbet yetkilendirilmedi: %+v", user)
+	}
+	if user := authorize(userMessage(3003, "/help")); user != nil {
+		t.Errorf("kayıt boşken yabancı kullanıcı yetkilendirildi: %+v", user)
+	}
+
+	loadTestUsers(t)
+	if user := authorize(userMessage(3003, "/help")); user == nil || user.Role != RoleMember || user.EmployeeID != 3 {
+		t.Errorf("kayıtlı üye = %+v", user)
+	}
+	// Kayıt doluyken ana sohbetteki kayıtsız kullanıcılar da reddedilir
+	if user := authorize(textMessage("/help")); user != nil {
+		t.Errorf("kayıtsız kullanıcı yetkilendirildi: %+v", user)
+	}
+}
+
+func TestReportScope(t *testing.T) {
+	newFakeTelegram(t)
+	loadTestUsers(t)
+
+	for _, tt := range []struct {
+		user           int64
+		arg            string
+		employee, want string
+		err            bool
+	}{
+		{user: 3003, arg: "", employee: "3"},
+		{user: 3003, arg: "ekip", employee: "3"}, // üye ekip isteyemez, kendi raporu döner
+		{user: 2002, arg: "", want: "qa"},
+		{user: 2002, arg: "QA", want: "QA"},
+		{user: 2002, arg: "ekip", err: true},
+		{user: 1001, arg: "ekip", want: "ekip"},
+	} {
+		u, _ := users.Lookup(tt.user)
+		employee, team, err := u.reportScope(tt.arg)
+		if (err != nil) != tt.err || employee != tt.employee || team != tt.want {
+			t.Errorf("%d %q: reportScope = %q, %q, %v", tt.user, tt.arg, employee, team, err)
+		}
+	}
+}
+
+func TestHandleMessageAddsEntryAsUser(t *testing.T) {
+	srv := newFakeOdoo(t)
+	tg := newFakeTelegram(t)
+	loadTestUsers(t)
+
+	msg := userMessage(3003, "2025-02-07|TEKNOSA||Analiz|2")
+	handleMessage(msg, authorize(msg))
+
+	records := srv.Records(odoo.ModelTimesheet)
+	created := records[len(records)-1]
+	if emp, _ := created["employee_id"].([]interface{}); len(emp) < 2 || emp[1] != "Fatih Delice" {
+		t.Errorf("kayıt kullanıcının çalışanına yazılmadı: %v", created["employee_id"])
+	}
+	if sent := tg.sentTo(3003); len(sent) != 1 || !strings.HasPrefix(sent[0], "✅") {
+		t.Errorf("yanıt kullanıcıya gönderilmedi: %q", tg.sent())
+	}
+}
+
+func TestCommandPermissions(t *testing.T) {
+	newFakeOdoo(t)
+	tg := newFakeTelegram(t)
+	loadTestUsers(t)
+
+	for _, tt := range []struct {
+		user int64
+		text string
+	}{
+		{3003, "/export csv last-week"},
+		{3003, "/users"},
+		{3003, "/teams"},
+		{2002, "/adduser 4004 member Harici Danışman"},
+		{2002, "/today ekip"},
+	} {
+		msg := userMessage(tt.user, tt.text)
+		handleMessage(msg, authorize(msg))
+		sent := tg.sentTo(tt.user)
+		if len(sent) == 0 || !strings.HasPrefix(sent[len(sent)-1], "⛔") {
+			t.Errorf("%d %q reddedilmedi: %q", tt.user, tt.text, sent)
+		}
+	}
+
+	// Üyenin yardım menüsünde yönetici komutları yer almaz
+	msg := userMessage(3003, "/help")
+	handleMessage(msg, authorize(msg))
+	if sent := tg.sentTo(3003); strings.Contains(sent[len(sent)-1], "/export") {
+		t.Errorf("üye yardımında /export var: %q", sent[len(sent)-1])
+	}
+}
+
+func TestAdminManagesUsers(t *testing.T) {
+	newFakeOdoo(t)
+	tg := newFakeTelegram(t)
+	loadTestUsers(t)
+
+	msg := userMessage(1001, "/adduser 4004 lead Harici Danışman ekip=qa,backend")
+	handleMessage(msg, authorize(msg))
+	if sent := tg.sentTo(1001); len(sent) != 1 || !strings.HasPrefix(sent[0], "✅") {
+		t.Fatalf("kullanıcı eklenemedi: %q", sent)
+	}
+
+	// Değişiklik dosyaya yazılır
+	reloaded, err := loadUserRegistry()
+	if err != nil {
+		t.Fatal(err)
+	}
+	u, ok := reloaded.Lookup(4004)
+	if !ok || u.EmployeeID != 4 || u.Role != RoleLead || strings.Join(u.Teams, ",") != "qa,backend" {
+		t.Errorf("eklenen kullanıcı = %+v", u)
+	}
+
+	msg = userMessage(1001, "/removeuser 4004")
+	handleMessage(msg, authorize(msg))
+	if reloaded, err = loadUserRegistry(); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := reloaded.Lookup(4004); ok {
+		t.Error("kullanıcı silinmedi")
+	}
+}
+
+func TestSetKeyCreatesEntriesAsUser(t *testing.T) {
+	srv := newFakeOdoo(t)
+	tg := newFakeTelegram(t)
+	loadTestUsers(t)
+	t.Setenv("BOT_SECRET_KEY", testSecretKey)
+
+	// Yanlış anahtar kaydedilmez
+	msg := userMessage(2002, "/setkey aysegul@example.com yanlis")
+	handleMessage(msg, authorize(msg))
+	if sent := tg.sentTo(2002); len(sent) != 1 || !strings.HasPrefix(sent[0], "❌") {
+		t.Fatalf("yanlış anahtar reddedilmedi: %q", sent)
+	}
+	if u, _ := users.Lookup(2002); u.HasOdooKey() {
+		t.Fatal("yanlış anahtar kaydedildi")
+	}
+
+	msg = userMessage(2002, "/setkey aysegul@example.com aysegul-api-key")
+	handleMessage(msg, authorize(msg))
+	if sent := tg.sentTo(2002); len(sent) != 2 || !strings.HasPrefix(sent[1], "✅") {
+		t.Fatalf("anahtar kaydedilmedi: %q", sent)
+	}
+	data, err := os.ReadFile(os.Getenv("USERS_FILE"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(data), "aysegul-api-key") || !strings.Contains(string(data), "odoo_key: "+secretPrefix) {
+		t.Errorf("anahtar şifrelenmeden saklandı:\n%s", data)
+	}
+
+	msg = userMessage(2002, "2025-02-07|TEKNOSA||Test|1.5")
+	handleMessage(msg, authorize(msg))
+
+	calls := srv.Calls()
+	var create *odootest.Call
+	for i := range calls {
+		if calls[i].Model == odoo.ModelTimesheet && calls[i].Method == "create" {
+			create = &calls[i]
+		}
+	}
+	if create == nil || create.UID != 7 {
+		t.Fatalf("kayıt kullanıcının Odoo hesabıyla oluşturulmadı: %+v", create)
+	}
+	records := srv.Records(odoo.ModelTimesheet)
+	created := records[len(records)-1]
+	if emp, _ := created["employee_id"].([]interface{}); len(emp) < 2 || emp[1] != "Ayşegül Şahin" {
+		t.Errorf("kayıt kullanıcının çalışanına yazılmadı: %v", created["employee_id"])
+	}
+}
+
+func TestSetKeyRequiresPrivateChat(t *testing.T) {
+	newFakeOdoo(t)
+	tg := newFakeTelegram(t)
+	loadTestUsers(t)
+	t.Setenv("BOT_SECRET_KEY", testSecretKey)
+
+	msg := userMessage(2002, "/setkey aysegul@example.com aysegul-api-key")
+	msg.Chat = &tgbotapi.Chat{ID: -100, Type: "group"}
+	handleMessage(msg, authorize(msg))
+	if sent := tg.sentTo(-100); len(sent) != 1 || !strings.HasPrefix(sent[0], "⛔") {
+		t.Errorf("grup sohbetinde anahtar reddedilmedi: %q", sent)
+	}
+	if u, _ := users.Lookup(2002); u.HasOdooKey() {
+		t.Error("grup sohbetinden gelen anahtar kaydedildi")
+	}
+}
+
+func TestHandleMessageAmbiguousTask(t *testing.T) {
+	srv := newFakeOdoo(t)
+	tg := newFakeTelegram(t)
+	before := len(srv.Records(odoo.ModelTimesheet))
+
+	handleMessage(textMessage("2025-02-07|TEKNOSA|CX-70|Geliştirme|2"), legacyAdmin)
+
+	if len(srv.Records(odoo.ModelTimesheet)) != before {
+		t.Error("belirsiz görev için kayıt oluşturuldu")
+	}
+	sent := tg.sent()
+	if len(sent) != 1 || !strings.Contains(sent[0], "CX-7006 (#1)") || !strings.Contains(sent[0], "CX-7010 (#2)") {
+		t.Errorf("adaylar listelenmedi: %q", sent)
+	}
+}
+
+func TestHandleMessageConfirmsInexactMatch(t *testing.T) {
+	srv := newFakeOdoo(t)
+	tg := newFakeTelegram(t)
+	loadTestUsers(t)
+	before := len(srv.Records(odoo.ModelTimesheet))
+
+	// "teknos" yalnızca TEKNOSA ile kısmen eşleşir; kayıttan önce onay istenir
+	sendText(3003, "2025-02-07|teknos|#2|Geliştirme|2")
+	if len(srv.Records(odoo.ModelTimesheet)) != before {
+		t.Fatal("onay beklenmeden kayıt oluşturuldu")
+	}
+	sent := tg.sentTo(3003)
+	if len(sent) != 1 || !strings.Contains(sent[0], "Proje: TEKNOSA") || !strings.Contains(sent[0], "Görev: CX-7010") {
+		t.Fatalf("onay mesajı: %q", sent)
+	}
+
+	pressButton(t, tg, 3003, "✅ Kaydet")
+	records := srv.Records(odoo.ModelTimesheet)
+	if len(records) != before+1 {
+		t.Fatalf("onaydan sonra kayıt oluşturulmadı: %q", tg.sentTo(3003))
+	}
+	if task, _ := records[len(records)-1]["task_id"].([]interface{}); len(task) < 2 || task[1] != "CX-7010" {
+		t.Errorf("görev = %v", records[len(records)-1]["task_id"])
+	}
+}
+
+func TestEditAndDeleteEntries(t *testing.T) {
+	srv := newFakeOdoo(t)
+	tg := newFakeTelegram(t)
+	loadTestUsers(t)
+	id := srv.Insert(odoo.ModelTimesheet, map[string]interface{}{
+		"date": time.Now().Format(dateLayout), "employee_id": 3, "project_id": 1, "name": "Bugün", "unit_amount": 1.5,
+	})
+	ref := strconv.FormatInt(id, 10)
+
+	sendText(3003, "/entries")
+	if sent := tg.sentTo(3003); !strings.Contains(sent[len(sent)-1], "#"+ref+" ") {
+		t.Fatalf("kayıt listelenmedi: %q", sent)
+	}
+
+	sendText(3003, "/edit "+ref+" saat 3")
+	if sent := tg.sentTo(3003); !strings.Contains(sent[len(sent)-1], "Saat: 1.50 → 3.00") {
+		t.Fatalf("onay mesajı: %q", sent[len(sent)-1])
+	}
+	pressButton(t, tg, 3003, "✅ Onayla")
+	if rec := findRecord(srv, id); rec["unit_amount"] != 3.0 {
+		t.Errorf("kayıt güncellenmedi: %v", rec)
+	}
+
+	// Başkasının kaydı değiştirilemez
+	sendText(3003, "/delete 1")
+	if sent := tg.sentTo(3003); !strings.HasPrefix(sent[len(sent)-1], "⛔") {
+		t.Errorf("başkasının kaydı için silme onayı istendi: %q", sent[len(sent)-1])
+	}
+
+	sendText(3003, "/delete "+ref)
+	pressButton(t, tg, 3003, "❌ Vazgeç")
+	if findRecord(srv, id) == nil {
+		t.Fatal("vazgeçilen kayıt silindi")
+	}
+	sendText(3003, "/delete "+ref)
+	pressButton(t, tg, 3003, "✅ Onayla")
+	if findRecord(srv, id) != nil {
+		t.Error("kayıt silinmedi")
+	}
+}
+
+func TestEditRevalidatedOnConfirm(t *testing.T) {
+	srv := newFakeOdoo(t)
+	tg := newFakeTelegram(t)
+	loadTestUsers(t)
+	today := time.Now().Format(dateLayout)
+	id := srv.Insert(odoo.ModelTimesheet, map[string]interface{}{
+		"date": today, "employee_id": 3, "project_id": 1, "name": "Bugün", "unit_amount": 1.5,
+	})
+	ref := strconv.FormatInt(id, 10)
+
+	sendText(3003, "/edit "+ref+" saat 6")
+	if sent := tg.sentTo(3003); !strings.Contains(sent[len(sent)-1], "Saat: 1.50 → 6.00") {
+		t.Fatalf("onay mesajı: %q", sent[len(sent)-1])
+	}
+
+	// Onay beklerken aynı güne eklenen kayıt günlük sınırı aşırır
+	srv.Insert(odoo.ModelTimesheet, map[string]interface{}{
+		"date": today, "employee_id": 3, "project_id": 1, "name": "Başka", "unit_amount": 8.0,
+	})
+	pressButton(t, tg, 3003, "✅ Onayla")
+	if rec := findRecord(srv, id); rec["unit_amount"] != 1.5 {
+		t.Errorf("günlük sınırı aşan değişiklik yazıldı: %v", rec)
+	}
+	if sent := tg.sentTo(3003); !strings.HasPrefix(sent[len(sent)-1], "❌") {
+		t.Errorf("doğrulama hatası bildirilmedi: %q", sent[len(sent)-1])
+	}
+}
+
+func TestHandleMessageValidatesEntry(t *testing.T) {
+	srv := newFakeOdoo(t)
+	tg := newFakeTelegram(t)
+	before := len(srv.Records(odoo.ModelTimesheet))
+
+	handleMessage(textMessage("2025-02-07|TEKNOSA|CX-7006|x|0.3"), legacyAdmin)
+
+	if len(srv.Records(odoo.ModelTimesheet)) != before {
+		t.Error("geçersiz kayıt oluşturuldu")
+	}
+	sent := tg.sent()
+	if len(sent) != 1 || !strings.Contains(sent[0], "• saat:") || !strings.Contains(sent[0], "• açıklama:") {
+		t.Errorf("alan hataları bildirilmedi: %q", sent)
+	}
+}
+
+func TestTimerCommands(t *testing.T) {
+	srv := newFakeOdoo(t)
+	tg := newFakeTelegram(t)
+	loadTestUsers(t)
+	now := time.Date(2025, 2, 5, 14, 0, 0, 0, time.Local)
+	useTestTimers(t, &now)
+
+	sendText(3003, "/start")
+	if sent := tg.sentTo(3003); !strings.Contains(sent[len(sent)-1], "hoş geldiniz") {
+		t.Errorf("argümansız /start karşılama mesajı göstermedi: %q", sent[len(sent)-1])
+	}
+
+	sendText(3003, "/start TEKNOSA CX-7010")
+	if sent := tg.sentTo(3003); !strings.Contains(sent[len(sent)-1], "Zamanlayıcı başladı: TEKNOSA / CX-7010") {
+		t.Fatalf("başlatma yanıtı: %q", sent[len(sent)-1])
+	}
+
+	now = now.Add(95 * time.Minute)
+	sendText(3003, "/timer")
+	if sent := tg.sentTo(3003); !strings.Contains(sent[len(sent)-1], "Geçen süre: 1 sa 35 dk") {
+		t.Errorf("durum: %q", sent[len(sent)-1])
+	}
+
+	// Başka kullanıcının zamanlayıcısı yoktur
+	sendText(2002, "/stop")
+	if sent := tg.sentTo(2002); !strings.Contains(sent[len(sent)-1], "Çalışan bir zamanlayıcı yok") {
+		t.Errorf("başka kullanıcı: %q", sent[len(sent)-1])
+	}
+
+	before := len(srv.Records(odoo.ModelTimesheet))
+	sendText(3003, "/stop Test senaryoları")
+	if sent := tg.sentTo(3003); !strings.Contains(sent[len(sent)-1], "⏱️ Saat: 1.50") {
+		t.Fatalf("durdurma yanıtı: %q", sent[len(sent)-1])
+	}
+	records := srv.Records(odoo.ModelTimesheet)
+	if len(records) != before+1 {
+		t.Fatalf("%d kayıt eklendi, beklenen 1", len(records)-before)
+	}
+	rec := records[len(records)-1]
+	employee, _ := rec["employee_id"].([]interface{})
+	if len(employee) < 1 || employee[0] != int64(3) || rec["unit_amount"] != 1.5 || rec["name"] != "Test senaryoları" {
+		t.Errorf("oluşturulan kayıt = %v", rec)
+	}
+
+	sendText(3003, "/start CX Portal")
+	sendText(3003, "/timer iptal")
+	if sent := tg.sentTo(3003); !strings.Contains(sent[len(sent)-1], "iptal edildi: CX Portal") {
+		t.Errorf("iptal yanıtı: %q", sent[len(sent)-1])
+	}
+}
@@ -0,0 +1,397 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"odoo-efor-tracker/odoo"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Çalışan zamanlayıcıların saklandığı dosyanın varsayılan yolu
+const DefaultTimersFile = "timers.yaml"
+
+// RunningTimer, kullanıcının çalışan zamanlayıcısı
+type RunningTimer struct {
+	ProjectID   int64     `yaml:"project_id"`
+	ProjectName string    `yaml:"project"`
+	TaskID      int64     `yaml:"task_id,omitempty"`
+	TaskName    string    `yaml:"task,omitempty"`
+	Description string    `yaml:"description,omitempty"`
+	Started     time.Time `yaml:"started"`
+}
+
+// Zamanlayıcı başlangıç ve bitişinde kullanılan saat; testlerde değiştirilir
+var timerNow = time.Now
+
+// Zamanlayıcı dosyası her işlemde okunup yazılır; böylece bot yeniden
+// başlatıldığında zamanlayıcılar kaybolmaz. timersMu süreç içindeki,
+// lockTimers'ın aldığı dosya kilidi ise komut satırı ile bot gibi aynı
+// dosyayı kullanan süreçler arasındaki eşzamanlı değişiklikleri sıraya koyar.
+var timersMu sync.Mutex
+
+func timersPath() string {
+	if path := os.Getenv("TIMERS_FILE"); path != "" {
+		return path
+	}
+	return DefaultTimersFile
+}
+
+// Zamanlayıcı dosyasını kilitle (<dosya>.lock). Dönen fonksiyon kilidi bırakır.
+func lockTimers() (func(), error) {
+	timersMu.Lock()
+	f, err := os.OpenFile(timersPath()+".lock", os.O_CREATE|os.O_RDWR, 0o600)
+	if err != nil {
+		timersMu.Unlock()
+		return nil, fmt.Errorf("zamanlayıcı dosyası kilitlenemedi: %v", err)
+	}
+	if err := lockFile(f); err != nil {
+		f.Close()
+		timersMu.Unlock()
+		return nil, fmt.Errorf("zamanlayıcı dosyası kilitlenemedi: %v", err)
+	}
+	return func() {
+		unlockFile(f)
+		f.Close()
+		timersMu.Unlock()
+	}, nil
+}
+
+// Zamanlayıcıları oku; kilit tutulmalıdır. Dosya yoksa boş döner.
+func readTimers() (map[string]RunningTimer, error) {
+	timers := map[string]RunningTimer{}
+	data, err := os.ReadFile(timersPath())
+	if errors.Is(err, os.ErrNotExist) {
+		return timers, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("zamanlayıcı dosyası okunamadı: %v", err)
+	}
+	var file struct {
+		Timers map[string]RunningTimer `yaml:"timers"`
+	}
+	if err := yaml.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("zamanlayıcı dosyası çözümlenemedi (%s): %v", timersPath(), err)
+	}
+	for owner, t := range file.Timers {
+		timers[owner] = t
+	}
+	return timers, nil
+}
+
+// Zamanlayıcıları geçici dosya üzerinden atomik olarak yaz; kilit tutulmalıdır
+func writeTimers(timers map[string]RunningTimer) error {
+	data, err := yaml.Marshal(struct {
+		Timers map[string]RunningTimer `yaml:"timers"`
+	}{timers})
+	if err != nil {
+		return err
+	}
+	path := timersPath()
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".timers-*.yaml")
+	if err != nil {
+		return fmt.Errorf("zamanlayıcı dosyası yazılamadı: %v", err)
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("zamanlayıcı dosyası yazılamadı: %v", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("zamanlayıcı dosyası yazılamadı: %v", err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("zamanlayıcı dosyası yazılamadı: %v", err)
+	}
+	return nil
+}
+
+// Telegram kullanıcısının zamanlayıcı anahtarı
+func telegramTimerOwner(user *BotUser) string {
+	return "telegram:" + strconv.FormatInt(user.Telegram, 10)
+}
+
+// Komut satırı kullanıcısının zamanlayıcı anahtarı
+func cliTimerOwner(employee string) string {
+	return "cli:" + employee
+}
+
+// Kullanıcının çalışan zamanlayıcısı; yoksa nil
+func runningTimer(owner string) (*RunningTimer, error) {
+	unlock, err := lockTimers()
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+	timers, err := readTimers()
+	if err != nil {
+		return nil, err
+	}
+	if t, ok := timers[owner]; ok {
+		return &t, nil
+	}
+	return nil, nil
+}
+
+// Zamanlayıcıyı başlat. Kullanıcının çalışan bir zamanlayıcısı varsa hata döner.
+func startTimer(owner string, timer RunningTimer) error {
+	unlock, err := lockTimers()
+	if err != nil {
+		return err
+	}
+	defer unlock()
+	timers, err := readTimers()
+	if err != nil {
+		return err
+	}
+	if running, ok := timers[owner]; ok {
+		return fmt.Errorf("zaten çalışan bir zamanlayıcı var: %s", running.label())
+	}
+	timers[owner] = timer
+	return writeTimers(timers)
+}
+
+// Zamanlayıcıyı kaldırıp döndür; yoksa nil. Kayıt oluşturulamazsa
+// restoreTimer ile geri konur, böylece iki durdurma isteği aynı kaydı iki
+// kez oluşturamaz.
+func takeTimer(owner string) (*RunningTimer, error) {
+	unlock, err := lockTimers()
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+	timers, err := readTimers()
+	if err != nil {
+		return nil, err
+	}
+	t, ok := timers[owner]
+	if !ok {
+		return nil, nil
+	}
+	delete(timers, owner)
+	if err := writeTimers(timers); err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+// takeTimer ile kaldırılan zamanlayıcıyı geri koy. Bu arada yeni bir
+// zamanlayıcı başlatıldıysa onun üzerine yazılmaz; ikisi de hatada belirtilir.
+func restoreTimer(owner string, timer *RunningTimer) error {
+	unlock, err := lockTimers()
+	if err != nil {
+		return err
+	}
+	defer unlock()
+	timers, err := readTimers()
+	if err != nil {
+		return err
+	}
+	if running, ok := timers[owner]; ok {
+		return fmt.Errorf("bu arada yeni bir zamanlayıcı başlatılmış (%s, %s); kaydedilemeyen zamanlayıcı: %s, %s",
+			running.label(), running.Started.Format("2006-01-02 15:04"),
+			timer.label(), timer.Started.Format("2006-01-02 15:04"))
+	}
+	timers[owner] = *timer
+	return writeTimers(timers)
+}
+
+// Zamanlayıcının yuvarlama adımı: TIMER_ROUNDING (dakika), verilmemişse
+// ENTRY_HOURS_STEP. TIMER_ROUNDING, ENTRY_HOURS_STEP'in katı olmalıdır; aksi
+// halde yuvarlanan süre doğrulamadan hiçbir zaman geçemez.
+func timerIncrement(rules ValidationRules) (time.Duration, error) {
+	if v := os.Getenv("TIMER_ROUNDING"); v != "" {
+		minutes, err := strconv.Atoi(v)
+		if err != nil || minutes < 0 {
+			return 0, fmt.Errorf("geçersiz TIMER_ROUNDING: %q", v)
+		}
+		if step := rules.HoursStep * 60; step > 0 {
+			if n := float64(minutes) / step; minutes == 0 || math.Abs(n-math.Round(n)) > 1e-9 {
+				return 0, fmt.Errorf("TIMER_ROUNDING (%d dk), ENTRY_HOURS_STEP adımının (%g dk) katı olmalı", minutes, step)
+			}
+		}
+		return time.Duration(minutes) * time.Minute, nil
+	}
+	return time.Duration(rules.HoursStep * float64(time.Hour)), nil
+}
+
+// Doğrulama kuralları ve zamanlayıcının yuvarlama adımı
+func timerSettings() (ValidationRules, time.Duration, error) {
+	rules, err := validationRulesFromEnv()
+	if err != nil {
+		return rules, 0, err
+	}
+	increment, err := timerIncrement(rules)
+	return rules, increment, err
+}
+
+// Geçen süreyi adımın en yakın katına yuvarlayıp saate çevir (en az bir
+// adım). Adım sıfırsa süre dakikaya yuvarlanır.
+func timerHours(elapsed, increment time.Duration) float64 {
+	if increment <= 0 {
+		increment = time.Minute
+	}
+	steps := math.Max(1, math.Round(float64(elapsed)/float64(increment)))
+	return math.Round(steps*increment.Hours()*100) / 100
+}
+
+// Zamanlayıcının kısa gösterimi
+func (t RunningTimer) label() string {
+	label := t.ProjectName
+	if t.TaskName != "" {
+		label += " / " + t.TaskName
+	}
+	return label
+}
+
+// Zamanlayıcının durumu
+func (t RunningTimer) Status(now time.Time) string {
+	elapsed := now.Sub(t.Started).Round(time.Minute)
+	text := fmt.Sprintf("⏱️ %s\nBaşlangıç: %s\nGeçen süre: %s",
+		t.label(), t.Started.Format("2006-01-02 15:04"), formatElapsed(elapsed))
+	if t.Description != "" {
+		text += "\nAçıklama: " + t.Description
+	}
+	return text
+}
+
+// Süreyi "2 sa 15 dk" biçiminde yaz
+func formatElapsed(d time.Duration) string {
+	h, m := int(d.Hours()), int(d.Minutes())%60
+	if h == 0 {
+		return fmt.Sprintf("%d dk", m)
+	}
+	return fmt.Sprintf("%d sa %d dk", h, m)
+}
+
+// Proje ve görevi çözümleyip zamanlayıcıyı hazırla. Proje ya da görev
+// kapalıysa zamanlayıcı başlatılmaz.
+func newTimer(client odoo.API, project, task, description string) (*RunningTimer, error) {
+	entry, err := resolveTimeEntry(client, "", project, task, description, 0)
+	if err != nil {
+		return nil, err
+	}
+	if reason, err := checkOpen(client, odoo.ModelProject, entry.ProjectID); err != nil {
+		return nil, err
+	} else if reason != "" {
+		return nil, fmt.Errorf("proje %s: %s", reason, entry.ProjectName)
+	}
+	if entry.TaskID > 0 {
+		if reason, err := checkOpen(client, odoo.ModelTask, entry.TaskID); err != nil {
+			return nil, err
+		} else if reason != "" {
+			return nil, fmt.Errorf("görev %s: %s", reason, entry.TaskName)
+		}
+	}
+	return &RunningTimer{
+		ProjectID:   entry.ProjectID,
+		ProjectName: entry.ProjectName,
+		TaskID:      entry.TaskID,
+		TaskName:    entry.TaskName,
+		Description: strings.TrimSpace(description),
+		Started:     timerNow(),
+	}, nil
+}
+
+// "/start" ve "start" argümanlarından zamanlayıcıyı hazırla. Argümanlar
+// "proje | görev | açıklama" biçiminde ya da boşlukla ayrılmış olabilir; ikinci
+// durumda proje adı en uzun eşleşen baştaki kelimelerden, görev kalanından
+// oluşur: "CX Portal", "TEKNOSA CX-7006".
+func parseTimerArgs(client odoo.API, args string) (*RunningTimer, error) {
+	if strings.Contains(args, "|") {
+		parts := strings.SplitN(args, "|", 3)
+		for len(parts) < 3 {
+			parts = append(parts, "")
+		}
+		project := strings.TrimSpace(parts[0])
+		if project == "" {
+			return nil, errors.New("proje gerekli")
+		}
+		return newTimer(client, project, strings.TrimSpace(parts[1]), parts[2])
+	}
+
+	fields := strings.Fields(args)
+	if len(fields) == 0 {
+		return nil, errors.New("proje gerekli")
+	}
+	var lastErr error
+	for i := len(fields); i > 0; i-- {
+		candidates, err := searchProjects(client, strings.Join(fields[:i], " "))
+		if err != nil {
+			return nil, err
+		}
+		if _, err := pickCandidate("proje", strings.Join(fields[:i], " "), candidates); err != nil {
+			lastErr = err
+			continue
+		}
+		return newTimer(client, strings.Join(fields[:i], " "), strings.Join(fields[i:], " "), "")
+	}
+	return nil, lastErr
+}
+
+// Zamanlayıcıdan oluşturulacak kayıt. Kayıt zamanlayıcının başladığı güne
+// yazılır. Açıklama verilmezse başlatırken girilen açıklama, o da yoksa görev
+// ya da proje adı kullanılır. Süre maxHours'u aşarsa (açık unutulan
+// zamanlayıcı) saat, sınırın altındaki en büyük adıma indirilir; capped bu
+// durumu bildirir.
+func (t RunningTimer) Entry(now time.Time, increment time.Duration, maxHours float64, description string) (entry *ResolvedEntry, capped bool) {
+	description = strings.TrimSpace(description)
+	for _, fallback := range []string{t.Description, t.TaskName, t.ProjectName} {
+		if description == "" {
+			description = fallback
+		}
+	}
+	hours := timerHours(now.Sub(t.Started), increment)
+	if maxHours > 0 && hours > maxHours {
+		hours, capped = maxHours, true
+		if step := increment.Hours(); step > 0 && step <= maxHours {
+			hours = math.Round(math.Floor(maxHours/step+1e-9)*step*100) / 100
+		}
+	}
+	return &ResolvedEntry{
+		NewTimeEntry: NewTimeEntry{
+			Date:        t.Started.In(now.Location()).Format(dateLayout),
+			ProjectID:   t.ProjectID,
+			TaskID:      t.TaskID,
+			Description: description,
+			Hours:       hours,
+		},
+		ProjectName: t.ProjectName,
+		TaskName:    t.TaskName,
+		Exact:       true,
+	}, capped
+}
+
+// Zamanlayıcıyı durdur ve kaydı oluştur. Kayıt oluşturulamazsa zamanlayıcı
+// çalışmaya devam eder. capped, sürenin ENTRY_MAX_HOURS ile sınırlandığını
+// bildirir.
+func stopTimer(client *odoo.Client, user *BotUser, owner, description string) (entry *ResolvedEntry, capped bool, err error) {
+	rules, increment, err := timerSettings()
+	if err != nil {
+		return nil, false, err
+	}
+	timer, err := takeTimer(owner)
+	if err != nil {
+		return nil, false, err
+	}
+	if timer == nil {
+		return nil, false, errors.New("çalışan zamanlayıcı yok")
+	}
+
+	entry, capped = timer.Entry(timerNow(), increment, rules.MaxHours, description)
+	if _, err := createTimeEntry(client, user, entry.NewTimeEntry); err != nil {
+		if restoreErr := restoreTimer(owner, timer); restoreErr != nil {
+			return nil, false, fmt.Errorf("%v (zamanlayıcı geri yüklenemedi: %v)", err, restoreErr)
+		}
+		return nil, false, err
+	}
+	return entry, capped, nil
+}
@@ -0,0 +1,280 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"odoo-efor-tracker/odoo"
+)
+
+// Zamanlayıcı dosyasını geçici dizine al ve saati verilen değere sabitle
+func useTestTimers(t *testing.T, now *time.Time) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "timers.yaml")
+	t.Setenv("TIMERS_FILE", path)
+	timerNow = func() time.Time { return *now }
+	t.Cleanup(func() { timerNow = time.Now })
+	return path
+}
+
+func TestTimerHours(t *testing.T) {
+	for _, tt := range []struct {
+		elapsed, increment time.Duration
+		want               float64
+	}{
+		{2*time.Hour + 20*time.Minute, 15 * time.Minute, 2.25},
+		{2*time.Hour + 23*time.Minute, 15 * time.Minute, 2.5},
+		{3 * time.Minute, 15 * time.Minute, 0.25}, // En az bir adım
+		{50 * time.Minute, 30 * time.Minute, 1},
+		{50 * time.Minute, 0, 0.83},
+	} {
+		if got := timerHours(tt.elapsed, tt.increment); got != tt.want {
+			t.Errorf("timerHours(%v, %v) = %v, beklenen %v", tt.elapsed, tt.increment, got, tt.want)
+		}
+	}
+
+	// Sınırı aşan süre, sınırın altındaki en büyük adıma indirilir
+	timer := RunningTimer{ProjectName: "TEKNOSA", Started: time.Date(2025, 2, 5, 8, 0, 0, 0, time.UTC)}
+	entry, capped := timer.Entry(timer.Started.Add(9*time.Hour), 15*time.Minute, 7.9, "")
+	if !capped || entry.Hours != 7.75 {
+		t.Errorf("sınırlanan saat = %v (%v), beklenen 7.75", entry.Hours, capped)
+	}
+	if entry, capped := timer.Entry(timer.Started.Add(2*time.Hour), 15*time.Minute, 7.9, ""); capped || entry.Hours != 2 {
+		t.Errorf("sınır altındaki saat = %v (%v)", entry.Hours, capped)
+	}
+
+	t.Setenv("TIMER_ROUNDING", "30")
+	if d, err := timerIncrement(ValidationRules{HoursStep: 0.25}); err != nil || d != 30*time.Minute {
+		t.Errorf("TIMER_ROUNDING ile adım = %v, %v", d, err)
+	}
+	// Adımın katı olmayan yuvarlama doğrulamadan geçmeyen saatler üretir
+	for _, v := range []string{"10", "20", "0"} {
+		t.Setenv("TIMER_ROUNDING", v)
+		if _, err := timerIncrement(ValidationRules{HoursStep: 0.25}); err == nil {
+			t.Errorf("TIMER_ROUNDING=%s kabul edildi", v)
+		}
+	}
+	if d, err := timerIncrement(ValidationRules{}); err != nil || d != 0 {
+		t.Errorf("adımsız kurallarla TIMER_ROUNDING=0 = %v, %v", d, err)
+	}
+	t.Setenv("TIMER_ROUNDING", "")
+	if d, _ := timerIncrement(ValidationRules{HoursStep: 0.5}); d != 30*time.Minute {
+		t.Errorf("varsayılan adım = %v, beklenen ENTRY_HOURS_STEP", d)
+	}
+}
+
+func TestTimerStore(t *testing.T) {
+	now := time.Date(2025, 2, 5, 9, 0, 0, 0, time.Local)
+	path := useTestTimers(t, &now)
+
+	timer := RunningTimer{ProjectID: 1, ProjectName: "TEKNOSA", TaskID: 1, TaskName: "CX-7006", Started: now}
+	if err := startTimer("telegram:1", timer); err != nil {
+		t.Fatal(err)
+	}
+	if err := startTimer("telegram:1", timer); err == nil || !strings.Contains(err.Error(), "TEKNOSA / CX-7006") {
+		t.Errorf("ikinci zamanlayıcı hatası = %v", err)
+	}
+	if err := startTimer("cli:", RunningTimer{ProjectID: 3, ProjectName: "CX Portal", Started: now}); err != nil {
+		t.Fatal(err)
+	}
+
+	// Zamanlayıcılar dosyadan okunur; süreç yeniden başlasa da korunur
+	data, err := os.ReadFile(path)
+	if err != nil || !strings.Contains(string(data), "telegram:1:") {
+		t.Fatalf("zamanlayıcı dosyası: %s %v", data, err)
+	}
+	got, err := runningTimer("telegram:1")
+	if err != nil || got == nil || got.TaskID != 1 || !got.Started.Equal(now) {
+		t.Fatalf("okunan zamanlayıcı = %+v, %v", got, err)
+	}
+
+	taken, err := takeTimer("telegram:1")
+	if err != nil || taken == nil {
+		t.Fatalf("takeTimer = %+v, %v", taken, err)
+	}
+	if again, _ := takeTimer("telegram:1"); again != nil {
+		t.Error("zamanlayıcı iki kez alındı")
+	}
+	if err := restoreTimer("telegram:1", taken); err != nil {
+		t.Fatal(err)
+	}
+	if got, _ := runningTimer("telegram:1"); got == nil {
+		t.Error("zamanlayıcı geri konmadı")
+	}
+
+	// Arada başlatılan zamanlayıcının üzerine yazılmaz
+	taken, _ = takeTimer("telegram:1")
+	later := RunningTimer{ProjectID: 3, ProjectName: "CX Portal", Started: now.Add(time.Hour)}
+	if err := startTimer("telegram:1", later); err != nil {
+		t.Fatal(err)
+	}
+	err = restoreTimer("telegram:1", taken)
+	if err == nil || !strings.Contains(err.Error(), "CX Portal") || !strings.Contains(err.Error(), "TEKNOSA / CX-7006") {
+		t.Errorf("restoreTimer hatası = %v", err)
+	}
+	if got, _ := runningTimer("telegram:1"); got == nil || got.ProjectID != 3 {
+		t.Errorf("yeni zamanlayıcının üzerine yazıldı: %+v", got)
+	}
+	if other, _ := runningTimer("cli:"); other == nil || other.ProjectID != 3 {
+		t.Errorf("diğer kullanıcının zamanlayıcısı = %+v", other)
+	}
+}
+
+func TestParseTimerArgs(t *testing.T) {
+	newFakeOdoo(t)
+	client, err := authenticateOdoo()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer client.Close()
+
+	for _, tt := range []struct {
+		args, project, task, description string
+	}{
+		{"TEKNOSA CX-7006", "TEKNOSA", "CX-7006", ""},
+		{"CX Portal", "CX Portal", "", ""},
+		{"#1 cx-7010", "TEKNOSA", "CX-7010", ""},
+		{"TEKNOSA | CX-7010 | Kod incelemesi", "TEKNOSA", "CX-7010", "Kod incelemesi"},
+	} {
+		timer, err := parseTimerArgs(client, tt.args)
+		if err != nil {
+			t.Errorf("%q: %v", tt.args, err)
+			continue
+		}
+		if timer.ProjectName != tt.project || timer.TaskName != tt.task || timer.Description != tt.description {
+			t.Errorf("%q = %+v", tt.args, timer)
+		}
+	}
+	if _, err := parseTimerArgs(client, "Olmayan Proje"); err == nil || !strings.Contains(err.Error(), "proje bulunamadı") {
+		t.Errorf("olmayan proje hatası = %v", err)
+	}
+	if _, err := parseTimerArgs(client, "TEKNOSA Olmayan"); err == nil || !strings.Contains(err.Error(), "görev bulunamadı") {
+		t.Errorf("olmayan görev hatası = %v", err)
+	}
+}
+
+func TestCLITimerRejectsMisalignedRounding(t *testing.T) {
+	newFakeOdoo(t)
+	now := time.Date(2025, 2, 5, 9, 0, 0, 0, time.Local)
+	useTestTimers(t, &now)
+	captureCLI(t, "")
+
+	t.Setenv("TIMER_ROUNDING", "10")
+	if err := runStartCommand([]string{"-project", "TEKNOSA"}); err == nil || !strings.Contains(err.Error(), "TIMER_ROUNDING") {
+		t.Errorf("uyumsuz TIMER_ROUNDING ile zamanlayıcı başlatıldı: %v", err)
+	}
+	if timer, _ := runningTimer(cliTimerOwner("")); timer != nil {
+		t.Error("zamanlayıcı kaydedildi")
+	}
+}
+
+func TestCLITimer(t *testing.T) {
+	srv := newFakeOdoo(t)
+	now := time.Date(2025, 2, 5, 9, 0, 0, 0, time.Local)
+	useTestTimers(t, &now)
+	out := captureCLI(t, "")
+
+	if err := runStartCommand([]string{"-project", "TEKNOSA", "-task", "CX-7006"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := runStartCommand([]string{"-project", "CX Portal"}); err == nil {
+		t.Error("çalışan zamanlayıcı varken yenisi başlatıldı")
+	}
+
+	now = now.Add(2*time.Hour + 20*time.Minute)
+	if err := runTimerCommand(nil); err != nil || !strings.Contains(out.String(), "Geçen süre: 2 sa 20 dk") {
+		t.Errorf("durum çıktısı (%v):\n%s", err, out)
+	}
+
+	before := len(srv.Records(odoo.ModelTimesheet))
+	if err := runStopCommand([]string{"-description", "Giriş ekranı"}); err != nil {
+		t.Fatal(err)
+	}
+	records := srv.Records(odoo.ModelTimesheet)
+	if len(records) != before+1 {
+		t.Fatalf("%d kayıt eklendi, beklenen 1", len(records)-before)
+	}
+	rec := records[len(records)-1]
+	if rec["date"] != "2025-02-05" || rec["unit_amount"] != 2.25 || rec["name"] != "Giriş ekranı" {
+		t.Errorf("oluşturulan kayıt = %v", rec)
+	}
+	if err := runStopCommand(nil); err == nil {
+		t.Error("durdurulmuş zamanlayıcı tekrar durduruldu")
+	}
+
+	// Açık unutulan zamanlayıcının süresi kayıt başına en fazla saate indirilir
+	now = time.Date(2025, 2, 6, 8, 0, 0, 0, time.Local)
+	if err := runStartCommand([]string{"-project", "CX Portal"}); err != nil {
+		t.Fatal(err)
+	}
+	now = now.Add(13 * time.Hour)
+	if err := runStopCommand(nil); err != nil {
+		t.Fatalf("uzun süren zamanlayıcı durdurulamadı: %v", err)
+	}
+	records = srv.Records(odoo.ModelTimesheet)
+	if rec := records[len(records)-1]; rec["date"] != "2025-02-06" || rec["unit_amount"] != DefaultMaxEntryHours {
+		t.Errorf("oluşturulan kayıt = %v", rec)
+	}
+	if !strings.Contains(out.String(), "en fazla saate indirildi (12.00 saat)") {
+		t.Errorf("sınırlama uyarısı yok:\n%s", out)
+	}
+
+	// Doğrulamadan geçmeyen kayıt oluşturulmaz ve zamanlayıcı çalışmaya devam eder
+	if err := runStartCommand([]string{"-project", "CX Portal"}); err != nil {
+		t.Fatal(err)
+	}
+	now = now.Add(time.Hour)
+	if err := runStopCommand([]string{"-description", "x"}); err == nil {
+		t.Fatal("geçersiz açıklamalı kayıt eklendi")
+	}
+	if timer, _ := runningTimer(cliTimerOwner("")); timer == nil {
+		t.Fatal("başarısız durdurmadan sonra zamanlayıcı kayboldu")
+	}
+	if err := runTimerCommand([]string{"-discard"}); err != nil {
+		t.Fatal(err)
+	}
+	if timer, _ := runningTimer(cliTimerOwner("")); timer != nil {
+		t.Error("zamanlayıcı iptal edilmedi")
+	}
+}
+
+func TestTimerFileLock(t *testing.T) {
+	now := time.Date(2025, 2, 5, 9, 0, 0, 0, time.Local)
+	path := useTestTimers(t, &now)
+
+	// Başka bir süreç (ör. komut satırı) dosyayı kilitlemiş gibi
+	other, err := os.OpenFile(path+".lock", os.O_CREATE|os.O_RDWR, 0o600)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer other.Close()
+	if err := lockFile(other); err != nil {
+		t.Fatal(err)
+	}
+
+	done := make(chan error, 1)
+	go func() { done <- startTimer("cli:", RunningTimer{ProjectID: 1, ProjectName: "TEKNOSA", Started: now}) }()
+	select {
+	case err := <-done:
+		t.Fatalf("kilit beklenmeden yazıldı: %v", err)
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	if err := unlockFile(other); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatal(err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("kilit bırakıldıktan sonra zamanlayıcı yazılmadı")
+	}
+	if timer, _ := runningTimer("cli:"); timer == nil {
+		t.Error("zamanlayıcı kaydedilmedi")
+	}
+}
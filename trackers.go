@@ -0,0 +1,544 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Zaman takip aracı eşleme dosyasının varsayılan yolu
+const DefaultTrackersFile = "trackers.yaml"
+
+// Desteklenen zaman takip araçları
+const (
+	TrackerToggl    = "toggl"
+	TrackerClockify = "clockify"
+	TrackerHarvest  = "harvest"
+)
+
+// TrackerEntry, zaman takip aracının dışa aktarımındaki tek kayıt
+type TrackerEntry struct {
+	Line        int
+	Date        string
+	Client      string
+	Project     string
+	Task        string
+	Tags        []string
+	User        string
+	Description string
+	Hours       float64
+}
+
+// TrackerMapping, araçtaki müşteri/proje/görev/etiket adlarının Odoo proje
+// ve görevine eşlenmesi. Boş bırakılan ölçütler her kayıtla eşleşir.
+type TrackerMapping struct {
+	Source  string `yaml:"source"` // toggl, clockify, harvest; boşsa hepsi
+	Client  string `yaml:"client"`
+	Project string `yaml:"project"`
+	Task    string `yaml:"task"`
+	Tag     string `yaml:"tag"`
+
+	OdooProject int64 `yaml:"odoo_project"`
+	OdooTask    int64 `yaml:"odoo_task"` // Sıfırsa görevsiz kayıt
+}
+
+// TrackerConfig, zaman takip aracı eşlemeleri
+type TrackerConfig struct {
+	Mappings []TrackerMapping `yaml:"mappings"`
+
+	// Araçtaki kullanıcı adı -> Odoo çalışanı (ad ya da ID). Kullanıcısı
+	// belirtilmeyen kayıtlar varsayılan çalışana yazılır; eşlenmeyen
+	// kullanıcıların kayıtları hatalı sayılır, böylece çok kullanıcılı bir
+	// dışa aktarımdaki başkalarının saatleri varsayılan çalışana yazılmaz.
+	Users map[string]string `yaml:"users"`
+}
+
+// Eşlemeleri TRACKERS_FILE (varsayılan trackers.yaml) dosyasından oku. Dosya
+// yoksa boş yapılandırma döner; bu durumda araçtaki proje ve görev adları
+// Odoo'da aranır.
+func loadTrackerConfig() (*TrackerConfig, error) {
+	path := os.Getenv("TRACKERS_FILE")
+	if path == "" {
+		path = DefaultTrackersFile
+	}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return &TrackerConfig{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("eşleme dosyası okunamadı: %v", err)
+	}
+
+	var cfg TrackerConfig
+	if err := yaml.Unmarshal(data, &cfg); err != nil {
+		return nil, fmt.Errorf("eşleme dosyası çözümlenemedi (%s): %v", path, err)
+	}
+	for i, m := range cfg.Mappings {
+		if m.OdooProject <= 0 {
+			return nil, fmt.Errorf("eşleme dosyası (%s): %d. eşlemede odoo_project eksik", path, i+1)
+		}
+	}
+	return &cfg, nil
+}
+
+// Eşleme kayıtla eşleşiyor mu
+func (m TrackerMapping) matches(source string, e TrackerEntry) bool {
+	same := func(want, got string) bool {
+		return want == "" || strings.EqualFold(strings.TrimSpace(want), strings.TrimSpace(got))
+	}
+	if !same(m.Source, source) || !same(m.Client, e.Client) || !same(m.Project, e.Project) || !same(m.Task, e.Task) {
+		return false
+	}
+	if m.Tag == "" {
+		return true
+	}
+	for _, tag := range e.Tags {
+		if same(m.Tag, tag) {
+			return true
+		}
+	}
+	return false
+}
+
+// Kaydı içe aktarma satırına çevir. İlk eşleşen eşleme kullanılır; eşleme
+// yoksa proje ve görev adları Odoo'da aranmak üzere olduğu gibi bırakılır.
+// Kullanıcısı users ile eşlenmemiş kayıt hatalı satır olur.
+func (c *TrackerConfig) row(source string, e TrackerEntry) ImportRow {
+	row := ImportRow{
+		Line:        e.Line,
+		Date:        e.Date,
+		Project:     e.Project,
+		Task:        e.Task,
+		Description: e.Description,
+		Hours:       e.Hours,
+	}
+	if e.User != "" {
+		employee, ok := c.Users[e.User]
+		if !ok {
+			row.Err = fmt.Errorf("%s kullanıcısı eşleme dosyasında (users) tanımlı değil", e.User)
+		}
+		row.Employee = employee
+	}
+	for _, m := range c.Mappings {
+		if m.matches(source, e) {
+			row.Project, row.Task = fmt.Sprintf("#%d", m.OdooProject), ""
+			if m.OdooTask > 0 {
+				row.Task = fmt.Sprintf("#%d", m.OdooTask)
+			}
+			break
+		}
+	}
+	return row
+}
+
+// Kayıtları eşleyip içe aktarma satırlarına çevir. Araçlar kısa kayıtlar
+// ürettiğinden aynı gün, çalışan, proje, görev ve açıklamaya sahip kayıtlar
+// birleştirilir ve toplam saat step'in en yakın katına yuvarlanır (en az bir
+// adım). Süresiz ve hâlâ çalışan kayıtlar atlanır.
+func trackerRows(source string, entries []TrackerEntry, cfg *TrackerConfig, step float64) []ImportRow {
+	var rows []ImportRow
+	index := map[ImportRow]int{}
+	for _, e := range entries {
+		if e.Hours <= 0 {
+			continue
+		}
+		row := cfg.row(source, e)
+		key := row
+		key.Line, key.Hours = 0, 0
+		if i, ok := index[key]; ok {
+			rows[i].Hours += row.Hours
+			continue
+		}
+		index[key] = len(rows)
+		rows = append(rows, row)
+	}
+	if step > 0 {
+		for i := range rows {
+			rows[i].Hours = math.Max(step, math.Round(rows[i].Hours/step)*step)
+		}
+	}
+	return rows
+}
+
+// Aracın dışa aktarma dosyasını uzantısına göre CSV ya da JSON olarak oku
+func readTrackerFile(source, path string) ([]TrackerEntry, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("dosya okunamadı: %v", err)
+	}
+	ext := strings.ToLower(filepath.Ext(path))
+	if ext != ".csv" && ext != ".json" {
+		return nil, fmt.Errorf("desteklenmeyen dosya türü %q (.csv ya da .json)", filepath.Ext(path))
+	}
+	var entries []TrackerEntry
+	switch source {
+	case TrackerToggl:
+		if ext == ".csv" {
+			entries, err = parseTogglCSV(data)
+		} else {
+			entries, err = parseTogglJSON(data)
+		}
+	case TrackerClockify:
+		if ext == ".csv" {
+			entries, err = parseClockifyCSV(data)
+		} else {
+			entries, err = parseClockifyJSON(data)
+		}
+	case TrackerHarvest:
+		if ext == ".csv" {
+			entries, err = parseHarvestCSV(data)
+		} else {
+			entries, err = parseHarvestJSON(data)
+		}
+	default:
+		return nil, fmt.Errorf("bilinmeyen biçim %q (%s, %s, %s)", source, TrackerToggl, TrackerClockify, TrackerHarvest)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%s dosyası okunamadı: %v", source, err)
+	}
+	return entries, nil
+}
+
+// Aracın dışa aktarma dosyasını oku ve eşleme dosyasına göre içe aktarma
+// satırlarına çevir
+func readTrackerRows(source, path string, step float64) ([]ImportRow, error) {
+	entries, err := readTrackerFile(source, path)
+	if err != nil {
+		return nil, err
+	}
+	cfg, err := loadTrackerConfig()
+	if err != nil {
+		return nil, err
+	}
+	return trackerRows(source, entries, cfg, step), nil
+}
+
+// Dışa aktarımlardaki tarih biçimleri
+var trackerDateLayouts = []string{dateLayout, "01/02/2006", "02.01.2006", "2006/01/02"}
+
+// Tarihi YYYY-MM-DD biçimine çevir
+func parseTrackerDate(value string) (string, error) {
+	for _, layout := range trackerDateLayouts {
+		if day, err := time.Parse(layout, value); err == nil {
+			return day.Format(dateLayout), nil
+		}
+	}
+	return "", fmt.Errorf("geçersiz tarih: %q", value)
+}
+
+// Zaman damgasının yerel saatteki gününü döndür
+func timestampDate(value string) (string, error) {
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return "", fmt.Errorf("geçersiz zaman: %q", value)
+	}
+	return t.In(time.Local).Format(dateLayout), nil
+}
+
+// "01:30:00" ya da "1:30" biçimindeki süreyi saate çevir
+func parseClockDuration(value string) (float64, error) {
+	parts := strings.Split(value, ":")
+	if len(parts) < 2 || len(parts) > 3 {
+		return 0, fmt.Errorf("geçersiz süre: %q", value)
+	}
+	hours := 0.0
+	for i, part := range parts {
+		n, err := strconv.Atoi(part)
+		if err != nil || n < 0 {
+			return 0, fmt.Errorf("geçersiz süre: %q", value)
+		}
+		hours += float64(n) / math.Pow(60, float64(i))
+	}
+	return hours, nil
+}
+
+// ISO 8601 süresi: "PT1H30M15S"
+var isoDuration = regexp.MustCompile(`^PT(?:(\d+)H)?(?:(\d+)M)?(?:(\d+)S)?$`)
+
+// ISO 8601 süresini ya da saniye sayısını saate çevir
+func parseISODuration(raw json.RawMessage) (float64, error) {
+	var seconds float64
+	if err := json.Unmarshal(raw, &seconds); err == nil {
+		return seconds / 3600, nil
+	}
+	var value string
+	if err := json.Unmarshal(raw, &value); err != nil {
+		return 0, fmt.Errorf("geçersiz süre: %s", raw)
+	}
+	m := isoDuration.FindStringSubmatch(value)
+	if m == nil || value == "PT" {
+		return 0, fmt.Errorf("geçersiz süre: %q", value)
+	}
+	hours := 0.0
+	for i, unit := range []float64{1, 60, 3600} {
+		if m[i+1] != "" {
+			n, _ := strconv.Atoi(m[i+1])
+			hours += float64(n) / unit
+		}
+	}
+	return hours, nil
+}
+
+// Virgülle ayrılmış etiketleri böl
+func splitTags(value string) []string {
+	var tags []string
+	for _, tag := range strings.Split(value, ",") {
+		if tag = strings.TrimSpace(tag); tag != "" {
+			tags = append(tags, tag)
+		}
+	}
+	return tags
+}
+
+// Zorunlu sütunları kontrol et
+func requireColumns(head []string, columns ...string) error {
+	have := map[string]bool{}
+	for _, name := range head {
+		have[name] = true
+	}
+	for _, name := range columns {
+		if !have[name] {
+			return fmt.Errorf("CSV'de %q sütunu yok", name)
+		}
+	}
+	return nil
+}
+
+// Toggl Track ayrıntılı rapor CSV'si
+func parseTogglCSV(data []byte) ([]TrackerEntry, error) {
+	head, records, err := readCSV(data)
+	if err != nil {
+		return nil, err
+	}
+	if err := requireColumns(head, "project", "description", "start date", "duration"); err != nil {
+		return nil, err
+	}
+	entries := make([]TrackerEntry, 0, len(records))
+	for _, rec := range records {
+		f := rec.Fields
+		date, err := parseTrackerDate(f["start date"])
+		if err != nil {
+			return nil, fmt.Errorf("satır %d: %v", rec.Line, err)
+		}
+		hours, err := parseClockDuration(f["duration"])
+		if err != nil {
+			return nil, fmt.Errorf("satır %d: %v", rec.Line, err)
+		}
+		entries = append(entries, TrackerEntry{
+			Line: rec.Line, Date: date, Hours: hours,
+			Client: f["client"], Project: f["project"], Task: f["task"],
+			Tags: splitTags(f["tags"]), User: f["user"], Description: f["description"],
+		})
+	}
+	return entries, nil
+}
+
+// Toggl Track ayrıntılı rapor JSON'u: {"data": [...]} ya da kayıt dizisi.
+// Süre (dur) milisaniyedir.
+func parseTogglJSON(data []byte) ([]TrackerEntry, error) {
+	type togglEntry struct {
+		User        string   `json:"user"`
+		Client      string   `json:"client"`
+		Project     string   `json:"project"`
+		Task        string   `json:"task"`
+		Description string   `json:"description"`
+		Start       string   `json:"start"`
+		Dur         int64    `json:"dur"`
+		Tags        []string `json:"tags"`
+	}
+	var list []togglEntry
+	if err := json.Unmarshal(data, &list); err != nil {
+		var report struct {
+			Data []togglEntry `json:"data"`
+		}
+		if err := json.Unmarshal(data, &report); err != nil {
+			return nil, err
+		}
+		list = report.Data
+	}
+	entries := make([]TrackerEntry, 0, len(list))
+	for i, e := range list {
+		date, err := timestampDate(e.Start)
+		if err != nil {
+			return nil, fmt.Errorf("kayıt %d: %v", i+1, err)
+		}
+		entries = append(entries, TrackerEntry{
+			Line: i + 1, Date: date, Hours: float64(e.Dur) / float64(time.Hour/time.Millisecond),
+			Client: e.Client, Project: e.Project, Task: e.Task,
+			Tags: e.Tags, User: e.User, Description: e.Description,
+		})
+	}
+	return entries, nil
+}
+
+// Clockify ayrıntılı rapor CSV'si
+func parseClockifyCSV(data []byte) ([]TrackerEntry, error) {
+	head, records, err := readCSV(data)
+	if err != nil {
+		return nil, err
+	}
+	if err := requireColumns(head, "project", "description", "start date"); err != nil {
+		return nil, err
+	}
+	entries := make([]TrackerEntry, 0, len(records))
+	for _, rec := range records {
+		f := rec.Fields
+		date, err := parseTrackerDate(f["start date"])
+		if err != nil {
+			return nil, fmt.Errorf("satır %d: %v", rec.Line, err)
+		}
+		var hours float64
+		if v, ok := f["duration (decimal)"]; ok {
+			hours, err = parseHours(v)
+		} else {
+			hours, err = parseClockDuration(f["duration (h)"])
+		}
+		if err != nil {
+			return nil, fmt.Errorf("satır %d: geçersiz süre: %v", rec.Line, err)
+		}
+		entries = append(entries, TrackerEntry{
+			Line: rec.Line, Date: date, Hours: hours,
+			Client: f["client"], Project: f["project"], Task: f["task"],
+			Tags: splitTags(f["tags"]), User: f["user"], Description: f["description"],
+		})
+	}
+	return entries, nil
+}
+
+// Clockify JSON'u: API'nin ayrıntılı (hydrated) kayıt dizisi ya da ayrıntılı
+// raporun {"timeentries": [...]} nesnesi
+func parseClockifyJSON(data []byte) ([]TrackerEntry, error) {
+	type named struct {
+		Name       string `json:"name"`
+		ClientName string `json:"clientName"`
+	}
+	type clockifyEntry struct {
+		Description  string `json:"description"`
+		TimeInterval struct {
+			Start    string          `json:"start"`
+			Duration json.RawMessage `json:"duration"`
+		} `json:"timeInterval"`
+		Project *named  `json:"project"`
+		Task    *named  `json:"task"`
+		User    *named  `json:"user"`
+		Tags    []named `json:"tags"`
+
+		// Rapor biçiminde adlar düz alanlardadır
+		ProjectName string `json:"projectName"`
+		ClientName  string `json:"clientName"`
+		TaskName    string `json:"taskName"`
+		UserName    string `json:"userName"`
+	}
+	var list []clockifyEntry
+	if err := json.Unmarshal(data, &list); err != nil {
+		var report struct {
+			TimeEntries []clockifyEntry `json:"timeentries"`
+		}
+		if err := json.Unmarshal(data, &report); err != nil {
+			return nil, err
+		}
+		list = report.TimeEntries
+	}
+	entries := make([]TrackerEntry, 0, len(list))
+	for i, e := range list {
+		date, err := timestampDate(e.TimeInterval.Start)
+		if err != nil {
+			return nil, fmt.Errorf("kayıt %d: %v", i+1, err)
+		}
+		hours, err := parseISODuration(e.TimeInterval.Duration)
+		if err != nil {
+			return nil, fmt.Errorf("kayıt %d: %v", i+1, err)
+		}
+		entry := TrackerEntry{
+			Line: i + 1, Date: date, Hours: hours, Description: e.Description,
+			Client: e.ClientName, Project: e.ProjectName, Task: e.TaskName, User: e.UserName,
+		}
+		if e.Project != nil {
+			entry.Project, entry.Client = e.Project.Name, e.Project.ClientName
+		}
+		if e.Task != nil {
+			entry.Task = e.Task.Name
+		}
+		if e.User != nil {
+			entry.User = e.User.Name
+		}
+		for _, tag := range e.Tags {
+			entry.Tags = append(entry.Tags, tag.Name)
+		}
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
+
+// Harvest ayrıntılı zaman raporu CSV'si
+func parseHarvestCSV(data []byte) ([]TrackerEntry, error) {
+	head, records, err := readCSV(data)
+	if err != nil {
+		return nil, err
+	}
+	if err := requireColumns(head, "date", "project", "notes", "hours"); err != nil {
+		return nil, err
+	}
+	entries := make([]TrackerEntry, 0, len(records))
+	for _, rec := range records {
+		f := rec.Fields
+		date, err := parseTrackerDate(f["date"])
+		if err != nil {
+			return nil, fmt.Errorf("satır %d: %v", rec.Line, err)
+		}
+		hours, err := parseHours(f["hours"])
+		if err != nil {
+			return nil, fmt.Errorf("satır %d: geçersiz saat: %q", rec.Line, f["hours"])
+		}
+		entries = append(entries, TrackerEntry{
+			Line: rec.Line, Date: date, Hours: hours,
+			Client: f["client"], Project: f["project"], Task: f["task"],
+			User: strings.TrimSpace(f["first name"] + " " + f["last name"]), Description: f["notes"],
+		})
+	}
+	return entries, nil
+}
+
+// Harvest API v2 JSON'u: {"time_entries": [...]}
+func parseHarvestJSON(data []byte) ([]TrackerEntry, error) {
+	type named struct {
+		Name string `json:"name"`
+	}
+	var export struct {
+		TimeEntries []struct {
+			SpentDate string  `json:"spent_date"`
+			Hours     float64 `json:"hours"`
+			Notes     string  `json:"notes"`
+			User      named   `json:"user"`
+			Client    named   `json:"client"`
+			Project   named   `json:"project"`
+			Task      named   `json:"task"`
+		} `json:"time_entries"`
+	}
+	if err := json.Unmarshal(data, &export); err != nil {
+		return nil, err
+	}
+	entries := make([]TrackerEntry, 0, len(export.TimeEntries))
+	for i, e := range export.TimeEntries {
+		date, err := parseTrackerDate(e.SpentDate)
+		if err != nil {
+			return nil, fmt.Errorf("kayıt %d: %v", i+1, err)
+		}
+		entries = append(entries, TrackerEntry{
+			Line: i + 1, Date: date, Hours: e.Hours,
+			Client: e.Client.Name, Project: e.Project.Name, Task: e.Task.Name,
+			User: e.User.Name, Description: e.Notes,
+		})
+	}
+	return entries, nil
+}
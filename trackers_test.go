@@ -0,0 +1,146 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"odoo-efor-tracker/odoo"
+)
+
+func TestParseTrackerExports(t *testing.T) {
+	for _, tt := range []struct {
+		source, ext, data string
+		want              TrackerEntry
+	}{
+		{TrackerToggl, ".csv",
+			"User,Email,Client,Project,Task,Description,Billable,Start date,Start time,End date,End time,Duration,Tags,Amount ()\n" +
+				"Fatih,fatih@example.com,Teknosa,Mobil,,Giriş ekranı,Yes,2025-02-05,09:00:00,2025-02-05,10:30:00,01:30:00,\"geliştirme, mobil\",\n",
+			TrackerEntry{Line: 2, Date: "2025-02-05", Client: "Teknosa", Project: "Mobil", Tags: []string{"geliştirme", "mobil"}, User: "Fatih", Description: "Giriş ekranı", Hours: 1.5}},
+		{TrackerToggl, ".json",
+			`{"data": [{"user": "Fatih", "client": "Teknosa", "project": "Mobil", "description": "Giriş ekranı", "start": "2025-02-05T09:00:00+03:00", "dur": 5400000, "tags": ["mobil"]}]}`,
+			TrackerEntry{Line: 1, Date: "2025-02-05", Client: "Teknosa", Project: "Mobil", Tags: []string{"mobil"}, User: "Fatih", Description: "Giriş ekranı", Hours: 1.5}},
+		{TrackerClockify, ".csv",
+			"Project,Client,Description,Task,User,Group,Email,Tags,Billable,Start Date,Start Time,End Date,End Time,Duration (h),Duration (decimal)\n" +
+				"Portal,Enoca,Toplantı,Planlama,Fatih,,fatih@example.com,,No,02/05/2025,09:00:00,02/05/2025,09:45:00,00:45:00,0.75\n",
+			TrackerEntry{Line: 2, Date: "2025-02-05", Client: "Enoca", Project: "Portal", Task: "Planlama", User: "Fatih", Description: "Toplantı", Hours: 0.75}},
+		{TrackerClockify, ".json",
+			`[{"description": "Toplantı", "timeInterval": {"start": "2025-02-05T06:00:00Z", "duration": "PT45M"}, "project": {"name": "Portal", "clientName": "Enoca"}, "task": {"name": "Planlama"}, "user": {"name": "Fatih"}, "tags": [{"name": "iç"}]}]`,
+			TrackerEntry{Line: 1, Date: "2025-02-05", Client: "Enoca", Project: "Portal", Task: "Planlama", Tags: []string{"iç"}, User: "Fatih", Description: "Toplantı", Hours: 0.75}},
+		{TrackerHarvest, ".csv",
+			"Date,Client,Project,Project Code,Task,Notes,Hours,Hours Rounded,Billable?,Invoiced?,First Name,Last Name\n" +
+				"2025-02-05,Teknosa,Mobil,TKN,Development,Kod incelemesi,\"2,25\",2.25,Yes,No,Fatih,Kaya\n",
+			TrackerEntry{Line: 2, Date: "2025-02-05", Client: "Teknosa", Project: "Mobil", Task: "Development", User: "Fatih Kaya", Description: "Kod incelemesi", Hours: 2.25}},
+		{TrackerHarvest, ".json",
+			`{"time_entries": [{"spent_date": "2025-02-05", "hours": 2.25, "notes": "Kod incelemesi", "user": {"name": "Fatih Kaya"}, "client": {"name": "Teknosa"}, "project": {"name": "Mobil"}, "task": {"name": "Development"}}]}`,
+			TrackerEntry{Line: 1, Date: "2025-02-05", Client: "Teknosa", Project: "Mobil", Task: "Development", User: "Fatih Kaya", Description: "Kod incelemesi", Hours: 2.25}},
+	} {
+		path := filepath.Join(t.TempDir(), "export"+tt.ext)
+		if err := os.WriteFile(path, []byte(tt.data), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		entries, err := readTrackerFile(tt.source, path)
+		if err != nil {
+			t.Errorf("%s%s: %v", tt.source, tt.ext, err)
+			continue
+		}
+		got, _ := json.Marshal(entries)
+		want, _ := json.Marshal([]TrackerEntry{tt.want})
+		if string(got) != string(want) {
+			t.Errorf("%s%s:\n%s\nbeklenen\n%s", tt.source, tt.ext, got, want)
+		}
+	}
+
+	if _, err := readTrackerFile("jira", filepath.Join("testdata", "teams.yaml")); err == nil {
+		t.Error("bilinmeyen biçim kabul edildi")
+	}
+}
+
+func TestTrackerRows(t *testing.T) {
+	cfg := &TrackerConfig{
+		Mappings: []TrackerMapping{
+			{Source: TrackerToggl, Tag: "toplantı", OdooProject: 2, OdooTask: 3},
+			{Client: "Teknosa", Project: "Mobil", OdooProject: 1, OdooTask: 1},
+			{Client: "Teknosa", OdooProject: 1},
+		},
+		Users: map[string]string{"Fatih": "3"},
+	}
+	entries := []TrackerEntry{
+		{Line: 2, Date: "2025-02-05", Client: "Teknosa", Project: "Mobil", Tags: []string{"Toplantı"}, User: "Fatih", Description: "Haftalık", Hours: 0.5},
+		{Line: 3, Date: "2025-02-05", Client: "teknosa", Project: "mobil", User: "Fatih", Description: "Giriş", Hours: 0.4},
+		{Line: 4, Date: "2025-02-05", Client: "Teknosa", Project: "Mobil", User: "Fatih", Description: "Giriş", Hours: 0.45},
+		{Line: 5, Date: "2025-02-05", Client: "Teknosa", Project: "Web", Description: "Destek", Hours: 0.05},
+		{Line: 6, Date: "2025-02-05", Project: "CX Portal", Task: "Analiz", Description: "Eşlemesiz", Hours: 1},
+		{Line: 7, Date: "2025-02-05", Project: "Mobil", Description: "Çalışıyor", Hours: -1},
+		{Line: 8, Date: "2025-02-05", Client: "Teknosa", Project: "Web", User: "Zeynep", Description: "Destek", Hours: 1},
+	}
+	got := trackerRows(TrackerToggl, entries, cfg, 0.25)
+	want := []ImportRow{
+		{Line: 2, Date: "2025-02-05", Project: "#2", Task: "#3", Description: "Haftalık", Hours: 0.5, Employee: "3"},
+		{Line: 3, Date: "2025-02-05", Project: "#1", Task: "#1", Description: "Giriş", Hours: 0.75, Employee: "3"},
+		{Line: 5, Date: "2025-02-05", Project: "#1", Description: "Destek", Hours: 0.25},
+		{Line: 6, Date: "2025-02-05", Project: "CX Portal", Task: "Analiz", Description: "Eşlemesiz", Hours: 1},
+	}
+	if len(got) != len(want)+1 {
+		t.Fatalf("satırlar = %+v", got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("satır %d = %+v, beklenen %+v", i, got[i], want[i])
+		}
+	}
+	// Eşlenmeyen kullanıcının kaydı varsayılan çalışana yazılmaz
+	if row := got[len(want)]; row.Line != 8 || row.Err == nil || !strings.Contains(row.Err.Error(), "Zeynep") {
+		t.Errorf("eşlenmeyen kullanıcı satırı = %+v", row)
+	}
+
+	// Etiket eşlemesi yalnızca Toggl için tanımlı
+	if rows := trackerRows(TrackerClockify, entries[:1], cfg, 0.25); rows[0].Project != "#1" || rows[0].Task != "#1" {
+		t.Errorf("Clockify satırı = %+v", rows[0])
+	}
+}
+
+func TestCLIImportTracker(t *testing.T) {
+	srv := newFakeOdoo(t)
+	dir := t.TempDir()
+	mapping := filepath.Join(dir, "trackers.yaml")
+	if err := os.WriteFile(mapping, []byte(`
+mappings:
+  - client: Teknosa
+    project: Mobil
+    odoo_project: 1
+    odoo_task: 2
+users:
+  Fatih Kaya: Fatih
+`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("TRACKERS_FILE", mapping)
+
+	path := filepath.Join(dir, "harvest.csv")
+	if err := os.WriteFile(path, []byte("Date,Client,Project,Task,Notes,Hours,First Name,Last Name\n"+
+		"2025-02-05,Teknosa,Mobil,Development,Kod incelemesi,1.1,Fatih,Kaya\n"+
+		"2025-02-05,Teknosa,Mobil,Development,Kod incelemesi,0.9,Fatih,Kaya\n"+
+		"2025-02-05,Teknosa,Mobil,Development,Tasarım,3,Zeynep,Ak\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	out := captureCLI(t, "")
+	if err := runImportCommand([]string{"-format", "harvest", "-file", path, "-skip-invalid", "-yes"}); err != nil {
+		t.Fatalf("%v\n%s", err, out)
+	}
+	if !strings.Contains(out.String(), "Zeynep Ak kullanıcısı eşleme dosyasında (users) tanımlı değil") {
+		t.Errorf("eşlenmeyen kullanıcı hatası yok:\n%s", out)
+	}
+	if !strings.Contains(out.String(), "✓ satır 2: #") || !strings.Contains(out.String(), "Fatih Delice: TEKNOSA / CX-7010 - 2.00 saat - Kod incelemesi") {
+		t.Errorf("rapor:\n%s", out)
+	}
+	records := srv.Records(odoo.ModelTimesheet)
+	rec := records[len(records)-1]
+	employee, _ := rec["employee_id"].([]interface{})
+	if rec["unit_amount"] != 2.0 || len(employee) < 1 || employee[0] != int64(3) {
+		t.Errorf("oluşturulan kayıt = %v", rec)
+	}
+}
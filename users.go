@@ -0,0 +1,262 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+
+	"odoo-efor-tracker/odoo"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Kullanıcı kayıt dosyasının varsayılan yolu
+const DefaultUsersFile = "users.yaml"
+
+// Bot kullanıcı rolleri. Her rol bir öncekinin yetkilerini de kapsar.
+const (
+	RoleMember = "member" // Kendi saatleri ve kendi adına kayıt
+	RoleLead   = "lead"   // Liderlik ettiği ekiplerin raporları
+	RoleAdmin  = "admin"  // Tüm raporlar, dışa aktarma ve kullanıcı yönetimi
+)
+
+var roleRanks = map[string]int{RoleMember: 1, RoleLead: 2, RoleAdmin: 3}
+
+// BotUser, bir Telegram kullanıcısının bağlı olduğu Odoo çalışanı ve rolü
+type BotUser struct {
+	Telegram   int64    `yaml:"telegram"`
+	Employee   string   `yaml:"employee,omitempty"` // Çalışan adı
+	EmployeeID int64    `yaml:"employee_id,omitempty"`
+	Role       string   `yaml:"role"`
+	Teams      []string `yaml:"teams,omitempty"` // Liderin raporlarını görebildiği ekipler
+
+	// Kullanıcının kendi Odoo hesabı; kayıtlar bu kimlikle oluşturulur.
+	// OdooKey, BOT_SECRET_KEY ile şifrelenmiş API anahtarıdır.
+	OdooLogin string `yaml:"odoo_login,omitempty"`
+	OdooKey   string `yaml:"odoo_key,omitempty"`
+}
+
+// Kullanıcının en az verilen role sahip olup olmadığı
+func (u *BotUser) Can(role string) bool {
+	return roleRanks[u.Role] >= roleRanks[role]
+}
+
+// Rapor filtresi olarak kullanılacak çalışan (ID varsa ID)
+func (u *BotUser) employeeFilter() string {
+	if u.EmployeeID > 0 {
+		return strconv.FormatInt(u.EmployeeID, 10)
+	}
+	return u.Employee
+}
+
+// Kullanıcının kayıtlı Odoo API anahtarı var mı
+func (u *BotUser) HasOdooKey() bool {
+	return u.OdooLogin != "" && u.OdooKey != ""
+}
+
+// API anahtarını şifreleyerek kullanıcıya ata
+func (u *BotUser) SetOdooKey(login, apiKey string) error {
+	secret, err := encryptSecret(apiKey, strconv.FormatInt(u.Telegram, 10))
+	if err != nil {
+		return err
+	}
+	u.OdooLogin, u.OdooKey = login, secret
+	return nil
+}
+
+// Kullanıcının Odoo bağlantı bilgileri; sunucu ve veritabanı çevre
+// değişkenlerinden, kimlik kullanıcının kendi anahtarından gelir
+func (u *BotUser) odooConfig() (odoo.Config, error) {
+	apiKey, err := decryptSecret(u.OdooKey, strconv.FormatInt(u.Telegram, 10))
+	if err != nil {
+		return odoo.Config{}, err
+	}
+	cfg := odoo.ConfigFromEnv()
+	cfg.Username, cfg.Password = u.OdooLogin, apiKey
+	return cfg, nil
+}
+
+// Kullanıcının kimliğiyle Odoo'ya bağlan; anahtarı yoksa servis hesabı
+// kullanılır
+func dialAs(u *BotUser) (*odoo.Client, error) {
+	if !u.HasOdooKey() {
+		return authenticateOdoo()
+	}
+	cfg, err := u.odooConfig()
+	if err != nil {
+		return nil, err
+	}
+	client, err := odoo.Dial(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("%s hesabıyla giriş yapılamadı: %v", u.OdooLogin, err)
+	}
+	return client, nil
+}
+
+// Kullanıcının görebileceği rapor kapsamını belirle. Üyeler yalnızca kendi
+// kayıtlarını, liderler kendi ekiplerini (ekip verilmezse ilkini), yöneticiler
+// istenen ekibi görür.
+func (u *BotUser) reportScope(team string) (employee, scopeTeam string, err error) {
+	switch {
+	case u.Can(RoleAdmin):
+		return "", team, nil
+	case u.Can(RoleLead) && len(u.Teams) > 0:
+		if team == "" {
+			return "", u.Teams[0], nil
+		}
+		if containsFold(u.Teams, team) {
+			return "", team, nil
+		}
+		return "", "", fmt.Errorf("%s ekibinin raporunu görme yetkiniz yok", team)
+	}
+	if u.employeeFilter() == "" {
+		return "", "", errors.New("hesabınız bir çalışana bağlı değil")
+	}
+	return u.employeeFilter(), "", nil
+}
+
+// UserRegistry, USERS_FILE (varsayılan users.yaml) dosyasında saklanan bot
+// kullanıcıları. Bot üzerinden yapılan değişiklikler dosyaya geri yazılır.
+type UserRegistry struct {
+	mu    sync.Mutex
+	path  string
+	Users []BotUser `yaml:"users"`
+}
+
+// Kullanıcı kayıtlarını oku. Dosya yoksa boş kayıt döner; bu durumda bot
+// yalnızca TELEGRAM_CHAT_ID sohbetine yönetici olarak yanıt verir.
+func loadUserRegistry() (*UserRegistry, error) {
+	path := os.Getenv("USERS_FILE")
+	if path == "" {
+		path = DefaultUsersFile
+	}
+	registry := &UserRegistry{path: path}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return registry, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("kullanıcı dosyası okunamadı: %v", err)
+	}
+	if err := yaml.Unmarshal(data, registry); err != nil {
+		return nil, fmt.Errorf("kullanıcı dosyası çözümlenemedi (%s): %v", path, err)
+	}
+	for _, u := range registry.Users {
+		if err := validateUser(u); err != nil {
+			return nil, fmt.Errorf("kullanıcı dosyası (%s): %v", path, err)
+		}
+	}
+	return registry, nil
+}
+
+func validateUser(u BotUser) error {
+	if u.Telegram == 0 {
+		return errors.New("Telegram kullanıcı ID'si eksik")
+	}
+	if _, ok := roleRanks[u.Role]; !ok {
+		return fmt.Errorf("%d: geçersiz rol %q (member, lead, admin)", u.Telegram, u.Role)
+	}
+	if u.Role != RoleAdmin && u.Employee == "" && u.EmployeeID == 0 {
+		return fmt.Errorf("%d: çalışan tanımlı değil", u.Telegram)
+	}
+	return nil
+}
+
+// Hiç kullanıcı tanımlı değil mi
+func (r *UserRegistry) Empty() bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return len(r.Users) == 0
+}
+
+// Telegram kullanıcı ID'siyle kullanıcıyı bul
+func (r *UserRegistry) Lookup(telegramID int64) (*BotUser, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, u := range r.Users {
+		if u.Telegram == telegramID {
+			return &u, true
+		}
+	}
+	return nil, false
+}
+
+// Çalışana bağlı kullanıcıyı bul
+func (r *UserRegistry) ByEmployee(emp odoo.Employee) (*BotUser, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, u := range r.Users {
+		if (u.EmployeeID != 0 && u.EmployeeID == emp.ID) || (u.EmployeeID == 0 && strings.EqualFold(u.Employee, emp.Name)) {
+			return &u, true
+		}
+	}
+	return nil, false
+}
+
+// Tüm kullanıcılar
+func (r *UserRegistry) List() []BotUser {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]BotUser(nil), r.Users...)
+}
+
+// Kullanıcıyı ekle ya da güncelle ve dosyaya yaz
+func (r *UserRegistry) Put(u BotUser) error {
+	if err := validateUser(u); err != nil {
+		return err
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for i := range r.Users {
+		if r.Users[i].Telegram == u.Telegram {
+			r.Users[i] = u
+			return r.save()
+		}
+	}
+	r.Users = append(r.Users, u)
+	return r.save()
+}
+
+// Kullanıcıyı sil ve dosyaya yaz
+func (r *UserRegistry) Remove(telegramID int64) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for i := range r.Users {
+		if r.Users[i].Telegram == telegramID {
+			r.Users = append(r.Users[:i], r.Users[i+1:]...)
+			return r.save()
+		}
+	}
+	return fmt.Errorf("kullanıcı bulunamadı: %d", telegramID)
+}
+
+// Dosyayı geçici dosya üzerinden atomik olarak yaz; kilit tutulmalıdır
+func (r *UserRegistry) save() error {
+	data, err := yaml.Marshal(struct {
+		Users []BotUser `yaml:"users"`
+	}{r.Users})
+	if err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(r.path), ".users-*.yaml")
+	if err != nil {
+		return fmt.Errorf("kullanıcı dosyası yazılamadı: %v", err)
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("kullanıcı dosyası yazılamadı: %v", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("kullanıcı dosyası yazılamadı: %v", err)
+	}
+	if err := os.Rename(tmp.Name(), r.path); err != nil {
+		return fmt.Errorf("kullanıcı dosyası yazılamadı: %v", err)
+	}
+	return nil
+}
@@ -0,0 +1,299 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"odoo-efor-tracker/odoo"
+)
+
+// Doğrulama kurallarının varsayılanları
+const (
+	DefaultMaxEntryHours        = 12.0
+	DefaultHoursStep            = 0.25 // Çeyrek saat
+	DefaultDailyHoursCap        = 12.0
+	DefaultDescriptionMinLength = 3
+)
+
+// ValidationRules, Odoo'ya yazılmadan önce her zaman kaydına uygulanan
+// kurallar. Sıfır değerli sınırlar (adım ve günlük üst sınır) kapalıdır.
+type ValidationRules struct {
+	MaxHours             float64 // Tek kayıttaki en fazla saat
+	HoursStep            float64 // Saatin katı olması gereken adım
+	DailyCap             float64 // Çalışanın bir gündeki toplam saat üst sınırı
+	DescriptionMinLength int     // Açıklamanın en az karakter sayısı
+}
+
+// Doğrulama kurallarını çevre değişkenlerinden oku
+func validationRulesFromEnv() (ValidationRules, error) {
+	rules := ValidationRules{
+		MaxHours:             DefaultMaxEntryHours,
+		HoursStep:            DefaultHoursStep,
+		DailyCap:             DefaultDailyHoursCap,
+		DescriptionMinLength: DefaultDescriptionMinLength,
+	}
+	for _, f := range []struct {
+		env string
+		dst *float64
+	}{
+		{"ENTRY_MAX_HOURS", &rules.MaxHours},
+		{"ENTRY_HOURS_STEP", &rules.HoursStep},
+		{"DAILY_HOURS_CAP", &rules.DailyCap},
+	} {
+		if v := os.Getenv(f.env); v != "" {
+			n, err := strconv.ParseFloat(v, 64)
+			if err != nil || n < 0 {
+				return rules, fmt.Errorf("geçersiz %s: %q", f.env, v)
+			}
+			*f.dst = n
+		}
+	}
+	if v := os.Getenv("DESCRIPTION_MIN_LENGTH"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 0 {
+			return rules, fmt.Errorf("geçersiz DESCRIPTION_MIN_LENGTH: %q", v)
+		}
+		rules.DescriptionMinLength = n
+	}
+	if rules.MaxHours <= 0 {
+		return rules, fmt.Errorf("ENTRY_MAX_HOURS sıfırdan büyük olmalı")
+	}
+	return rules, nil
+}
+
+// FieldError, kaydın tek bir alanındaki doğrulama hatası
+type FieldError struct {
+	Field   string // tarih, saat, açıklama, proje, görev
+	Message string
+}
+
+// ValidationError, kayıttaki tüm alan hataları
+type ValidationError []FieldError
+
+func (e ValidationError) Error() string {
+	lines := make([]string, 0, len(e))
+	for _, f := range e {
+		lines = append(lines, fmt.Sprintf("• %s: %s", f.Field, f.Message))
+	}
+	return "kayıt geçersiz:\n" + strings.Join(lines, "\n")
+}
+
+// Hata varsa ValidationError, yoksa nil döndür
+func (e ValidationError) orNil() error {
+	if len(e) == 0 {
+		return nil
+	}
+	return e
+}
+
+// Tarih YYYY-MM-DD biçiminde ve bugünden sonra olmamalı
+func (r ValidationRules) checkDate(date string, now time.Time) string {
+	day, err := time.ParseInLocation(dateLayout, date, time.Local)
+	if err != nil {
+		return fmt.Sprintf("%q YYYY-MM-DD biçiminde olmalı", date)
+	}
+	if day.After(now) {
+		return fmt.Sprintf("%s ileri bir tarih, gelecek günler için kayıt girilemez", date)
+	}
+	return ""
+}
+
+// Saat sıfırdan büyük, üst sınırı aşmayan ve adımın katı olmalı
+func (r ValidationRules) checkHours(hours float64) string {
+	if hours <= 0 {
+		return "sıfırdan büyük olmalı"
+	}
+	if hours > r.MaxHours {
+		return fmt.Sprintf("%.2f saat, tek kayıt için üst sınır olan %.2f saati aşıyor", hours, r.MaxHours)
+	}
+	if r.HoursStep > 0 {
+		steps := hours / r.HoursStep
+		if math.Abs(steps-math.Round(steps)) > 1e-9 {
+			return fmt.Sprintf("%.2f saat, %g saatin katı olmalı (örn. %.2f)", hours, r.HoursStep, math.Round(steps)*r.HoursStep)
+		}
+	}
+	return ""
+}
+
+// Açıklama boş olmamalı ve en az DescriptionMinLength karakter olmalı
+func (r ValidationRules) checkDescription(description string) string {
+	n := utf8.RuneCountInString(strings.TrimSpace(description))
+	if n == 0 {
+		return "boş olamaz"
+	}
+	if n < r.DescriptionMinLength {
+		return fmt.Sprintf("en az %d karakter olmalı", r.DescriptionMinLength)
+	}
+	return ""
+}
+
+// Odoo'ya gitmeden yapılabilen alan kontrolleri
+func (r ValidationRules) checkFields(entry NewTimeEntry, now time.Time) ValidationError {
+	var errs ValidationError
+	if msg := r.checkDate(entry.Date, now); msg != "" {
+		errs = append(errs, FieldError{"tarih", msg})
+	}
+	if msg := r.checkHours(entry.Hours); msg != "" {
+		errs = append(errs, FieldError{"saat", msg})
+	}
+	if msg := r.checkDescription(entry.Description); msg != "" {
+		errs = append(errs, FieldError{"açıklama", msg})
+	}
+	return errs
+}
+
+// Proje ya da görevin kapalı olma nedeni; açıksa boş döner. Alanlar Odoo
+// sürümüne göre değiştiğinden yalnızca okunan alanlara bakılır.
+func closedReason(fields map[string]interface{}) string {
+	if fieldFalse(fields, "active") {
+		return "arşivlenmiş"
+	}
+	if fieldFalse(fields, "allow_timesheets") {
+		return "zaman kaydına kapalı"
+	}
+	if closed, _ := fields["is_closed"].(bool); closed {
+		return "kapatılmış"
+	}
+	switch fields["state"] {
+	case "1_done":
+		return "tamamlanmış"
+	case "1_canceled":
+		return "iptal edilmiş"
+	}
+	return ""
+}
+
+// Alan kayıtta var ve false mu. Çözücü, map'e okunan false değerleri boş
+// alan gibi nil'e çevirir; alanın hiç olmamasından ayırmak için anahtara bakılır.
+func fieldFalse(fields map[string]interface{}, name string) bool {
+	v, ok := fields[name]
+	return ok && (v == nil || v == false)
+}
+
+// Proje ve görevin kapalı olup olmadığını belirleyen alanlar
+var openFields = []string{"active", "allow_timesheets", "is_closed", "state"}
+
+// İstenen alanlardan modelde var olanlar. Hiçbiri yoksa yalnızca id okunur;
+// boş liste tüm alanları okuturdu.
+func presentFields(client odoo.API, model string, names ...string) ([]string, error) {
+	all, err := client.Fields(model)
+	if err != nil {
+		return nil, err
+	}
+	var fields []string
+	for _, name := range names {
+		if all[name] {
+			fields = append(fields, name)
+		}
+	}
+	if len(fields) == 0 {
+		fields = []string{"id"}
+	}
+	return fields, nil
+}
+
+// Açık kayıtları seçen domain; closedReason ile aynı kurallar, yalnızca
+// modelde var olan alanlarla uygulanır
+func openDomain(client odoo.API, model string) (odoo.Domain, error) {
+	fields, err := presentFields(client, model, openFields...)
+	if err != nil {
+		return nil, err
+	}
+	domain := odoo.NewDomain()
+	for _, name := range fields {
+		switch name {
+		case "active", "allow_timesheets":
+			domain = domain.Where(name, "=", true)
+		case "is_closed":
+			domain = domain.Where(name, "=", false)
+		case "state":
+			domain = domain.Where(name, "not in", []string{"1_done", "1_canceled"})
+		}
+	}
+	return domain, nil
+}
+
+// Kaydın açık olup olmadığını kontrol et
+func checkOpen(client odoo.API, model string, id int64) (string, error) {
+	fields, err := presentFields(client, model, openFields...)
+	if err != nil {
+		return "", err
+	}
+	var records []map[string]interface{}
+	if err := client.Read(model, []int64{id}, fields, &records); err != nil {
+		return "", err
+	}
+	if len(records) == 0 {
+		return "bulunamadı", nil
+	}
+	return closedReason(records[0]), nil
+}
+
+// Zaman kaydını tüm kurallara göre doğrula: alanlar, proje ve görevin açık
+// olması ve çalışanın o günkü toplam saati. excludeID sıfır değilse o kayıt
+// (düzenlenen kayıt) günlük toplama katılmaz.
+func validateEntry(client odoo.API, rules ValidationRules, employeeID int64, entry NewTimeEntry, excludeID int64, now time.Time) error {
+	errs := rules.checkFields(entry, now)
+
+	if reason, err := checkOpen(client, odoo.ModelProject, entry.ProjectID); err != nil {
+		return err
+	} else if reason != "" {
+		errs = append(errs, FieldError{"proje", "proje " + reason})
+	}
+	if entry.TaskID > 0 {
+		if reason, err := checkOpen(client, odoo.ModelTask, entry.TaskID); err != nil {
+			return err
+		} else if reason != "" {
+			errs = append(errs, FieldError{"görev", "görev " + reason})
+		}
+	}
+
+	if rules.DailyCap > 0 && employeeID > 0 && len(errs) == 0 {
+		existing, err := dailyHours(client, employeeID, entry.Date, excludeID)
+		if err != nil {
+			return err
+		}
+		if msg := rules.checkDailyCap(entry.Date, existing, entry.Hours); msg != "" {
+			errs = append(errs, FieldError{"saat", msg})
+		}
+	}
+	return errs.orNil()
+}
+
+// Çalışanın o güne ait kayıtlarının toplam saati; excludeID sıfır değilse o
+// kayıt toplama katılmaz
+func dailyHours(client odoo.API, employeeID int64, date string, excludeID int64) (float64, error) {
+	var lines []odoo.TimesheetLine
+	domain := odoo.NewDomain().
+		Where("employee_id", "=", employeeID).
+		Where("date", "=", date)
+	if excludeID > 0 {
+		domain = domain.Where("id", "!=", excludeID)
+	}
+	if err := client.SearchRead(odoo.ModelTimesheet, domain,
+		&odoo.SearchOptions{Fields: []string{"unit_amount"}}, &lines); err != nil {
+		return 0, err
+	}
+	total := 0.0
+	for _, line := range lines {
+		total += line.UnitAmount
+	}
+	return total, nil
+}
+
+// Gündeki mevcut saatlere eklenen kayıt günlük üst sınırı aşmamalı
+func (r ValidationRules) checkDailyCap(date string, existing, hours float64) string {
+	if r.DailyCap <= 0 {
+		return ""
+	}
+	if total := existing + hours; total > r.DailyCap+1e-9 {
+		return fmt.Sprintf("%s için toplam %.2f saat olur, günlük üst sınır %.2f saat (girilebilecek en fazla %.2f saat)",
+			date, total, r.DailyCap, math.Max(0, r.DailyCap-existing))
+	}
+	return ""
+}
@@ -0,0 +1,126 @@
+package main
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"odoo-efor-tracker/odoo"
+)
+
+func TestValidationRulesFromEnv(t *testing.T) {
+	rules, err := validationRulesFromEnv()
+	if err != nil || rules.MaxHours != DefaultMaxEntryHours || rules.HoursStep != DefaultHoursStep || rules.DailyCap != DefaultDailyHoursCap {
+		t.Errorf("varsayılan kurallar = %+v, %v", rules, err)
+	}
+
+	t.Setenv("ENTRY_HOURS_STEP", "0")
+	t.Setenv("DAILY_HOURS_CAP", "10")
+	t.Setenv("DESCRIPTION_MIN_LENGTH", "10")
+	if rules, err = validationRulesFromEnv(); err != nil || rules.HoursStep != 0 || rules.DailyCap != 10 || rules.DescriptionMinLength != 10 {
+		t.Errorf("kurallar = %+v, %v", rules, err)
+	}
+
+	t.Setenv("ENTRY_MAX_HOURS", "çok")
+	if _, err := validationRulesFromEnv(); err == nil {
+		t.Error("geçersiz ENTRY_MAX_HOURS kabul edildi")
+	}
+}
+
+func TestCheckFields(t *testing.T) {
+	rules := ValidationRules{MaxHours: 12, HoursStep: 0.25, DailyCap: 12, DescriptionMinLength: 3}
+	now := time.Date(2025, 2, 7, 10, 0, 0, 0, time.Local)
+
+	for _, tt := range []struct {
+		entry  NewTimeEntry
+		fields string
+	}{
+		{NewTimeEntry{Date: "2025-02-07", Hours: 1.75, Description: "Geliştirme"}, ""},
+		{NewTimeEntry{Date: "2025-02-08", Hours: 1, Description: "Geliştirme"}, "tarih"},
+		{NewTimeEntry{Date: "07.02.2025", Hours: 1, Description: "Geliştirme"}, "tarih"},
+		{NewTimeEntry{Date: "2025-02-07", Hours: 0, Description: "Geliştirme"}, "saat"},
+		{NewTimeEntry{Date: "2025-02-07", Hours: 40, Description: "Geliştirme"}, "saat"},
+		{NewTimeEntry{Date: "2025-02-07", Hours: 1.1, Description: "Geliştirme"}, "saat"},
+		{NewTimeEntry{Date: "2025-02-07", Hours: -2, Description: " ab "}, "saat,açıklama"},
+	} {
+		var fields []string
+		for _, f := range rules.checkFields(tt.entry, now) {
+			fields = append(fields, f.Field)
+		}
+		if got := strings.Join(fields, ","); got != tt.fields {
+			t.Errorf("%+v: hatalı alanlar = %q, beklenen %q", tt.entry, got, tt.fields)
+		}
+	}
+}
+
+func TestValidateEntry(t *testing.T) {
+	srv := newFakeOdoo(t)
+	client, err := authenticateOdoo()
+	if err != nil {
+		t.Fatal(err)
+	}
+	rules := ValidationRules{MaxHours: 12, HoursStep: 0.25, DailyCap: 12, DescriptionMinLength: 3}
+	now := time.Date(2025, 2, 7, 10, 0, 0, 0, time.Local)
+
+	// Fatih'in 2025-02-04'te 7 saati var
+	entry := NewTimeEntry{Date: "2025-02-04", ProjectID: 1, TaskID: 1, Description: "Geliştirme", Hours: 5}
+	if err := validateEntry(client, rules, 3, entry, 0, now); err != nil {
+		t.Errorf("sınırdaki kayıt reddedildi: %v", err)
+	}
+	entry.Hours = 5.25
+	var verr ValidationError
+	if err := validateEntry(client, rules, 3, entry, 0, now); !errors.As(err, &verr) || !strings.Contains(err.Error(), "en fazla 5.00 saat") {
+		t.Errorf("günlük üst sınır aşıldı: %v", err)
+	}
+	// Düzenlenen kayıt günlük toplama katılmaz
+	if err := validateEntry(client, rules, 3, NewTimeEntry{Date: "2025-02-04", ProjectID: 3, Description: "Analiz", Hours: 12}, 7, now); err != nil {
+		t.Errorf("düzenlenen kayıt kendisiyle toplandı: %v", err)
+	}
+
+	archived := srv.Insert(odoo.ModelProject, map[string]interface{}{"name": "Eski Proje", "active": false})
+	done := srv.Insert(odoo.ModelTask, map[string]interface{}{"name": "CX-6000", "project_id": 1, "state": "1_done"})
+	err = validateEntry(client, rules, 3, NewTimeEntry{Date: "2025-02-05", ProjectID: archived, Description: "Geliştirme", Hours: 1}, 0, now)
+	if err == nil || !strings.Contains(err.Error(), "proje: proje arşivlenmiş") {
+		t.Errorf("arşivlenmiş proje kabul edildi: %v", err)
+	}
+	err = validateEntry(client, rules, 3, NewTimeEntry{Date: "2025-02-05", ProjectID: 1, TaskID: done, Description: "Geliştirme", Hours: 1}, 0, now)
+	if err == nil || !strings.Contains(err.Error(), "görev: görev tamamlanmış") {
+		t.Errorf("tamamlanmış görev kabul edildi: %v", err)
+	}
+}
+
+func TestCheckOpenReadsOnlyKnownFields(t *testing.T) {
+	srv := newFakeOdoo(t)
+	client, err := authenticateOdoo()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for i := 0; i < 2; i++ {
+		if reason, err := checkOpen(client, odoo.ModelProject, 1); err != nil || reason != "" {
+			t.Fatalf("açık proje: %q, %v", reason, err)
+		}
+	}
+
+	var fieldsGet, reads int
+	for _, call := range srv.Calls() {
+		if call.Model != odoo.ModelProject {
+			continue
+		}
+		switch call.Method {
+		case "fields_get":
+			fieldsGet++
+		case "read":
+			reads++
+			// Bu sürümde olmayan is_closed ve state istenmez
+			if got := call.Kwargs["fields"]; !reflect.DeepEqual(got, []interface{}{"active", "allow_timesheets"}) {
+				t.Errorf("okunan alanlar = %v", got)
+			}
+		}
+	}
+	if fieldsGet != 1 || reads != 2 {
+		t.Errorf("%d fields_get, %d read çağrısı; beklenen 1 ve 2", fieldsGet, reads)
+	}
+}
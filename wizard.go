@@ -0,0 +1,552 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"odoo-efor-tracker/odoo"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// Sihirbaz adımları
+const (
+	stepDate = iota
+	stepProject
+	stepTask
+	stepDescription
+	stepHours
+	stepConfirm
+)
+
+// Klavyede gösterilecek en fazla proje/görev sayısı; fazlası için kullanıcı
+// ad yazarak arama yapar
+const wizardChoiceLimit = 20
+
+// Son kullanılan projeler belirlenirken bakılan en fazla kayıt sayısı
+const wizardRecentLines = 200
+
+// Sihirbazın süresi; bu süre boyunca dokunulmayan form iptal sayılır
+const wizardTimeout = 30 * time.Minute
+
+// Geri çağrı verilerinin öneki ("add:<eylem>:<değer>")
+const wizardCallbackPrefix = "add:"
+
+// Sihirbaz durumları sohbet ve kullanıcı bazında tutulur; grup sohbetinde
+// her kullanıcının kendi formu olur
+type wizardKey struct {
+	chat int64
+	user int64
+}
+
+// entryWizard, /add ile başlayan adım adım zaman kaydı formu
+type entryWizard struct {
+	mu      sync.Mutex // Aynı forma art arda gelen güncellemeleri sıraya koyar
+	step    int
+	entry   ResolvedEntry
+	updated time.Time
+}
+
+var (
+	wizardsMu sync.Mutex
+	wizards   = map[wizardKey]*entryWizard{}
+)
+
+// Etkin sihirbazı getir ve kilitle; süresi dolmuşsa silinir. Çağıran,
+// işi bitince w.mu.Unlock() çağırmalıdır.
+func activeWizard(key wizardKey) *entryWizard {
+	wizardsMu.Lock()
+	w, ok := wizards[key]
+	if ok && time.Since(w.updated) > wizardTimeout {
+		delete(wizards, key)
+		ok = false
+	}
+	if ok {
+		w.updated = time.Now()
+	}
+	wizardsMu.Unlock()
+	if !ok {
+		return nil
+	}
+	w.mu.Lock()
+	return w
+}
+
+// Formu kapat; açık bir form yoksa false döner
+func endWizard(key wizardKey) bool {
+	wizardsMu.Lock()
+	defer wizardsMu.Unlock()
+	_, ok := wizards[key]
+	delete(wizards, key)
+	return ok
+}
+
+// Yeni bir zaman kaydı formu başlat
+func startWizard(chat int64, user *BotUser) {
+	openWizard(chat, user, &entryWizard{step: stepDate})
+}
+
+// Tek mesajla girilen ama proje ya da görevi kesin eşleşmeyen kaydı,
+// çözümlenen adlarla birlikte onaya sun
+func confirmEntry(chat int64, user *BotUser, entry *ResolvedEntry) {
+	openWizard(chat, user, &entryWizard{step: stepConfirm, entry: *entry})
+}
+
+func openWizard(chat int64, user *BotUser, w *entryWizard) {
+	w.updated = time.Now()
+	wizardsMu.Lock()
+	wizards[wizardKey{chat, user.Telegram}] = w
+	wizardsMu.Unlock()
+	w.prompt(chat, user)
+}
+
+// Formdaki adıma göre kullanıcıya soruyu ve seçenekleri gönder
+func (w *entryWizard) prompt(chat int64, user *BotUser) {
+	switch w.step {
+	case stepDate:
+		now := time.Now()
+		sendTelegramKeyboard(chat, "📅 Hangi gün için kayıt ekliyorsunuz? Tarihi YYYY-MM-DD olarak da yazabilirsiniz.",
+			tgbotapi.NewInlineKeyboardMarkup(
+				tgbotapi.NewInlineKeyboardRow(
+					tgbotapi.NewInlineKeyboardButtonData("Bugün", wizardCallbackPrefix+"date:"+now.Format(dateLayout)),
+					tgbotapi.NewInlineKeyboardButtonData("Dün", wizardCallbackPrefix+"date:"+now.AddDate(0, 0, -1).Format(dateLayout)),
+				),
+				cancelRow(),
+			))
+
+	case stepProject:
+		w.promptProjects(chat, user, "")
+
+	case stepTask:
+		w.promptTasks(chat, user, "")
+
+	case stepDescription:
+		sendTelegramKeyboard(chat, "📋 Yapılan işin açıklamasını yazın.",
+			tgbotapi.NewInlineKeyboardMarkup(cancelRow()))
+
+	case stepHours:
+		row := tgbotapi.NewInlineKeyboardRow()
+		for _, h := range []string{"0.5", "1", "2", "4", "8"} {
+			row = append(row, tgbotapi.NewInlineKeyboardButtonData(h, wizardCallbackPrefix+"hours:"+h))
+		}
+		sendTelegramKeyboard(chat, "⏱️ Kaç saat çalıştınız? Farklı bir değeri yazabilirsiniz (örn. 3.5).",
+			tgbotapi.NewInlineKeyboardMarkup(row, cancelRow()))
+
+	case stepConfirm:
+		sendTelegramKeyboard(chat, "Kaydı onaylıyor musunuz?\n\n"+w.entry.Summary(),
+			tgbotapi.NewInlineKeyboardMarkup(
+				tgbotapi.NewInlineKeyboardRow(
+					tgbotapi.NewInlineKeyboardButtonData("✅ Kaydet", wizardCallbackPrefix+"save:"),
+					tgbotapi.NewInlineKeyboardButtonData("❌ İptal", wizardCallbackPrefix+"cancel:"),
+				),
+			))
+	}
+}
+
+// Projeleri klavye olarak gönder; filter verilirse adında geçenler listelenir
+func (w *entryWizard) promptProjects(chat int64, user *BotUser, filter string) {
+	client, err := dialAs(user)
+	if err != nil {
+		sendTelegramTo(chat, fmt.Sprintf("❌ Odoo kimlik doğrulama hatası: %v", err))
+		return
+	}
+	defer client.Close()
+
+	var projects []Candidate
+	more := false
+	if filter != "" {
+		if projects, err = searchProjects(client, filter); err == nil {
+			projects, err = openProjects(client, projects)
+		}
+	} else {
+		projects, more, err = wizardProjects(client, user)
+	}
+	if err != nil {
+		sendTelegramTo(chat, fmt.Sprintf("❌ Projeler okunamadı: %v", err))
+		return
+	}
+	if len(projects) == 0 {
+		sendTelegramTo(chat, fmt.Sprintf("❓ %q ile eşleşen proje yok, başka bir ad yazın.", filter))
+		return
+	}
+	// Yazılan ad tek bir projeyle kesin ya da tek başına eşleşiyorsa seç
+	if p, err := pickCandidate("proje", filter, projects); filter != "" && err == nil && (p.Exact() || len(projects) == 1) {
+		w.selectProject(p.ID, p.Name)
+		w.prompt(chat, user)
+		return
+	}
+
+	var buttons []tgbotapi.InlineKeyboardButton
+	for _, p := range limitCandidates(projects) {
+		buttons = append(buttons, tgbotapi.NewInlineKeyboardButtonData(p.Name, wizardCallbackPrefix+"project:"+strconv.FormatInt(p.ID, 10)))
+	}
+	rows := keyboardRows(buttons, 2)
+	rows = append(rows, cancelRow())
+	text := fmt.Sprintf("📅 %s\n\n🏢 Projeyi seçin ya da adını yazarak arayın.", w.entry.Date)
+	if more {
+		text += "\nSon kullandığınız projeler önce gösterilir; listede olmayan projeyi adıyla arayın."
+	}
+	sendTelegramKeyboard(chat, text, tgbotapi.NewInlineKeyboardMarkup(rows...))
+}
+
+// Proje adımında gösterilecek açık projeler: önce çalışanın son kayıtlarında
+// kullandıkları (en yeniden eskiye), ardından ada göre diğerleri. Listeye
+// sığmayan açık proje varsa more true döner.
+func wizardProjects(client *odoo.Client, user *BotUser) (projects []Candidate, more bool, err error) {
+	open, err := openDomain(client, odoo.ModelProject)
+	if err != nil {
+		return nil, false, err
+	}
+	employeeID, err := userEmployeeID(client, user)
+	if err != nil {
+		return nil, false, err
+	}
+
+	var lines []odoo.TimesheetLine
+	err = client.SearchRead(odoo.ModelTimesheet, odoo.NewDomain().Where("employee_id", "=", employeeID),
+		&odoo.SearchOptions{Limit: wizardRecentLines, Order: "date desc, id desc"}, &lines)
+	if err != nil {
+		return nil, false, err
+	}
+	var recent []Candidate
+	seen := make(map[int64]bool)
+	for _, line := range lines {
+		if line.Project.Valid() && !seen[line.Project.ID] {
+			seen[line.Project.ID] = true
+			recent = append(recent, Candidate{ID: line.Project.ID, Name: line.Project.Name})
+		}
+	}
+	if projects, err = openProjects(client, recent); err != nil {
+		return nil, false, err
+	}
+	if len(projects) > wizardChoiceLimit {
+		return projects[:wizardChoiceLimit], true, nil
+	}
+
+	domain := append(odoo.NewDomain(), open...)
+	if len(projects) > 0 {
+		ids := make([]int64, 0, len(projects))
+		for _, p := range projects {
+			ids = append(ids, p.ID)
+		}
+		domain = domain.Where("id", "not in", ids)
+	}
+	// Listeye sığmayan proje olup olmadığını görmek için bir fazlası okunur
+	free := wizardChoiceLimit - len(projects)
+	var others []odoo.Project
+	err = client.SearchRead(odoo.ModelProject, domain,
+		&odoo.SearchOptions{Limit: free + 1, Order: "name"}, &others)
+	if err != nil {
+		return nil, false, err
+	}
+	if len(others) > free {
+		others, more = others[:free], true
+	}
+	for _, p := range others {
+		projects = append(projects, Candidate{ID: p.ID, Name: p.Name})
+	}
+	return projects, more, nil
+}
+
+// Adaylardan açık projeleri sırası korunarak döndür
+func openProjects(client odoo.API, candidates []Candidate) ([]Candidate, error) {
+	if len(candidates) == 0 {
+		return candidates, nil
+	}
+	open, err := openDomain(client, odoo.ModelProject)
+	if err != nil {
+		return nil, err
+	}
+	ids := make([]int64, 0, len(candidates))
+	for _, c := range candidates {
+		ids = append(ids, c.ID)
+	}
+	var projects []odoo.Project
+	err = client.SearchRead(odoo.ModelProject, append(odoo.NewDomain().Where("id", "in", ids), open...),
+		&odoo.SearchOptions{Fields: []string{"id"}}, &projects)
+	if err != nil {
+		return nil, err
+	}
+	found := make(map[int64]bool, len(projects))
+	for _, p := range projects {
+		found[p.ID] = true
+	}
+	var result []Candidate
+	for _, c := range candidates {
+		if found[c.ID] {
+			result = append(result, c)
+		}
+	}
+	return result, nil
+}
+
+// Seçilen projenin görevlerini klavye olarak gönder
+func (w *entryWizard) promptTasks(chat int64, user *BotUser, filter string) {
+	client, err := dialAs(user)
+	if err != nil {
+		sendTelegramTo(chat, fmt.Sprintf("❌ Odoo kimlik doğrulama hatası: %v", err))
+		return
+	}
+	defer client.Close()
+
+	var tasks []Candidate
+	if filter != "" {
+		tasks, err = searchTasks(client, w.entry.ProjectID, filter)
+	} else {
+		var all []odoo.Task
+		err = client.SearchRead(odoo.ModelTask, odoo.NewDomain().Where("project_id", "=", w.entry.ProjectID),
+			&odoo.SearchOptions{Limit: wizardChoiceLimit, Order: "name"}, &all)
+		for _, t := range all {
+			tasks = append(tasks, Candidate{ID: t.ID, Name: t.Name})
+		}
+	}
+	if err != nil {
+		sendTelegramTo(chat, fmt.Sprintf("❌ Görevler okunamadı: %v", err))
+		return
+	}
+	if filter != "" && len(tasks) == 0 {
+		sendTelegramTo(chat, fmt.Sprintf("❓ %q ile eşleşen görev yok, başka bir ad yazın.", filter))
+		return
+	}
+	if t, err := pickCandidate("görev", filter, tasks); filter != "" && err == nil && (t.Exact() || len(tasks) == 1) {
+		w.selectTask(t.ID, t.Name)
+		w.prompt(chat, user)
+		return
+	}
+
+	var buttons []tgbotapi.InlineKeyboardButton
+	for _, t := range limitCandidates(tasks) {
+		buttons = append(buttons, tgbotapi.NewInlineKeyboardButtonData(t.Name, wizardCallbackPrefix+"task:"+strconv.FormatInt(t.ID, 10)))
+	}
+	rows := keyboardRows(buttons, 2)
+	rows = append(rows,
+		tgbotapi.NewInlineKeyboardRow(tgbotapi.NewInlineKeyboardButtonData("Görevsiz", wizardCallbackPrefix+"task:0")),
+		cancelRow())
+	sendTelegramKeyboard(chat, fmt.Sprintf("🏢 %s\n\n📝 Görevi seçin ya da adını yazarak arayın.", w.entry.ProjectName),
+		tgbotapi.NewInlineKeyboardMarkup(rows...))
+}
+
+// Klavyeye sığacak kadar adayı döndür
+func limitCandidates(candidates []Candidate) []Candidate {
+	if len(candidates) > wizardChoiceLimit {
+		return candidates[:wizardChoiceLimit]
+	}
+	return candidates
+}
+
+func (w *entryWizard) selectProject(id int64, name string) {
+	w.entry.ProjectID, w.entry.ProjectName = id, name
+	w.step = stepTask
+}
+
+func (w *entryWizard) selectTask(id int64, name string) {
+	w.entry.TaskID, w.entry.TaskName = id, name
+	w.step = stepDescription
+}
+
+// Formun beklediği metni işle. Etkin form yoksa false döner.
+func handleWizardText(message *tgbotapi.Message, user *BotUser) bool {
+	chat := message.Chat.ID
+	key := wizardKey{chat, user.Telegram}
+	w := activeWizard(key)
+	if w == nil {
+		return false
+	}
+	defer w.mu.Unlock()
+	text := strings.TrimSpace(message.Text)
+
+	switch w.step {
+	case stepDate:
+		if !checkStep(chat, "tarih", func(r ValidationRules) string { return r.checkDate(text, time.Now()) }) {
+			return true
+		}
+		w.entry.Date = text
+		w.step = stepProject
+
+	case stepProject:
+		w.promptProjects(chat, user, text)
+		return true
+
+	case stepTask:
+		w.promptTasks(chat, user, text)
+		return true
+
+	case stepDescription:
+		if !checkStep(chat, "açıklama", func(r ValidationRules) string { return r.checkDescription(text) }) {
+			return true
+		}
+		w.entry.Description = text
+		w.step = stepHours
+
+	case stepHours:
+		if !w.setHours(chat, text) {
+			return true
+		}
+
+	case stepConfirm:
+		sendTelegramTo(chat, "Kaydetmek ya da iptal etmek için düğmeleri kullanın.")
+		return true
+	}
+	w.prompt(chat, user)
+	return true
+}
+
+func (w *entryWizard) setHours(chat int64, text string) bool {
+	hours, err := strconv.ParseFloat(strings.Replace(text, ",", ".", 1), 64)
+	if err != nil {
+		sendTelegramTo(chat, "❌ Geçersiz saat. Sayısal bir değer girin (örn. 1.5).")
+		return false
+	}
+	if !checkStep(chat, "saat", func(r ValidationRules) string { return r.checkHours(hours) }) {
+		return false
+	}
+	w.entry.Hours = hours
+	w.step = stepConfirm
+	return true
+}
+
+// Formda girilen alanı doğrulama kuralıyla kontrol et; geçersizse hatayı
+// kullanıcıya bildirir. Günlük toplam ve proje/görev kontrolleri kayıt
+// sırasında yapılır.
+func checkStep(chat int64, field string, check func(ValidationRules) string) bool {
+	rules, err := validationRulesFromEnv()
+	if err != nil {
+		sendTelegramTo(chat, fmt.Sprintf("❌ %v", err))
+		return false
+	}
+	if msg := check(rules); msg != "" {
+		sendTelegramTo(chat, fmt.Sprintf("❌ %s: %s", field, msg))
+		return false
+	}
+	return true
+}
+
+// Form düğmelerinden gelen geri çağrıyı işle
+func handleWizardCallback(query *tgbotapi.CallbackQuery, user *BotUser) {
+	chat := query.Message.Chat.ID
+	// Düğmeye tekrar basılmasın diye eski mesajın klavyesini kaldır
+	bot.Request(tgbotapi.NewEditMessageReplyMarkup(chat, query.Message.MessageID,
+		tgbotapi.InlineKeyboardMarkup{InlineKeyboard: [][]tgbotapi.InlineKeyboardButton{}}))
+
+	action, value, _ := strings.Cut(strings.TrimPrefix(query.Data, wizardCallbackPrefix), ":")
+	key := wizardKey{chat, user.Telegram}
+	w := activeWizard(key)
+	if w == nil {
+		bot.Request(tgbotapi.NewCallback(query.ID, "Form zaman aşımına uğradı, /add ile yeniden başlayın."))
+		return
+	}
+	defer w.mu.Unlock()
+	bot.Request(tgbotapi.NewCallback(query.ID, ""))
+
+	switch action {
+	case "cancel":
+		endWizard(key)
+		sendTelegramTo(chat, "🚫 Zaman kaydı iptal edildi.")
+		return
+
+	case "date":
+		if w.step != stepDate {
+			return
+		}
+		w.entry.Date = value
+		w.step = stepProject
+
+	case "project":
+		if w.step != stepProject {
+			return
+		}
+		id, _ := strconv.ParseInt(value, 10, 64)
+		name, err := lookupName(user, odoo.ModelProject, id)
+		if err != nil {
+			sendTelegramTo(chat, fmt.Sprintf("❌ Proje okunamadı: %v", err))
+			return
+		}
+		w.selectProject(id, name)
+
+	case "task":
+		if w.step != stepTask {
+			return
+		}
+		id, _ := strconv.ParseInt(value, 10, 64)
+		name := ""
+		if id > 0 {
+			var err error
+			if name, err = lookupName(user, odoo.ModelTask, id); err != nil {
+				sendTelegramTo(chat, fmt.Sprintf("❌ Görev okunamadı: %v", err))
+				return
+			}
+		}
+		w.selectTask(id, name)
+
+	case "hours":
+		if w.step != stepHours || !w.setHours(chat, value) {
+			return
+		}
+
+	case "save":
+		if w.step != stepConfirm {
+			return
+		}
+		endWizard(key)
+		w.save(chat, user)
+		return
+	}
+	w.prompt(chat, user)
+}
+
+// Onaylanan kaydı Odoo'da oluştur
+func (w *entryWizard) save(chat int64, user *BotUser) {
+	client, err := dialAs(user)
+	if err != nil {
+		sendTelegramTo(chat, fmt.Sprintf("❌ Odoo kimlik doğrulama hatası: %v", err))
+		return
+	}
+	defer client.Close()
+
+	if _, err := createTimeEntry(client, user, w.entry.NewTimeEntry); err != nil {
+		sendTelegramTo(chat, fmt.Sprintf("❌ Zaman kaydı eklenemedi: %v", err))
+		return
+	}
+	sendTelegramTo(chat, "✅ Zaman kaydı başarıyla eklendi!\n\n"+w.entry.Summary())
+}
+
+// Kaydın adını oku
+func lookupName(user *BotUser, model string, id int64) (string, error) {
+	client, err := dialAs(user)
+	if err != nil {
+		return "", err
+	}
+	defer client.Close()
+
+	var records []struct {
+		Name string `odoo:"name"`
+	}
+	if err := client.Read(model, []int64{id}, []string{"name"}, &records); err != nil {
+		return "", err
+	}
+	if len(records) == 0 {
+		return "", fmt.Errorf("kayıt bulunamadı: %d", id)
+	}
+	return records[0].Name, nil
+}
+
+// Düğmeleri satır başına perRow adet olacak şekilde diz
+func keyboardRows(buttons []tgbotapi.InlineKeyboardButton, perRow int) [][]tgbotapi.InlineKeyboardButton {
+	var rows [][]tgbotapi.InlineKeyboardButton
+	for len(buttons) > 0 {
+		n := perRow
+		if len(buttons) < n {
+			n = len(buttons)
+		}
+		rows = append(rows, buttons[:n])
+		buttons = buttons[n:]
+	}
+	return rows
+}
+
+func cancelRow() []tgbotapi.InlineKeyboardButton {
+	return tgbotapi.NewInlineKeyboardRow(tgbotapi.NewInlineKeyboardButtonData("❌ İptal", wizardCallbackPrefix+"cancel:"))
+}
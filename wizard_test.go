@@ -0,0 +1,145 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"odoo-efor-tracker/odoo"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// Kullanıcının satır içi düğmeye basması
+func pressButton(t *testing.T, tg *fakeTelegram, from int64, label string) {
+	t.Helper()
+	data, ok := tg.lastKeyboard(from)[label]
+	if !ok {
+		t.Fatalf("%q düğmesi yok: %v", label, tg.lastKeyboard(from))
+	}
+	query := &tgbotapi.CallbackQuery{
+		ID:      "1",
+		From:    &tgbotapi.User{ID: from},
+		Message: &tgbotapi.Message{MessageID: 1, Chat: &tgbotapi.Chat{ID: from, Type: "private"}},
+		Data:    data,
+	}
+	handleCallback(query, authorizeFrom(from, query.From))
+}
+
+func sendText(from int64, text string) {
+	msg := userMessage(from, text)
+	handleMessage(msg, authorize(msg))
+}
+
+func TestEntryWizard(t *testing.T) {
+	srv := newFakeOdoo(t)
+	tg := newFakeTelegram(t)
+	loadTestUsers(t)
+
+	sendText(3003, "/add")
+	pressButton(t, tg, 3003, "Dün")
+
+	// Proje listesi Odoo'dan gelir; yazılan ad tek projeyle eşleşirse seçilir
+	if _, ok := tg.lastKeyboard(3003)["CX Portal"]; !ok {
+		t.Fatalf("proje klavyesi: %v", tg.lastKeyboard(3003))
+	}
+	sendText(3003, "teknosa")
+	keyboard := tg.lastKeyboard(3003)
+	if _, ok := keyboard["CX-7010"]; !ok || keyboard["Görevsiz"] == "" {
+		t.Fatalf("görev klavyesi: %v", keyboard)
+	}
+	pressButton(t, tg, 3003, "CX-7006")
+	sendText(3003, "Sihirbazla eklendi")
+	pressButton(t, tg, 3003, "2")
+
+	sent := tg.sentTo(3003)
+	if summary := sent[len(sent)-1]; !strings.Contains(summary, "TEKNOSA") || !strings.Contains(summary, "CX-7006") {
+		t.Errorf("onay özeti: %q", summary)
+	}
+	before := len(srv.Records(odoo.ModelTimesheet))
+	pressButton(t, tg, 3003, "✅ Kaydet")
+
+	records := srv.Records(odoo.ModelTimesheet)
+	if len(records) != before+1 {
+		t.Fatalf("kayıt oluşturulmadı: %q", tg.sentTo(3003))
+	}
+	created := records[len(records)-1]
+	yesterday := time.Now().AddDate(0, 0, -1).Format(dateLayout)
+	task, _ := created["task_id"].([]interface{})
+	emp, _ := created["employee_id"].([]interface{})
+	if created["date"] != yesterday || created["name"] != "Sihirbazla eklendi" || created["unit_amount"] != 2.0 ||
+		len(task) < 2 || task[1] != "CX-7006" || len(emp) < 2 || emp[1] != "Fatih Delice" {
+		t.Errorf("oluşturulan kayıt = %v", created)
+	}
+	if activeWizard(wizardKey{3003, 3003}) != nil {
+		t.Error("kayıttan sonra form kapanmadı")
+	}
+}
+
+func TestEntryWizardCancel(t *testing.T) {
+	srv := newFakeOdoo(t)
+	tg := newFakeTelegram(t)
+	loadTestUsers(t)
+
+	sendText(3003, "/add")
+	sendText(3003, "07.02.2025")
+	if sent := tg.sentTo(3003); !strings.HasPrefix(sent[len(sent)-1], "❌ tarih:") {
+		t.Errorf("geçersiz tarih kabul edildi: %q", sent[len(sent)-1])
+	}
+	sendText(3003, "2025-02-07")
+	pressButton(t, tg, 3003, "❌ İptal")
+
+	// Form kapandıktan sonra metin yeniden kayıt formatı olarak yorumlanır
+	before := len(srv.Records(odoo.ModelTimesheet))
+	sendText(3003, "Enoca")
+	if sent := tg.sentTo(3003); !strings.HasPrefix(sent[len(sent)-1], "❓") {
+		t.Errorf("iptalden sonraki yanıt: %q", sent[len(sent)-1])
+	}
+	if len(srv.Records(odoo.ModelTimesheet)) != before {
+		t.Error("iptal edilen formdan kayıt oluşturuldu")
+	}
+}
+
+func TestEntryWizardProjects(t *testing.T) {
+	srv := newFakeOdoo(t)
+	tg := newFakeTelegram(t)
+	loadTestUsers(t)
+
+	// Ada göre ilk 20 proje, çalışanın kullandığı projelerden önce gelir
+	for i := 1; i <= 25; i++ {
+		srv.Insert(odoo.ModelProject, map[string]interface{}{"name": fmt.Sprintf("A Proje %02d", i)})
+	}
+	srv.Insert(odoo.ModelProject, map[string]interface{}{"name": "A Arşiv", "active": false})
+	srv.Insert(odoo.ModelProject, map[string]interface{}{"name": "A Kapalı", "allow_timesheets": false})
+	srv.Insert(odoo.ModelTimesheet, map[string]interface{}{
+		"date": "2025-02-05", "employee_id": 3, "project_id": 1, "name": "Son kayıt", "unit_amount": 1.0,
+	})
+
+	sendText(3003, "/add")
+	pressButton(t, tg, 3003, "Dün")
+
+	keyboard := tg.lastKeyboard(3003)
+	for _, name := range []string{"TEKNOSA", "CX Portal", "A Proje 01"} {
+		if _, ok := keyboard[name]; !ok {
+			t.Errorf("klavyede %q yok: %v", name, keyboard)
+		}
+	}
+	for _, name := range []string{"A Arşiv", "A Kapalı", "Enoca İç Projeler", "A Proje 19"} {
+		if _, ok := keyboard[name]; ok {
+			t.Errorf("klavyede %q olmamalı: %v", name, keyboard)
+		}
+	}
+	if len(keyboard) != wizardChoiceLimit+1 {
+		t.Errorf("%d düğme, beklenen %d proje ve iptal", len(keyboard), wizardChoiceLimit)
+	}
+	if sent := tg.sentTo(3003); !strings.Contains(sent[len(sent)-1], "adıyla arayın") {
+		t.Errorf("arama ipucu yok: %q", sent[len(sent)-1])
+	}
+
+	// Kapalı projeler aramada da çıkmaz
+	sendText(3003, "kapalı")
+	if sent := tg.sentTo(3003); !strings.HasPrefix(sent[len(sent)-1], "❓") {
+		t.Errorf("kapalı proje aramada bulundu: %q", sent[len(sent)-1])
+	}
+}
@@ -0,0 +1,246 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"odoo-efor-tracker/odoo"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Çalışma saatleri geçersiz kılma dosyasının varsayılan yolu
+const DefaultWorkHoursFile = "work_hours.yaml"
+
+// Schedule, haftanın her günü için beklenen çalışma saati (time.Weekday sırası)
+type Schedule [7]float64
+
+// Pazartesi-cuma MinWorkHours, hafta sonu 0 saat
+func defaultSchedule() Schedule {
+	var s Schedule
+	for day := time.Monday; day <= time.Friday; day++ {
+		s[day] = MinWorkHours
+	}
+	return s
+}
+
+// HoursConfig, bir çalışanın (ya da varsayılanın) çalışma saatleri. Boş
+// bırakılan alanlar bir alt kaynaktan (Odoo takvimi ya da varsayılan) gelir.
+type HoursConfig struct {
+	Weekdays  *float64 `yaml:"weekdays"` // Pazartesi-cuma için ortak değer
+	Monday    *float64 `yaml:"monday"`
+	Tuesday   *float64 `yaml:"tuesday"`
+	Wednesday *float64 `yaml:"wednesday"`
+	Thursday  *float64 `yaml:"thursday"`
+	Friday    *float64 `yaml:"friday"`
+	Saturday  *float64 `yaml:"saturday"`
+	Sunday    *float64 `yaml:"sunday"`
+
+	// Belirli günler için saat (ör. yarım gün: "2025-02-05": 4)
+	Dates map[string]float64 `yaml:"dates"`
+}
+
+// Tanımlı alanları takvimin üzerine yaz
+func (h *HoursConfig) apply(s Schedule) Schedule {
+	if h.Weekdays != nil {
+		for day := time.Monday; day <= time.Friday; day++ {
+			s[day] = *h.Weekdays
+		}
+	}
+	for day, v := range map[time.Weekday]*float64{
+		time.Monday:    h.Monday,
+		time.Tuesday:   h.Tuesday,
+		time.Wednesday: h.Wednesday,
+		time.Thursday:  h.Thursday,
+		time.Friday:    h.Friday,
+		time.Saturday:  h.Saturday,
+		time.Sunday:    h.Sunday,
+	} {
+		if v != nil {
+			s[day] = *v
+		}
+	}
+	return s
+}
+
+// Bir çalışanın saatleri; Employee adı ya da hr.employee ID'sidir
+type EmployeeHours struct {
+	Employee    string `yaml:"employee"`
+	HoursConfig `yaml:",inline"`
+}
+
+// Çalışma saatleri dosyası. Odoo'daki çalışma takvimini geçersiz kılar.
+type WorkHoursConfig struct {
+	Default   *HoursConfig    `yaml:"default"` // Odoo'da takvimi olmayan çalışanlar için
+	Employees []EmployeeHours `yaml:"employees"`
+}
+
+// Çalışan için dosyadaki tanımı bul (ID ya da ad ile)
+func (c *WorkHoursConfig) For(emp odoo.Employee) *HoursConfig {
+	for i := range c.Employees {
+		key := strings.TrimSpace(c.Employees[i].Employee)
+		if id, err := strconv.ParseInt(key, 10, 64); err == nil && id == emp.ID {
+			return &c.Employees[i].HoursConfig
+		}
+		if strings.EqualFold(key, emp.Name) {
+			return &c.Employees[i].HoursConfig
+		}
+	}
+	return nil
+}
+
+// Çalışma saatleri dosyasını WORK_HOURS_FILE (varsayılan work_hours.yaml)
+// yolundan oku. Dosya yoksa boş yapılandırma döner.
+func loadWorkHoursConfig() (*WorkHoursConfig, error) {
+	path := os.Getenv("WORK_HOURS_FILE")
+	if path == "" {
+		path = DefaultWorkHoursFile
+	}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return &WorkHoursConfig{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("çalışma saatleri dosyası okunamadı: %v", err)
+	}
+
+	var cfg WorkHoursConfig
+	if err := yaml.Unmarshal(data, &cfg); err != nil {
+		return nil, fmt.Errorf("çalışma saatleri dosyası çözümlenemedi (%s): %v", path, err)
+	}
+	for _, emp := range cfg.Employees {
+		for date := range emp.Dates {
+			if _, err := time.Parse(dateLayout, date); err != nil {
+				return nil, fmt.Errorf("%s için geçersiz tarih %q (YYYY-MM-DD)", emp.Employee, date)
+			}
+		}
+	}
+	return &cfg, nil
+}
+
+// Odoo çalışma takviminin bir satırı. İki haftalık takvimlerde yalnızca
+// kendi haftasında, tarih aralığı varsa yalnızca o aralıkta geçerlidir.
+type calendarSlot struct {
+	day      time.Weekday
+	hours    float64
+	weekType string // "0" ya da "1"; her hafta geçerliyse boş
+	from, to string // YYYY-MM-DD; boşsa sınırsız
+}
+
+// Odoo çalışma takvimi (resource.calendar)
+type odooCalendar []calendarSlot
+
+// Günün içinde bulunduğu haftanın (pazartesi-pazar) takvimi
+func (c odooCalendar) Week(day time.Time) Schedule {
+	monday := day.AddDate(0, 0, -((int(day.Weekday()) + 6) % 7))
+	weekType := odooWeekType(monday)
+
+	var s Schedule
+	for _, slot := range c {
+		if slot.weekType != "" && slot.weekType != weekType {
+			continue
+		}
+		date := monday.AddDate(0, 0, (int(slot.day)+6)%7).Format(dateLayout)
+		if (slot.from != "" && date < slot.from) || (slot.to != "" && date > slot.to) {
+			continue
+		}
+		s[slot.day] += slot.hours
+	}
+	return s
+}
+
+// Odoo'nun iki haftalık takvimdeki hafta tipi: 0001-01-01'den (pazartesi)
+// bu yana geçen hafta sayısının çiftliği (get_week_type ile aynı hesap)
+func odooWeekType(day time.Time) string {
+	date := time.Date(day.Year(), day.Month(), day.Day(), 0, 0, 0, 0, time.UTC)
+	// 1970-01-01, 0001-01-01'den sonraki 719162. gündür
+	days := date.Unix()/86400 + 719162
+	return strconv.FormatInt(days/7%2, 10)
+}
+
+// Odoo çalışma takvimlerini (resource.calendar) satırlarına çevir. Öğle
+// arası ("lunch") ve iki haftalık takvimlerin başlık satırları sayılmaz.
+func loadOdooSchedules(client odoo.API, calendarIDs []int64) (map[int64]odooCalendar, error) {
+	calendars := make(map[int64]odooCalendar)
+	if len(calendarIDs) == 0 {
+		return calendars, nil
+	}
+
+	var attendances []odoo.Attendance
+	err := client.SearchRead(odoo.ModelAttendance,
+		odoo.NewDomain().Where("calendar_id", "in", calendarIDs), nil, &attendances)
+	if err != nil {
+		return nil, fmt.Errorf("çalışma takvimleri alınamadı: %v", err)
+	}
+	for _, a := range attendances {
+		if a.DayPeriod == "lunch" || a.DisplayType != "" {
+			continue
+		}
+		odooDay, err := strconv.Atoi(a.DayOfWeek)
+		if err != nil || odooDay < 0 || odooDay > 6 {
+			return nil, fmt.Errorf("%s takviminde geçersiz gün: %q", a.Calendar.Name, a.DayOfWeek)
+		}
+		calendars[a.Calendar.ID] = append(calendars[a.Calendar.ID], calendarSlot{
+			// Odoo'da 0 pazartesidir, time.Weekday'de pazar
+			day:      time.Weekday((odooDay + 1) % 7),
+			hours:    a.HourTo - a.HourFrom,
+			weekType: a.WeekType,
+			from:     a.DateFrom,
+			to:       a.DateTo,
+		})
+	}
+	return calendars, nil
+}
+
+// Çalışanların haftalık takvimlerini ve tarih bazlı istisnalarını belirle.
+// Öncelik: dosyadaki çalışan tanımı, Odoo takvimi, dosyadaki varsayılan,
+// pazartesi-cuma MinWorkHours.
+func loadEmployeeSchedules(client odoo.API, employees []odoo.Employee, cal *WorkCalendar) error {
+	cfg, err := loadWorkHoursConfig()
+	if err != nil {
+		return err
+	}
+
+	fallback := defaultSchedule()
+	if cfg.Default != nil {
+		fallback = cfg.Default.apply(fallback)
+	}
+	cal.fallback = fallback
+
+	var calendarIDs []int64
+	seen := make(map[int64]bool)
+	for _, emp := range employees {
+		if emp.Calendar.Valid() && !seen[emp.Calendar.ID] {
+			seen[emp.Calendar.ID] = true
+			calendarIDs = append(calendarIDs, emp.Calendar.ID)
+		}
+	}
+	odooCalendars, err := loadOdooSchedules(client, calendarIDs)
+	if err != nil {
+		return err
+	}
+
+	for _, emp := range employees {
+		override := cfg.For(emp)
+		if override != nil {
+			for date, hours := range override.Dates {
+				cal.SetHours(emp.ID, date, hours)
+			}
+		}
+		if c, ok := odooCalendars[emp.Calendar.ID]; ok {
+			cal.SetCalendar(emp.ID, c, override)
+			continue
+		}
+		schedule := fallback
+		if override != nil {
+			schedule = override.apply(schedule)
+		}
+		cal.SetSchedule(emp.ID, schedule)
+	}
+	return nil
+}
@@ -0,0 +1,152 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"odoo-efor-tracker/odoo"
+)
+
+func TestLoadOdooSchedules(t *testing.T) {
+	newFakeOdoo(t)
+	client, err := authenticateOdoo()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	calendars, err := loadOdooSchedules(client, []int64{1, 2, 3})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, tt := range []struct {
+		name     string
+		calendar int64
+		week     string
+		want     Schedule
+	}{
+		// Öğle arası sayılmaz; cuma kısa gün
+		{"Standart 40 Saat", 1, "2025-02-03", Schedule{0, 8, 8, 8, 8, 6, 0}},
+		{"Yarı Zamanlı", 2, "2025-02-03", Schedule{0, 4, 4, 4, 4, 4, 0}},
+		// İki haftalık takvim: başlık satırları sayılmaz, cuma satırı
+		// 2025-01-31'de biter, perşembe satırı 2025-02-10'da başlar
+		{"İki Haftalık 0. hafta", 3, "2025-01-20", Schedule{0, 8, 8, 0, 0, 4, 0}},
+		{"İki Haftalık 1. hafta", 3, "2025-01-27", Schedule{0, 0, 0, 8, 0, 0, 0}},
+		{"İki Haftalık 0. hafta, cuma bitti", 3, "2025-02-05", Schedule{0, 8, 8, 0, 0, 0, 0}},
+		{"İki Haftalık 1. hafta, perşembe başladı", 3, "2025-02-16", Schedule{0, 0, 0, 8, 4, 0, 0}},
+	} {
+		day, _ := time.Parse(dateLayout, tt.week)
+		if got := calendars[tt.calendar].Week(day); got != tt.want {
+			t.Errorf("%s = %v, beklenen %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestLoadEmployeeSchedules(t *testing.T) {
+	newFakeOdoo(t)
+	client, err := authenticateOdoo()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	path := filepath.Join(t.TempDir(), "work_hours.yaml")
+	err = os.WriteFile(path, []byte(`
+default:
+  weekdays: 7.5
+employees:
+  - employee: Ayşegül Şahin
+    friday: 8
+    dates:
+      "2025-02-04": 4
+  - employee: "4"
+    saturday: 2
+`), 0o644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("WORK_HOURS_FILE", path)
+
+	employees := []odoo.Employee{
+		{ID: 2, Name: "Ayşegül Şahin", Calendar: odoo.Many2One{ID: 1, Name: "Standart 40 Saat"}},
+		{ID: 4, Name: "Harici Danışman", Calendar: odoo.Many2One{ID: 2, Name: "Yarı Zamanlı"}},
+		{ID: 5, Name: "Vardiyalı", Calendar: odoo.Many2One{ID: 3, Name: "İki Haftalık"}},
+		{ID: 9, Name: "Takvimsiz"},
+	}
+	cal := newWorkCalendar()
+	if err := loadEmployeeSchedules(client, employees, cal); err != nil {
+		t.Fatal(err)
+	}
+	cal.AddLeave(2, "2025-02-05", 0.5)
+
+	for _, tt := range []struct {
+		employee int64
+		date     string
+		want     float64
+	}{
+		{2, "2025-02-03", 8},   // Odoo takvimi
+		{2, "2025-02-04", 4},   // tarih istisnası
+		{2, "2025-02-05", 4},   // yarım gün izin
+		{2, "2025-02-07", 8},   // dosyada cuma geçersiz kılındı
+		{4, "2025-02-07", 4},   // yarı zamanlı
+		{4, "2025-02-08", 2},   // ID ile eşleşen tanım
+		{5, "2025-02-03", 8},   // iki haftalık takvimin 0. haftası
+		{5, "2025-02-10", 0},   // 1. hafta pazartesi boş
+		{5, "2025-02-12", 8},   // 1. hafta çarşamba
+		{9, "2025-02-03", 7.5}, // dosyadaki varsayılan
+		{9, "2025-02-08", 0},
+	} {
+		day, _ := time.Parse(dateLayout, tt.date)
+		if got := cal.Expected(tt.employee, day); got != tt.want {
+			t.Errorf("Expected(%d, %s) = %.2f, beklenen %.2f", tt.employee, tt.date, got, tt.want)
+		}
+	}
+}
+
+func TestLoadWorkHoursConfigRejectsBadDate(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "work_hours.yaml")
+	err := os.WriteFile(path, []byte("employees:\n  - employee: Ali\n    dates:\n      \"05.02.2025\": 4\n"), 0o644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("WORK_HOURS_FILE", path)
+
+	if _, err := loadWorkHoursConfig(); err == nil {
+		t.Error("geçersiz tarih için hata bekleniyordu")
+	}
+}
+
+func TestGenerateReportPartTimeStatus(t *testing.T) {
+	newFakeOdoo(t)
+	chdirTemp(t)
+
+	period, err := parsePeriod("2025-W06", time.Now())
+	if err != nil {
+		t.Fatal(err)
+	}
+	report, _, err := generateReport(period, "", "qa", FormatText, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Yarı zamanlı çalışan için 4 saat tam gündür
+	for _, d := range report.Days[0].Employees {
+		if d.Employee == "Harici Danışman" && (d.Expected != 4 || d.Status == StatusShort) {
+			t.Errorf("2025-02-03 durumu = %+v", d)
+		}
+	}
+	var dates []string
+	for _, m := range report.Missing {
+		if m.Employee != "Harici Danışman" {
+			continue
+		}
+		dates = append(dates, m.Date)
+		if m.Expected != 4 {
+			t.Errorf("%s beklenen saat = %.2f", m.Date, m.Expected)
+		}
+	}
+	if want := []string{"2025-02-04", "2025-02-05", "2025-02-06"}; !reflect.DeepEqual(dates, want) {
+		t.Errorf("eksik günler = %v, beklenen %v", dates, want)
+	}
+}